@@ -5,16 +5,30 @@ import (
 	"fmt"
 	"log"
 	stdhttp "net/http"
+	"os"
 	"os/signal"
+	"path/filepath"
+	"strings"
 	"syscall"
 	"time"
+	// Time zone preferences are validated against the embedded database, so they do not
+	// depend on the zoneinfo files of the host.
+	_ "time/tzdata"
+
+	// The SQLite adapter only uses database/sql; this links the driver it opens.
+	_ "modernc.org/sqlite"
 
 	authMiddleware "backend/internal/middleware"
 
 	"backend/internal/adapters/alphavantage"
+	"backend/internal/adapters/mail"
+	"backend/internal/adapters/oidc"
 	"backend/internal/adapters/postgres"
+	"backend/internal/adapters/sqlite"
 	"backend/internal/application"
+	"backend/internal/auth"
 	"backend/internal/config"
+	"backend/internal/domain/model"
 	http "backend/internal/handler"
 
 	"github.com/go-chi/chi/v5"
@@ -28,25 +42,77 @@ func main() {
 		log.Fatal("config: ", err)
 	}
 
-	db, err := postgres.NewPostgresDB(cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.SSLMode)
-	if err != nil {
-		log.Fatal("cannot connect to database: ", err)
-	}
-	defer db.Close()
+	var repos repositories
+	switch {
+	case cfg.Demo:
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			log.Fatal("migrate: there is no database in demo mode")
+		}
+		log.Printf("demo mode: data is kept in memory and lost on exit")
+		repos = demoRepositories()
 
-	userRepo := postgres.NewUserRepository(db)
-	if err := userRepo.Migrate(); err != nil {
-		log.Fatal("cannot run user migration: ", err)
-	}
+	case cfg.DB.Driver == config.DriverSQLite:
+		db, err := sqlite.Open(cfg.DB.Path)
+		if err != nil {
+			log.Fatal("cannot open database: ", err)
+		}
+		defer db.Close()
+
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			log.Fatal("migrate: SQLite databases are migrated when the server starts")
+		}
+
+		// A SQLite file has a single server process, so it is migrated on start.
+		migrator, err := sqlite.NewMigrator(db)
+		if err != nil {
+			log.Fatal("cannot load migrations: ", err)
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal("cannot migrate database: ", err)
+		}
+		for _, m := range applied {
+			log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		}
+		repos = sqliteRepositories(db)
+
+	default:
+		db, err := postgres.NewPostgresDB(cfg.DB.Host, cfg.DB.Port, cfg.DB.User, cfg.DB.Password, cfg.DB.Name, cfg.DB.SSLMode)
+		if err != nil {
+			log.Fatal("cannot connect to database: ", err)
+		}
+		defer db.Close()
+
+		if len(os.Args) > 1 && os.Args[1] == "migrate" {
+			if err := runMigrate(db, os.Args[2:]); err != nil {
+				log.Fatal("migrate: ", err)
+			}
+			return
+		}
 
-	commodityRepo := postgres.NewCommodityRepository(db)
-	if err := commodityRepo.Migrate(); err != nil {
-		log.Fatal("cannot run commodity migration: ", err)
+		// The schema is migrated by the migrate subcommand, not on boot, so several instances
+		// never race to change it.
+		migrator, err := postgres.NewMigrator(db)
+		if err != nil {
+			log.Fatal("cannot load migrations: ", err)
+		}
+		pending, err := migrator.Pending(context.Background())
+		if err != nil {
+			log.Fatal("cannot check migrations: ", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("database schema is %d migration(s) behind; run \"%s migrate up\" first", len(pending), os.Args[0])
+		}
+		repos = postgresRepositories(db)
 	}
 
-	correlationRepo := postgres.NewCorrelationRepository(db)
-	if err := correlationRepo.Migrate(); err != nil {
-		log.Fatal("cannot run correlation migration: ", err)
+	userRepo := repos.users
+	roleRepo := repos.roles
+	commodityRepo := repos.commodities
+	correlationRepo := repos.correlations
+
+	if err := roleRepo.SeedDefaults(context.Background()); err != nil {
+		log.Fatal("cannot seed roles: ", err)
 	}
 
 	// Graceful shutdown context
@@ -60,59 +126,266 @@ func main() {
 	httpClient := &stdhttp.Client{Timeout: 30 * time.Second}
 	alphaClient := alphavantage.NewClient(httpClient, cfg.Alpha.AlphaVantageKey, cfg.Alpha.GoldPricezKey)
 
-	userService := application.NewUserService(userRepo, cfg.JWT.SigningKey)
-	commodityService := application.NewCommodityService(alphaClient, commodityRepo)
-	correlationService := application.NewCorrelationService(correlationRepo, commodityRepo)
+	jwtKeys, err := auth.LoadKeySet(cfg.JWT.PrivateKeyFile, cfg.JWT.PublicKeyFiles, []byte(cfg.JWT.SigningKey))
+	if err != nil {
+		log.Fatal("cannot load JWT keys: ", err)
+	}
+
+	loginPolicy := application.DefaultLoginThrottlePolicy()
+	loginPolicy.MaxAccountFailures = cfg.Login.MaxAccountFailures
+	loginPolicy.MaxIPFailures = cfg.Login.MaxIPFailures
+	loginPolicy.LockoutDuration = cfg.Login.LockoutDuration
+
+	// Emails carry sign-in capable links, so they are only kept on disk when asked to; a
+	// server without SMTP or an outbox runs without account emails.
+	var mailer application.Mailer
+	outboxDir := cfg.Mail.OutboxDir
+	if outboxDir == "" && cfg.Demo {
+		outboxDir = filepath.Join(os.TempDir(), "primetrading-mail")
+	}
+	switch {
+	case cfg.Mail.SMTPHost != "":
+		mailer = mail.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUsername, cfg.Mail.SMTPPassword, cfg.Mail.From)
+	case outboxDir != "":
+		outbox, err := mail.NewOutbox(outboxDir, cfg.Mail.From)
+		if err != nil {
+			log.Fatal("cannot create mail outbox: ", err)
+		}
+		log.Printf("Account emails are written to %s instead of being sent", outboxDir)
+		mailer = outbox
+	default:
+		log.Printf("WARNING: account emails are disabled: set SMTP_HOST, or MAIL_OUTBOX_DIR for development")
+	}
+
+	var identityProviders []application.IdentityProvider
+	for _, p := range cfg.OIDC.Providers {
+		identityProviders = append(identityProviders, oidc.NewProvider(httpClient, oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  strings.TrimRight(cfg.OIDC.PublicURL, "/") + "/api/oauth/" + p.Name + "/callback",
+			Scopes:       p.Scopes,
+		}))
+	}
+
+	auditLog := application.NewAuditLog(repos.audit, cfg.Audit.Retention)
+	userOptions := []application.UserServiceOption{
+		application.WithRoles(roleRepo),
+		application.WithRefreshTokenTTL(cfg.JWT.RefreshTTL),
+		application.WithAccountDeletionGrace(cfg.Accounts.DeletionGrace),
+		application.WithAuditLog(auditLog),
+	}
+	if repos.sessions != nil {
+		userOptions = append(userOptions, application.WithSessionRepository(repos.sessions))
+	}
+	if repos.userTokens != nil && mailer != nil {
+		userOptions = append(userOptions,
+			application.WithAccountEmails(repos.userTokens, mailer, cfg.Mail.AppBaseURL),
+			application.WithEmailVerificationRequired(cfg.Mail.RequireVerifiedEmail),
+		)
+	}
+	if repos.loginAttempts != nil {
+		userOptions = append(userOptions, application.WithLoginAttempts(repos.loginAttempts, loginPolicy))
+	} else {
+		log.Printf("WARNING: login throttling is disabled: the storage has no login attempts")
+	}
+	if repos.twoFactor != nil {
+		userOptions = append(userOptions, application.WithTwoFactor(repos.twoFactor, cfg.JWT.TOTPIssuer))
+	} else {
+		log.Printf("WARNING: two-factor authentication is disabled: it needs Postgres")
+	}
+	if repos.apiKeys != nil {
+		userOptions = append(userOptions, application.WithAPIKeys(repos.apiKeys))
+	} else {
+		log.Printf("WARNING: API keys are disabled: they need Postgres")
+	}
+	if repos.identities != nil {
+		userOptions = append(userOptions, application.WithIdentityProviders(repos.identities, identityProviders...))
+	} else if len(identityProviders) > 0 {
+		log.Printf("WARNING: external sign-in is disabled: it needs Postgres")
+	}
+	if repos.preferences != nil {
+		userOptions = append(userOptions, application.WithPreferences(repos.preferences))
+	} else {
+		log.Printf("WARNING: user preferences are disabled: they need Postgres")
+	}
+	userService := application.NewUserService(userRepo, jwtKeys, userOptions...)
+	roleService := application.NewRoleService(roleRepo, userRepo)
+	fxService := application.NewFXService(alphaClient, repos.fxRates)
+	priceValidator := application.NewPriceValidator(cfg.Prices.MaxJumpPct, cfg.Prices.MaxZScore)
+	commodityOptions := []application.CommodityServiceOption{application.WithLatestPriceTTL(cfg.Cache.LatestPriceTTL)}
+	if cfg.Cache.LatestPriceSource == model.PriceSourceStorage {
+		commodityOptions = append(commodityOptions, application.WithStoredLatestPrices(cfg.Cache.LatestPriceMaxAge))
+	}
+	commodityService := application.NewCommodityService(alphaClient, commodityRepo, repos.quarantine, priceValidator, fxService, commodityOptions...)
+	correlationService := application.NewCorrelationService(correlationRepo, commodityRepo,
+		application.WithLatestCorrelationTTL(cfg.Cache.LatestCorrelationTTL))
+	forecastService := application.NewForecastService(commodityRepo, fxService)
+	partitionService := application.NewPartitionService(repos.partitions, application.RetentionPolicy{
+		RawPrices:       cfg.Storage.PriceRetention,
+		Correlations:    cfg.Storage.CorrelationRetention,
+		PartitionsAhead: cfg.Storage.PartitionsAhead,
+	})
 
 	// Handlers
 	userHandler := http.NewUserHandler(userService, cfg.Server.CookieSecure)
-	commodityHandler := http.NewCommodityHandler(commodityService)
-	correlationHandler := http.NewCorrelationHandler(correlationService)
+	commodityHandler := http.NewCommodityHandler(commodityService, userService)
+	correlationHandler := http.NewCorrelationHandler(correlationService, auditLog)
+	forecastHandler := http.NewForecastHandler(forecastService, userService)
+	quarantineHandler := http.NewQuarantineHandler(commodityService, auditLog)
+	jwksHandler := http.NewJWKSHandler(jwtKeys)
+	roleHandler := http.NewRoleHandler(roleService, auditLog)
+	auditHandler := http.NewAuditHandler(auditLog)
+	storageHandler := http.NewStorageHandler(partitionService)
+	cacheHandler := http.NewCacheHandler(commodityService, correlationService)
+	oidcHandler := http.NewOIDCHandler(userService, cfg.Server.CookieSecure, cfg.Mail.AppBaseURL)
 
 	// Router
 	r := chi.NewRouter()
 
 	c := cors.New(cors.Options{
 		AllowedOrigins:   cfg.Server.CORSOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", authMiddleware.APIKeyHeader},
 		AllowCredentials: true,
 		MaxAge:           300,
 	})
 	r.Use(c.Handler)
+	r.Use(authMiddleware.NewRealIPMiddleware(cfg.Server.TrustedProxies))
 	r.Use(middleware.Logger)
+	r.Use(http.RequestInfoMiddleware)
 	r.Use(authMiddleware.CSRFMiddleware)
 
 	r.Get("/health", func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 		w.Write([]byte("OK"))
 	})
+	r.Get("/.well-known/jwks.json", jwksHandler.GetJWKSHandler)
+
+	jwtAuth := authMiddleware.NewJWTAuthMiddleware(jwtKeys,
+		authMiddleware.WithSessionChecker(userService),
+		authMiddleware.WithUserStatusChecker(userService),
+		authMiddleware.WithAPIKeys(userService),
+	)
 
 	r.Route("/api", func(r chi.Router) {
 		// Public routes
 		r.Post("/register", userHandler.RegisterUserHandler)
 		r.Post("/login", userHandler.LoginUserHandler)
+		r.Post("/login/2fa", userHandler.TwoFactorLoginHandler)
 		r.Post("/logout", userHandler.LogoutUserHandler)
 		r.Post("/refresh", userHandler.RefreshJWTokenHandler)
+		r.Post("/verify-email", userHandler.VerifyEmailHandler)
+		r.Post("/password/forgot", userHandler.ForgotPasswordHandler)
+		r.Post("/password/reset", userHandler.ResetPasswordHandler)
+		r.Post("/me/email/confirm", userHandler.ConfirmEmailChangeHandler)
+		r.Get("/oauth/providers", oidcHandler.ListProvidersHandler)
+		r.Get("/oauth/{provider}/login", oidcHandler.LoginHandler)
+		r.Get("/oauth/{provider}/callback", oidcHandler.CallbackHandler)
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
-			r.Use(authMiddleware.NewJWTAuthMiddleware(cfg.JWT.SigningKey))
+			r.Use(jwtAuth)
 			r.Get("/me", userHandler.MeHandler)
-			r.Post("/user/change-password", userHandler.ChangePasswordHandler)
-			r.Get("/commodity", commodityHandler.GetCommodityHandler)
-			r.Get("/commodity/{name}/history", commodityHandler.GetCommodityHistoryHandler)
-			r.Get("/commodity/status", commodityHandler.GetCommodityStatusHandler)
-			r.Get("/correlation", correlationHandler.GetCorrelationHandler)
-			r.Get("/correlation/history", correlationHandler.GetCorrelationHistoryHandler)
+			r.Get("/me/preferences", userHandler.GetPreferencesHandler)
+
+			// Account management needs a real login
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RejectAPIKeys)
+				r.Post("/user/change-password", userHandler.ChangePasswordHandler)
+				r.Patch("/me/preferences", userHandler.UpdatePreferencesHandler)
+				r.Post("/me/email", userHandler.ChangeEmailHandler)
+				r.Post("/me/username", userHandler.ChangeUsernameHandler)
+				r.Get("/me/export", userHandler.ExportAccountHandler)
+				r.Delete("/me", userHandler.DeleteAccountHandler)
+				r.Post("/me/deletion/cancel", userHandler.CancelAccountDeletionHandler)
+				r.Post("/verify-email/resend", userHandler.ResendVerificationHandler)
+				r.Get("/user/sessions", userHandler.ListSessionsHandler)
+				r.Delete("/user/sessions", userHandler.RevokeOtherSessionsHandler)
+				r.Delete("/user/sessions/{id}", userHandler.RevokeSessionHandler)
+				r.Post("/user/2fa/enroll", userHandler.EnrollTwoFactorHandler)
+				r.Post("/user/2fa/verify", userHandler.VerifyTwoFactorHandler)
+				r.Get("/user/api-keys", userHandler.ListAPIKeysHandler)
+				r.Post("/user/api-keys", userHandler.CreateAPIKeyHandler)
+				r.Delete("/user/api-keys/{id}", userHandler.RevokeAPIKeyHandler)
+				r.Get("/user/identities", oidcHandler.ListIdentitiesHandler)
+				r.Post("/user/identities/{provider}", oidcHandler.LinkIdentityHandler)
+				r.Post("/user/reauthenticate/{provider}", oidcHandler.ReauthenticateHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermCommodityRead))
+				r.Get("/commodity", commodityHandler.GetCommodityHandler)
+				r.Get("/commodity/{name}/history", commodityHandler.GetCommodityHistoryHandler)
+				r.Get("/commodity/{name}/forecast", forecastHandler.GetForecastHandler)
+				r.Get("/commodity/status", commodityHandler.GetCommodityStatusHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermCorrelationRead))
+				r.Get("/correlation", correlationHandler.GetCorrelationHandler)
+				r.Get("/correlation/history", correlationHandler.GetCorrelationHistoryHandler)
+				r.Get("/correlation/events", correlationHandler.GetCorrelationEventsHandler)
+			})
 		})
 
 		// Admin routes
 		r.Group(func(r chi.Router) {
-			r.Use(authMiddleware.NewJWTAuthMiddleware(cfg.JWT.SigningKey))
-			r.Use(authMiddleware.AdminRoleMiddleware)
+			r.Use(jwtAuth)
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermQuarantineReview))
+				r.Get("/admin/quarantine", quarantineHandler.ListQuarantineHandler)
+				r.Post("/admin/quarantine/{id}/approve", quarantineHandler.ApproveQuarantineHandler)
+				r.Post("/admin/quarantine/{id}/reject", quarantineHandler.RejectQuarantineHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermCorrelationRecompute))
+				r.Post("/admin/correlation/recompute", correlationHandler.RecomputeCorrelationHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermUsersManage))
+				r.Get("/admin/users", userHandler.ListUsersHandler)
+				r.Get("/admin/users/{id}", userHandler.GetUserHandler)
+				r.Put("/admin/users/{id}/role", userHandler.UpdateUserRoleHandler)
+				r.Post("/admin/users/{id}/disable", userHandler.DisableUserHandler)
+				r.Post("/admin/users/{id}/enable", userHandler.EnableUserHandler)
+				r.Post("/admin/users/{id}/force-password-reset", userHandler.ForcePasswordResetHandler)
+				r.Delete("/admin/users/{id}", userHandler.DeleteUserHandler)
+				r.Post("/admin/users/{id}/2fa/reset", userHandler.ResetTwoFactorHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermRolesManage))
+				r.Get("/admin/permissions", roleHandler.ListPermissionsHandler)
+				r.Get("/admin/roles", roleHandler.ListRolesHandler)
+				r.Put("/admin/roles/{name}", roleHandler.SaveRoleHandler)
+				r.Delete("/admin/roles/{name}", roleHandler.DeleteRoleHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermAuditRead))
+				r.Get("/admin/audit", auditHandler.ListAuditEventsHandler)
+			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(authMiddleware.RequirePermission(model.PermStorageRead))
+				r.Get("/admin/partitions", storageHandler.ListPartitionsHandler)
+				r.Get("/admin/cache", cacheHandler.GetCacheStatsHandler)
+			})
 		})
 	})
 
+	correlationPairs := [][]string{
+		{"gold", "silver"},
+		{"copper", "aluminum"},
+		{"gold", "copper"},
+		{"gold", "brent"},
+		{"copper", "brent"},
+	}
+
 	// Background commodity refresh
 	runUpdateCycle := func() {
 		log.Printf("Starting scheduled commodity refresh")
@@ -125,14 +398,11 @@ func main() {
 			log.Printf("Error updating industrial commodities: %v", err)
 		}
 
-		pairs := [][]string{
-			{"gold", "silver"},
-			{"copper", "aluminum"},
-			{"gold", "copper"},
-			{"gold", "brent"},
-			{"copper", "brent"},
+		if err := fxService.UpdateRates(ctx); err != nil {
+			log.Printf("Error updating exchange rates: %v", err)
 		}
-		for _, p := range pairs {
+
+		for _, p := range correlationPairs {
 			if err := correlationService.UpdateCorrelations(ctx, p[0], p[1]); err != nil {
 				log.Printf("Error updating correlation %s-%s: %v", p[0], p[1], err)
 			}
@@ -156,6 +426,58 @@ func main() {
 		}
 	}()
 
+	// Daily jobs: regime detection on daily closes, then cleanup of upcoming partitions,
+	// history retention, audit retention and accounts past their deletion grace period
+	runDailyJobs := func() {
+		for _, p := range correlationPairs {
+			if _, err := correlationService.DetectRegimeChanges(ctx, p[0], p[1]); err != nil {
+				log.Printf("Error detecting regime changes %s-%s: %v", p[0], p[1], err)
+			}
+		}
+
+		report, err := partitionService.Maintain(ctx)
+		if err != nil {
+			log.Printf("Error maintaining partitions: %v", err)
+		}
+		if len(report.Created) > 0 {
+			log.Printf("Created partitions %v", report.Created)
+		}
+		if len(report.Downsampled) > 0 {
+			log.Printf("Downsampled prices older than %s to daily rows, dropped partitions %v", cfg.Storage.PriceRetention, report.Downsampled)
+		}
+		if len(report.Dropped) > 0 {
+			log.Printf("Dropped correlation partitions older than %s: %v", cfg.Storage.CorrelationRetention, report.Dropped)
+		}
+
+		n, err := auditLog.PurgeExpired(ctx)
+		if err != nil {
+			log.Printf("Error purging audit events: %v", err)
+		} else if n > 0 {
+			log.Printf("Purged %d audit events older than %s", n, cfg.Audit.Retention)
+		}
+
+		erased, err := userService.PurgeDeletedAccounts(ctx)
+		if err != nil {
+			log.Printf("Error erasing deleted accounts: %v", err)
+		} else if erased > 0 {
+			log.Printf("Erased %d deleted accounts", erased)
+		}
+	}
+	runDailyJobs()
+
+	dailyTicker := time.NewTicker(24 * time.Hour)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				dailyTicker.Stop()
+				return
+			case <-dailyTicker.C:
+				runDailyJobs()
+			}
+		}
+	}()
+
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.Server.Host, cfg.Server.Port)
 	srv := &stdhttp.Server{
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"database/sql"
+
+	"backend/internal/adapters/memory"
+	"backend/internal/adapters/postgres"
+	"backend/internal/adapters/sqlite"
+	"backend/internal/domain/repository"
+)
+
+// repositories is the storage the server runs on. Sessions and emailed tokens are kept in
+// memory when the storage has no tables for them. Two-factor, API keys, external identities
+// and preferences only exist on Postgres; elsewhere they are nil and the server runs without
+// those features.
+type repositories struct {
+	users        repository.UserRepository
+	roles        repository.RoleRepository
+	audit        repository.AuditRepository
+	commodities  repository.CommodityRepository
+	quarantine   repository.QuarantineRepository
+	fxRates      repository.FXRateRepository
+	correlations repository.CorrelationRepository
+	partitions   repository.PartitionRepository
+
+	sessions      repository.SessionRepository
+	twoFactor     repository.TwoFactorRepository
+	loginAttempts repository.LoginAttemptRepository
+	userTokens    repository.UserTokenRepository
+	apiKeys       repository.APIKeyRepository
+	identities    repository.IdentityRepository
+	preferences   repository.PreferencesRepository
+}
+
+func postgresRepositories(db *sql.DB) repositories {
+	return repositories{
+		users:        postgres.NewUserRepository(db),
+		roles:        postgres.NewRoleRepository(db),
+		audit:        postgres.NewAuditRepository(db),
+		commodities:  postgres.NewCommodityRepository(db),
+		quarantine:   postgres.NewQuarantineRepository(db),
+		fxRates:      postgres.NewFXRateRepository(db),
+		correlations: postgres.NewCorrelationRepository(db),
+		partitions:   postgres.NewPartitionRepository(db),
+
+		sessions:      postgres.NewSessionRepository(db),
+		twoFactor:     postgres.NewTwoFactorRepository(db),
+		loginAttempts: postgres.NewLoginAttemptRepository(db),
+		userTokens:    postgres.NewUserTokenRepository(db),
+		apiKeys:       postgres.NewAPIKeyRepository(db),
+		identities:    postgres.NewIdentityRepository(db),
+		preferences:   postgres.NewPreferencesRepository(db),
+	}
+}
+
+// sqliteRepositories keeps users, prices and correlations in SQLite. Roles, the audit log,
+// quarantined prices, exchange rates, sessions and emailed tokens stay in memory and start
+// over on every restart.
+func sqliteRepositories(db *sql.DB) repositories {
+	return repositories{
+		users:        sqlite.NewUserRepository(db),
+		roles:        memory.NewRoleRepository(),
+		audit:        memory.NewAuditRepository(),
+		commodities:  sqlite.NewCommodityRepository(db),
+		quarantine:   memory.NewQuarantineRepository(),
+		fxRates:      memory.NewFXRateRepository(),
+		correlations: sqlite.NewCorrelationRepository(db),
+		partitions:   memory.NewPartitionRepository(),
+
+		sessions:      memory.NewSessionRepository(),
+		userTokens:    memory.NewUserTokenRepository(),
+		loginAttempts: sqlite.NewLoginAttemptRepository(db),
+	}
+}
+
+func demoRepositories() repositories {
+	return repositories{
+		users:        memory.NewUserRepository(),
+		roles:        memory.NewRoleRepository(),
+		audit:        memory.NewAuditRepository(),
+		commodities:  memory.NewCommodityRepository(),
+		quarantine:   memory.NewQuarantineRepository(),
+		fxRates:      memory.NewFXRateRepository(),
+		correlations: memory.NewCorrelationRepository(),
+		partitions:   memory.NewPartitionRepository(),
+
+		sessions:      memory.NewSessionRepository(),
+		userTokens:    memory.NewUserTokenRepository(),
+		loginAttempts: memory.NewLoginAttemptRepository(),
+	}
+}
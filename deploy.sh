@@ -18,9 +18,16 @@ echo "🏗️  Removing existing containers and rebuilding..."
 # by ensuring we start with a clean state.
 docker-compose down || true
 docker rm -f go_app postgres_db || true
-docker-compose up -d --build
+docker-compose build
 
-# 4. Cleanup
+# 4. Migrate the schema before the new version starts; the server refuses to boot on a
+# database with pending migrations.
+echo "🗄️  Applying database migrations..."
+docker-compose run --rm app migrate up
+
+docker-compose up -d
+
+# 5. Cleanup
 echo "🧹 Cleaning up old images..."
 docker image prune -f
 
@@ -2,12 +2,14 @@ package alphavantage
 
 import (
 	"backend/internal/domain/model"
+	"backend/internal/domain/units"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"sync"
@@ -18,11 +20,6 @@ const (
 	baseURL = "https://www.alphavantage.co/query"
 	goldPricezURL = "https://goldpricez.com/api/rates/currency/usd/measure/ounce/metal/all"
 	alphaVantageMinInterval = 1200 * time.Millisecond
-	
-	// Conversion factors to KG
-	troyOunceToKg = 0.0311035
-	metricTonToKg = 1000.0
-	barrelToKg    = 136.0 // Approximate for Brent Oil
 )
 
 type Client struct {
@@ -45,6 +42,7 @@ func NewClient(httpClient *http.Client, alphaVantageKey, goldPricezKey string) *
 type AlphaVantageExchangeRate struct {
 	RealtimeExchangeRate struct {
 		FromCurrencyCode string `json:"1. From_Currency Code"`
+		ToCurrencyCode   string `json:"3. To_Currency Code"`
 		ExchangeRate     string `json:"5. Exchange Rate"`
 		LastRefreshed    string `json:"6. Last Refreshed"`
 	} `json:"Realtime Currency Exchange Rate"`
@@ -138,7 +136,10 @@ func (c *Client) fetchPreciousMetal(ctx context.Context, metal string) (*model.C
 		return nil, fmt.Errorf("invalid %s price '%s': %w", metal, priceOunceRaw, err)
 	}
 
-	priceKg := priceOunce / troyOunceToKg
+	priceKg, err := units.ToPerKg(priceOunce, metal, units.TroyOunce)
+	if err != nil {
+		return nil, err
+	}
 
 	date := time.Now()
 	if data.GMTUpdated != "" {
@@ -148,11 +149,13 @@ func (c *Client) fetchPreciousMetal(ctx context.Context, metal string) (*model.C
 	}
 
 	return &model.Commodity{
-		Name:      metal,
-		Date:      date,
-		PriceKg:   priceKg,
-		Unit:      "USD/kg",
-		FetchedAt: time.Now(),
+		Name:        metal,
+		Date:        date,
+		PriceKg:     priceKg,
+		Unit:        "USD/kg",
+		FetchedAt:   time.Now(),
+		NativePrice: priceOunce,
+		NativeUnit:  string(units.TroyOunce),
 	}, nil
 }
 
@@ -198,26 +201,94 @@ func (c *Client) fetchIndustrialCommodity(ctx context.Context, commodity string)
 
 	// Take the latest value
 	latest := data.Data[0]
-	price, _ := strconv.ParseFloat(latest.Value, 64)
-	
-	var priceKg float64
-	switch function {
-	case "COPPER", "ALUMINUM":
-		// These are in dollars per metric ton
-		priceKg = price / metricTonToKg
-	case "BRENT":
-		// Brent is in dollars per barrel
-		priceKg = price / barrelToKg
+	price, err := strconv.ParseFloat(latest.Value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s price '%s': %w", commodity, latest.Value, err)
 	}
 
-	date, _ := time.Parse("2006-01-02", latest.Date)
+	// Copper and aluminum are quoted in dollars per metric ton, Brent in dollars per barrel
+	nativeUnit := units.Native(strings.ToLower(function))
+	priceKg, err := units.ToPerKg(price, strings.ToLower(function), nativeUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	date, err := time.Parse("2006-01-02", latest.Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s date '%s': %w", commodity, latest.Date, err)
+	}
 
 	return &model.Commodity{
-		Name:      commodity,
-		Date:      date,
-		PriceKg:   priceKg,
-		Unit:      "USD/kg",
-		FetchedAt: time.Now(),
+		Name:        commodity,
+		Date:        date,
+		PriceKg:     priceKg,
+		Unit:        "USD/kg",
+		FetchedAt:   time.Now(),
+		NativePrice: price,
+		NativeUnit:  string(nativeUnit),
+	}, nil
+}
+
+// FetchExchangeRate returns the current rate from one currency to another using
+// AlphaVantage CURRENCY_EXCHANGE_RATE. The rate is dated on the day it was last refreshed.
+func (c *Client) FetchExchangeRate(ctx context.Context, from, to string) (*model.FXRate, error) {
+	if c.alphaVantageAPIKey == "" {
+		return nil, errors.New("missing ALPHA_VANTAGE_API_KEY")
+	}
+
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	c.waitForAlphaVantageSlot()
+
+	reqURL := fmt.Sprintf("%s?function=CURRENCY_EXCHANGE_RATE&from_currency=%s&to_currency=%s&apikey=%s",
+		baseURL, url.QueryEscape(from), url.QueryEscape(to), c.alphaVantageAPIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := parseAlphaVantageError(body); err != nil {
+		return nil, fmt.Errorf("alphavantage %s/%s exchange rate request failed: %w", from, to, err)
+	}
+
+	var data AlphaVantageExchangeRate
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, err
+	}
+
+	rateRaw := data.RealtimeExchangeRate.ExchangeRate
+	if rateRaw == "" {
+		return nil, fmt.Errorf("alphavantage returned no %s/%s exchange rate", from, to)
+	}
+	rate, err := strconv.ParseFloat(rateRaw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s/%s exchange rate '%s': %w", from, to, rateRaw, err)
+	}
+
+	now := time.Now().UTC()
+	date := now
+	if parsed, err := time.Parse("2006-01-02 15:04:05", data.RealtimeExchangeRate.LastRefreshed); err == nil {
+		date = parsed
+	}
+
+	return &model.FXRate{
+		Base:      from,
+		Quote:     to,
+		Date:      time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		Rate:      rate,
+		FetchedAt: now,
 	}, nil
 }
 
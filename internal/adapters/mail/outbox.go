@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"backend/internal/application"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Outbox writes emails to dir as .eml files instead of delivering them, for local
+// development and demo mode. The bodies carry sign-in capable links and never go to the log.
+type Outbox struct {
+	dir  string
+	from string
+	sent atomic.Int64
+}
+
+func NewOutbox(dir, from string) (*Outbox, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("outbox dir is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create outbox dir: %w", err)
+	}
+	return &Outbox{dir: dir, from: from}, nil
+}
+
+func (o *Outbox) Send(ctx context.Context, email application.Email) error {
+	n := o.sent.Add(1)
+	now := time.Now()
+	name := fmt.Sprintf("%s-%03d.eml", now.Format("20060102T150405"), n)
+	path := filepath.Join(o.dir, name)
+	if err := os.WriteFile(path, formatMessage(o.from, email, now), 0o600); err != nil {
+		return fmt.Errorf("write outbox email: %w", err)
+	}
+	log.Printf("Outbox: email to %s written to %s", email.To, path)
+	return nil
+}
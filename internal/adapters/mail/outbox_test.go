@@ -0,0 +1,58 @@
+package mail
+
+import (
+	"backend/internal/application"
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOutboxNeedsADirectory(t *testing.T) {
+	if _, err := NewOutbox("", "no-reply@localhost"); err == nil {
+		t.Fatal("expected an outbox without a directory to be rejected")
+	}
+}
+
+func TestOutboxNeverLogsTheBody(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	outbox, err := NewOutbox(t.TempDir(), "no-reply@localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	email := application.Email{To: "alice@example.com", Subject: "Reset", Body: "Open https://app.example/reset?token=secret"}
+	if err := outbox.Send(context.Background(), email); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "secret") {
+		t.Fatalf("the body must not be logged, got %q", buf.String())
+	}
+}
+
+func TestOutboxWritesEmailFiles(t *testing.T) {
+	dir := t.TempDir()
+	outbox, err := NewOutbox(dir, "no-reply@localhost")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := outbox.Send(context.Background(), application.Email{To: "alice@example.com", Subject: "Reset", Body: "link"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 .eml files, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if !strings.Contains(string(data), "Subject: Reset\r\n") {
+		t.Fatalf("unexpected message:\n%s", data)
+	}
+}
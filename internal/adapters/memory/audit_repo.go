@@ -0,0 +1,73 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type AuditRepository struct {
+	mu     sync.RWMutex
+	events []model.AuditEvent
+	nextID int64
+}
+
+func NewAuditRepository() repository.AuditRepository {
+	return &AuditRepository{}
+}
+
+func (m *AuditRepository) Record(ctx context.Context, e model.AuditEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	e.ID = m.nextID
+	m.events = append(m.events, e)
+	return nil
+}
+
+// Query returns matching events, newest first.
+func (m *AuditRepository) Query(ctx context.Context, f repository.AuditFilter) ([]model.AuditEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matched []model.AuditEvent
+	for _, e := range m.events {
+		switch {
+		case f.ActorID != nil && (e.ActorID == nil || *e.ActorID != *f.ActorID),
+			f.Action != "" && e.Action != f.Action,
+			f.TargetType != "" && e.TargetType != f.TargetType,
+			f.TargetID != "" && e.TargetID != f.TargetID,
+			f.Result != "" && e.Result != f.Result,
+			!f.Since.IsZero() && e.CreatedAt.Before(f.Since),
+			!f.Until.IsZero() && !e.CreatedAt.Before(f.Until):
+			continue
+		}
+		matched = append(matched, e)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if !matched[i].CreatedAt.Equal(matched[j].CreatedAt) {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		}
+		return matched[i].ID > matched[j].ID
+	})
+	return page(matched, f.Limit, f.Offset), nil
+}
+
+func (m *AuditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.events[:0]
+	for _, e := range m.events {
+		if !e.CreatedAt.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	removed := int64(len(m.events) - len(kept))
+	m.events = kept
+	return removed, nil
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+type commodityKey struct {
+	name string
+	date int64
+}
+
+type CommodityRepository struct {
+	mu     sync.RWMutex
+	prices map[commodityKey]model.Commodity
+	nextID int64
+}
+
+func NewCommodityRepository() repository.CommodityRepository {
+	return &CommodityRepository{prices: make(map[commodityKey]model.Commodity)}
+}
+
+// Save inserts the price or replaces the one stored for the same commodity and date,
+// keeping its id.
+func (m *CommodityRepository) Save(ctx context.Context, stock model.Commodity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.upsert(stock)
+	return nil
+}
+
+func (m *CommodityRepository) SaveBatch(ctx context.Context, stocks []model.Commodity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, c := range stocks {
+		m.upsert(c)
+	}
+	return nil
+}
+
+func (m *CommodityRepository) GetLatestPrice(ctx context.Context, commodity string) (model.Commodity, error) {
+	history, _ := m.GetPriceHistory(ctx, commodity, 1)
+	if len(history) == 0 {
+		return model.Commodity{}, sql.ErrNoRows
+	}
+	return history[0], nil
+}
+
+// GetPriceHistory returns the newest prices first.
+func (m *CommodityRepository) GetPriceHistory(ctx context.Context, commodity string, limit int) ([]model.Commodity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return page(m.newestFirst(commodity), limit, 0), nil
+}
+
+func (m *CommodityRepository) GetDailyCloses(ctx context.Context, commodity string, days int) ([]model.Commodity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var closes []model.Commodity
+	for _, c := range m.newestFirst(commodity) {
+		if n := len(closes); n > 0 && sameUTCDay(closes[n-1].Date, c.Date) {
+			continue
+		}
+		if len(closes) == days {
+			break
+		}
+		closes = append(closes, c)
+	}
+	return closes, nil
+}
+
+func (m *CommodityRepository) HasRecentData(ctx context.Context) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	since := now().Add(-48 * time.Hour)
+	for _, c := range m.prices {
+		if c.Date.After(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *CommodityRepository) newestFirst(commodity string) []model.Commodity {
+	var history []model.Commodity
+	for k, c := range m.prices {
+		if k.name == commodity {
+			history = append(history, c)
+		}
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Date.After(history[j].Date) })
+	return history
+}
+
+func sameUTCDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+func (m *CommodityRepository) upsert(c model.Commodity) {
+	key := commodityKey{name: c.Name, date: c.Date.UnixMicro()}
+	if existing, ok := m.prices[key]; ok {
+		c.ID = existing.ID
+	} else {
+		m.nextID++
+		c.ID = m.nextID
+	}
+	// Only the stored columns survive, as in the database.
+	c.Price = 0
+	m.prices[key] = c
+}
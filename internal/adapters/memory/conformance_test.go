@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"backend/internal/adapters/repotest"
+	"backend/internal/domain/repository"
+	"testing"
+)
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return NewUserRepository()
+	})
+}
+
+func TestCommodityRepositoryConformance(t *testing.T) {
+	repotest.RunCommodityRepositoryTests(t, func(t *testing.T) repository.CommodityRepository {
+		return NewCommodityRepository()
+	})
+}
+
+func TestCorrelationRepositoryConformance(t *testing.T) {
+	repotest.RunCorrelationRepositoryTests(t, func(t *testing.T) repository.CorrelationRepository {
+		return NewCorrelationRepository()
+	})
+}
+
+func TestSessionRepositoryConformance(t *testing.T) {
+	repotest.RunSessionRepositoryTests(t, func(t *testing.T) repository.SessionRepository {
+		return NewSessionRepository()
+	})
+}
+
+func TestUserTokenRepositoryConformance(t *testing.T) {
+	repotest.RunUserTokenRepositoryTests(t, func(t *testing.T) repository.UserTokenRepository {
+		return NewUserTokenRepository()
+	})
+}
+
+func TestLoginAttemptRepositoryConformance(t *testing.T) {
+	repotest.RunLoginAttemptRepositoryTests(t, func(t *testing.T) repository.LoginAttemptRepository {
+		return NewLoginAttemptRepository()
+	})
+}
@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+	"sync"
+)
+
+type CorrelationRepository struct {
+	mu           sync.RWMutex
+	correlations []model.Correlation
+	events       []model.CorrelationEvent
+	nextID       int64
+	nextEventID  int64
+}
+
+func NewCorrelationRepository() repository.CorrelationRepository {
+	return &CorrelationRepository{}
+}
+
+func (m *CorrelationRepository) Save(ctx context.Context, correlation *model.Correlation) error {
+	return m.SaveBatch(ctx, []*model.Correlation{correlation})
+}
+
+func (m *CorrelationRepository) SaveBatch(ctx context.Context, correlations []*model.Correlation) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, c := range correlations {
+		stored := *c
+		m.nextID++
+		stored.ID = m.nextID
+		stored.CreatedAt = now().UTC()
+		m.correlations = append(m.correlations, stored)
+	}
+	return nil
+}
+
+func (m *CorrelationRepository) GetLatest(ctx context.Context, commodityA, commodityB string) (*model.Correlation, error) {
+	history, _ := m.GetHistory(ctx, commodityA, commodityB, 1)
+	if len(history) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return history[0], nil
+}
+
+// GetHistory returns the pair's correlations, newest first. The pair order matters.
+func (m *CorrelationRepository) GetHistory(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.Correlation, error) {
+	matched := m.filter(func(c model.Correlation) bool {
+		return c.CommodityA == commodityA && c.CommodityB == commodityB
+	})
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].CorrelationDate.After(matched[j].CorrelationDate) })
+	return page(matched, limit, 0), nil
+}
+
+// GetTopCorrelated returns the correlations involving commodity, strongest first.
+func (m *CorrelationRepository) GetTopCorrelated(ctx context.Context, commodity string, limit int) ([]*model.Correlation, error) {
+	matched := m.filter(func(c model.Correlation) bool {
+		return c.CommodityA == commodity || c.CommodityB == commodity
+	})
+	sort.SliceStable(matched, func(i, j int) bool { return math.Abs(matched[i].PearsonR) > math.Abs(matched[j].PearsonR) })
+	return page(matched, limit, 0), nil
+}
+
+// SaveEvents skips events already stored for the same pair, start date and kind.
+func (m *CorrelationRepository) SaveEvents(ctx context.Context, events []*model.CorrelationEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	type eventKey struct {
+		a, b, kind string
+		start      int64
+	}
+	seen := make(map[eventKey]bool, len(m.events))
+	for _, e := range m.events {
+		seen[eventKey{e.CommodityA, e.CommodityB, e.Kind, e.StartDate.UnixMicro()}] = true
+	}
+	for _, e := range events {
+		key := eventKey{e.CommodityA, e.CommodityB, e.Kind, e.StartDate.UnixMicro()}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		stored := *e
+		m.nextEventID++
+		stored.ID = m.nextEventID
+		stored.CreatedAt = now().UTC()
+		m.events = append(m.events, stored)
+	}
+	return nil
+}
+
+// GetEvents returns the newest events first, for one pair in either order or, without
+// commodityA, for every pair.
+func (m *CorrelationRepository) GetEvents(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.CorrelationEvent, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matched []*model.CorrelationEvent
+	for _, e := range m.events {
+		if commodityA == "" ||
+			(e.CommodityA == commodityA && e.CommodityB == commodityB) ||
+			(e.CommodityA == commodityB && e.CommodityB == commodityA) {
+			e := e
+			matched = append(matched, &e)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].StartDate.After(matched[j].StartDate) })
+	return page(matched, limit, 0), nil
+}
+
+func (m *CorrelationRepository) filter(keep func(c model.Correlation) bool) []*model.Correlation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matched []*model.Correlation
+	for _, c := range m.correlations {
+		if keep(c) {
+			c := c
+			matched = append(matched, &c)
+		}
+	}
+	return matched
+}
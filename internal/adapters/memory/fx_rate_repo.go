@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+type FXRateRepository struct {
+	mu     sync.RWMutex
+	rates  []model.FXRate
+	nextID int64
+}
+
+func NewFXRateRepository() repository.FXRateRepository {
+	return &FXRateRepository{}
+}
+
+// Save inserts the rate or replaces the one stored for the same pair and day.
+func (m *FXRateRepository) Save(ctx context.Context, rate model.FXRate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, r := range m.rates {
+		if r.Base == rate.Base && r.Quote == rate.Quote && sameDay(r, rate) {
+			m.rates[i].Rate, m.rates[i].FetchedAt = rate.Rate, rate.FetchedAt
+			return nil
+		}
+	}
+	m.nextID++
+	rate.ID = m.nextID
+	m.rates = append(m.rates, rate)
+	return nil
+}
+
+func (m *FXRateRepository) GetLatest(ctx context.Context, base, quote string) (model.FXRate, error) {
+	rates, _ := m.ListRates(ctx, base, quote)
+	if len(rates) == 0 {
+		return model.FXRate{}, sql.ErrNoRows
+	}
+	return rates[len(rates)-1], nil
+}
+
+// ListRates returns every stored daily rate for the pair in chronological order.
+func (m *FXRateRepository) ListRates(ctx context.Context, base, quote string) ([]model.FXRate, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var rates []model.FXRate
+	for _, r := range m.rates {
+		if r.Base == base && r.Quote == quote {
+			rates = append(rates, r)
+		}
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].Date.Before(rates[j].Date) })
+	return rates, nil
+}
+
+// sameDay compares the DATE column the rates are keyed on.
+func sameDay(a, b model.FXRate) bool {
+	ay, am, ad := a.Date.Date()
+	by, bm, bd := b.Date.Date()
+	return ay == by && am == bm && ad == bd
+}
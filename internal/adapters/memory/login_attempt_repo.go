@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	mu       sync.RWMutex
+	attempts []model.LoginAttempt
+	nextID   int64
+}
+
+func NewLoginAttemptRepository() repository.LoginAttemptRepository {
+	return &LoginAttemptRepository{}
+}
+
+func (m *LoginAttemptRepository) Record(ctx context.Context, a model.LoginAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	a.ID = m.nextID
+	if a.UserID != nil {
+		id := *a.UserID
+		a.UserID = &id
+	}
+	m.attempts = append(m.attempts, a)
+	return nil
+}
+
+func (m *LoginAttemptRepository) AccountFailures(ctx context.Context, accountKey string, since time.Time) (repository.AttemptFailures, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, a := range m.attempts {
+		if a.AccountKey == accountKey && a.Outcome == model.LoginOutcomeSuccess && a.CreatedAt.After(since) {
+			since = a.CreatedAt
+		}
+	}
+	return m.failures(func(a model.LoginAttempt) bool { return a.AccountKey == accountKey }, since), nil
+}
+
+func (m *LoginAttemptRepository) IPFailures(ctx context.Context, ip string, since time.Time) (repository.AttemptFailures, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.failures(func(a model.LoginAttempt) bool { return a.IP == ip }, since), nil
+}
+
+func (m *LoginAttemptRepository) ListByUser(ctx context.Context, userID uint) ([]model.LoginAttempt, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var attempts []model.LoginAttempt
+	for _, a := range m.attempts {
+		if a.UserID != nil && *a.UserID == userID {
+			attempts = append(attempts, a)
+		}
+	}
+	sort.SliceStable(attempts, func(i, j int) bool {
+		if !attempts[i].CreatedAt.Equal(attempts[j].CreatedAt) {
+			return attempts[i].CreatedAt.After(attempts[j].CreatedAt)
+		}
+		return attempts[i].ID > attempts[j].ID
+	})
+	return attempts, nil
+}
+
+func (m *LoginAttemptRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	kept := m.attempts[:0]
+	for _, a := range m.attempts {
+		if a.UserID == nil || *a.UserID != userID {
+			kept = append(kept, a)
+		}
+	}
+	m.attempts = kept
+	return nil
+}
+
+// failures counts the failed attempts matching match made after since.
+func (m *LoginAttemptRepository) failures(match func(model.LoginAttempt) bool, since time.Time) repository.AttemptFailures {
+	var f repository.AttemptFailures
+	for _, a := range m.attempts {
+		failed := a.Outcome == model.LoginOutcomeInvalidCredentials || a.Outcome == model.LoginOutcomeInvalidTwoFactor
+		if failed && match(a) && a.CreatedAt.After(since) {
+			f.Count++
+			if a.CreatedAt.After(f.Last) {
+				f.Last = a.CreatedAt
+			}
+		}
+	}
+	return f
+}
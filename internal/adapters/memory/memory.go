@@ -0,0 +1,9 @@
+// Package memory keeps repositories in process memory. It backs the demo mode, which runs
+// without a database, and follows the semantics of the postgres adapter so the two pass
+// the same conformance tests.
+package memory
+
+import "time"
+
+// now is the clock used for timestamps the database would set by default.
+var now = time.Now
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"time"
+)
+
+// PartitionRepository has nothing to partition: memory storage lives as long as the process.
+type PartitionRepository struct{}
+
+func NewPartitionRepository() repository.PartitionRepository {
+	return PartitionRepository{}
+}
+
+func (PartitionRepository) EnsurePartitions(ctx context.Context, from, through time.Time) ([]string, error) {
+	return nil, nil
+}
+
+func (PartitionRepository) DownsamplePrices(ctx context.Context, before time.Time) ([]string, error) {
+	return nil, nil
+}
+
+func (PartitionRepository) DropCorrelations(ctx context.Context, before time.Time) ([]string, error) {
+	return nil, nil
+}
+
+func (PartitionRepository) List(ctx context.Context) ([]model.Partition, error) {
+	return nil, nil
+}
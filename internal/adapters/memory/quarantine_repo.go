@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+)
+
+type QuarantineRepository struct {
+	mu     sync.RWMutex
+	prices []model.QuarantinedPrice
+	nextID int64
+}
+
+func NewQuarantineRepository() repository.QuarantineRepository {
+	return &QuarantineRepository{}
+}
+
+// Save ignores a price already held for the same commodity, date and value.
+func (m *QuarantineRepository) Save(ctx context.Context, price model.QuarantinedPrice) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, q := range m.prices {
+		if q.Name == price.Name && q.Date.Equal(price.Date) && q.PriceKg == price.PriceKg {
+			return nil
+		}
+	}
+	m.nextID++
+	price.ID = m.nextID
+	price.ReviewedBy, price.ReviewedAt = nil, nil
+	price.CreatedAt = now().UTC()
+	m.prices = append(m.prices, price)
+	return nil
+}
+
+func (m *QuarantineRepository) FindByID(ctx context.Context, id int64) (model.QuarantinedPrice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, q := range m.prices {
+		if q.ID == id {
+			return q, nil
+		}
+	}
+	return model.QuarantinedPrice{}, sql.ErrNoRows
+}
+
+// List returns quarantined prices newest first. An empty status returns every entry.
+func (m *QuarantineRepository) List(ctx context.Context, status string, limit int) ([]model.QuarantinedPrice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var matched []model.QuarantinedPrice
+	for i := len(m.prices) - 1; i >= 0; i-- {
+		if status == "" || m.prices[i].Status == status {
+			matched = append(matched, m.prices[i])
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].CreatedAt.After(matched[j].CreatedAt) })
+	return page(matched, limit, 0), nil
+}
+
+func (m *QuarantineRepository) CountPending(ctx context.Context, commodity string) (int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	n := 0
+	for _, q := range m.prices {
+		if q.Name == commodity && q.Status == model.QuarantineStatusPending {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// UpdateStatus records a review decision on a pending entry, or returns sql.ErrNoRows.
+func (m *QuarantineRepository) UpdateStatus(ctx context.Context, id int64, status string, reviewerID uint, reviewedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.prices {
+		q := &m.prices[i]
+		if q.ID != id || q.Status != model.QuarantineStatusPending {
+			continue
+		}
+		q.Status = status
+		q.ReviewedBy = &reviewerID
+		q.ReviewedAt = &reviewedAt
+		return nil
+	}
+	return sql.ErrNoRows
+}
@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+)
+
+type RoleRepository struct {
+	mu    sync.RWMutex
+	roles map[string]model.Role
+}
+
+func NewRoleRepository() repository.RoleRepository {
+	return &RoleRepository{roles: make(map[string]model.Role)}
+}
+
+func (m *RoleRepository) SeedDefaults(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, role := range model.DefaultRoles() {
+		if _, ok := m.roles[role.Name]; !ok {
+			m.roles[role.Name] = normalizeRole(role)
+		}
+	}
+	admin := m.roles[model.RoleAdmin]
+	admin.Permissions = append(admin.Permissions, model.AllPermissions...)
+	m.roles[model.RoleAdmin] = normalizeRole(admin)
+	return nil
+}
+
+func (m *RoleRepository) List(ctx context.Context) ([]model.Role, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	roles := make([]model.Role, 0, len(m.roles))
+	for _, r := range m.roles {
+		roles = append(roles, copyRole(r))
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles, nil
+}
+
+func (m *RoleRepository) Get(ctx context.Context, name string) (model.Role, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	r, ok := m.roles[name]
+	if !ok {
+		return model.Role{}, sql.ErrNoRows
+	}
+	return copyRole(r), nil
+}
+
+// Save creates the role or replaces its permissions; a built-in role stays built in.
+func (m *RoleRepository) Save(ctx context.Context, role model.Role) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	role.BuiltIn = m.roles[role.Name].BuiltIn
+	m.roles[role.Name] = normalizeRole(role)
+	return nil
+}
+
+func (m *RoleRepository) Delete(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.roles[name]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(m.roles, name)
+	return nil
+}
+
+// normalizeRole sorts and dedupes the permissions, as the role_permissions table does.
+func normalizeRole(r model.Role) model.Role {
+	seen := make(map[string]bool, len(r.Permissions))
+	perms := []string{}
+	for _, p := range r.Permissions {
+		if !seen[p] {
+			seen[p] = true
+			perms = append(perms, p)
+		}
+	}
+	sort.Strings(perms)
+	r.Permissions = perms
+	return r
+}
+
+func copyRole(r model.Role) model.Role {
+	r.Permissions = append([]string{}, r.Permissions...)
+	return r
+}
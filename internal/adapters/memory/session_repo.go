@@ -0,0 +1,154 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type SessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[string]model.Session
+	tokens   map[string]model.RefreshToken // by hash
+}
+
+func NewSessionRepository() repository.SessionRepository {
+	return &SessionRepository{sessions: make(map[string]model.Session), tokens: make(map[string]model.RefreshToken)}
+}
+
+func (m *SessionRepository) Create(ctx context.Context, s model.Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[s.ID]; ok {
+		return fmt.Errorf("session %s already exists", s.ID)
+	}
+	s.RevokedAt, s.Current = nil, false
+	m.sessions[s.ID] = s
+	return nil
+}
+
+func (m *SessionRepository) FindByID(ctx context.Context, id string) (model.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return model.Session{}, sql.ErrNoRows
+	}
+	return copySession(s), nil
+}
+
+func (m *SessionRepository) ListActive(ctx context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var sessions []model.Session
+	for _, s := range m.sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.ExpiresAt.After(now) {
+			sessions = append(sessions, copySession(s))
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt) })
+	return sessions, nil
+}
+
+func (m *SessionRepository) Touch(ctx context.Context, id string, seenAt time.Time, ip string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil
+	}
+	s.LastSeenAt, s.IP = seenAt, ip
+	if expiresAt.After(s.ExpiresAt) {
+		s.ExpiresAt = expiresAt
+	}
+	m.sessions[id] = s
+	return nil
+}
+
+func (m *SessionRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.sessions[id]; ok && s.RevokedAt == nil {
+		s.RevokedAt = &at
+		m.sessions[id] = s
+	}
+	return nil
+}
+
+func (m *SessionRepository) RevokeAllExcept(ctx context.Context, userID uint, keepID string, at time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ids []string
+	for id, s := range m.sessions {
+		if s.UserID == userID && id != keepID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+			m.sessions[id] = s
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (m *SessionRepository) CreateRefreshToken(ctx context.Context, t model.RefreshToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.sessions[t.SessionID]; !ok {
+		return fmt.Errorf("session %s does not exist", t.SessionID)
+	}
+	if _, ok := m.tokens[t.TokenHash]; ok {
+		return fmt.Errorf("refresh token already exists")
+	}
+	t.RotatedAt = nil
+	m.tokens[t.TokenHash] = t
+	return nil
+}
+
+func (m *SessionRepository) FindRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.tokens[tokenHash]
+	if !ok {
+		return model.RefreshToken{}, sql.ErrNoRows
+	}
+	if t.RotatedAt != nil {
+		at := *t.RotatedAt
+		t.RotatedAt = &at
+	}
+	return t, nil
+}
+
+func (m *SessionRepository) MarkRefreshTokenRotated(ctx context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for hash, t := range m.tokens {
+		if t.ID == id && t.RotatedAt == nil {
+			t.RotatedAt = &at
+			m.tokens[hash] = t
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func copySession(s model.Session) model.Session {
+	if s.RevokedAt != nil {
+		at := *s.RevokedAt
+		s.RevokedAt = &at
+	}
+	return s
+}
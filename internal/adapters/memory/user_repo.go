@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type UserRepository struct {
+	mu     sync.RWMutex
+	users  map[uint]model.User
+	nextID uint
+}
+
+func NewUserRepository() repository.UserRepository {
+	return &UserRepository{users: make(map[uint]model.User)}
+}
+
+func (m *UserRepository) Save(ctx context.Context, user model.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkUnique(0, user.Username, user.Email); err != nil {
+		return err
+	}
+	m.nextID++
+	// Like the INSERT of the postgres adapter, only these fields are taken from user.
+	m.users[m.nextID] = model.User{
+		ID:        m.nextID,
+		Username:  user.Username,
+		Email:     user.Email,
+		Password:  user.Password,
+		Role:      user.Role,
+		CreatedAt: now().UTC(),
+	}
+	return nil
+}
+
+func (m *UserRepository) FindByUsernameOrEmail(ctx context.Context, identifier string) (model.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, u := range m.sorted() {
+		if u.Username == identifier || u.Email == identifier {
+			return u, nil
+		}
+	}
+	return model.User{}, errors.New("user not found")
+}
+
+func (m *UserRepository) FindByID(ctx context.Context, id uint) (model.User, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	u, ok := m.users[id]
+	if !ok {
+		return model.User{}, errors.New("user not found")
+	}
+	return copyUser(u), nil
+}
+
+func (m *UserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	m.update(id, func(u *model.User) {
+		u.Password = hashedPassword
+		u.PasswordResetRequired = false
+	})
+	return nil
+}
+
+func (m *UserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	m.update(id, func(u *model.User) { u.EmailVerified = true })
+	return nil
+}
+
+func (m *UserRepository) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	q := strings.ToLower(strings.TrimSpace(filter.Query))
+	var matched []model.User
+	for _, u := range m.sorted() {
+		if q != "" && !strings.Contains(strings.ToLower(u.Username), q) && !strings.Contains(strings.ToLower(u.Email), q) {
+			continue
+		}
+		if filter.Role != "" && u.Role != filter.Role {
+			continue
+		}
+		if filter.Disabled != nil && u.Disabled != *filter.Disabled {
+			continue
+		}
+		matched = append(matched, u)
+	}
+	return page(matched, filter.Limit, filter.Offset), len(matched), nil
+}
+
+func (m *UserRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	return m.updateExisting(id, func(u *model.User) { u.Role = role })
+}
+
+func (m *UserRepository) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	return m.updateExisting(id, func(u *model.User) { u.Disabled = disabled })
+}
+
+func (m *UserRepository) RequirePasswordReset(ctx context.Context, id uint) error {
+	return m.updateExisting(id, func(u *model.User) { u.PasswordResetRequired = true })
+}
+
+func (m *UserRepository) UpdateEmail(ctx context.Context, id uint, email string, verified bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkUnique(id, "", email); err != nil {
+		return err
+	}
+	return m.updateLocked(id, func(u *model.User) {
+		u.Email = email
+		u.EmailVerified = verified
+	})
+}
+
+func (m *UserRepository) UpdateUsername(ctx context.Context, id uint, username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.checkUnique(id, username, ""); err != nil {
+		return err
+	}
+	return m.updateLocked(id, func(u *model.User) { u.Username = username })
+}
+
+func (m *UserRepository) ScheduleDeletion(ctx context.Context, id uint, at *time.Time) error {
+	return m.updateExisting(id, func(u *model.User) {
+		u.DeletionScheduledAt = nil
+		if at != nil {
+			t := *at
+			u.DeletionScheduledAt = &t
+		}
+	})
+}
+
+func (m *UserRepository) ListDueForDeletion(ctx context.Context, t time.Time) ([]uint, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var ids []uint
+	for _, u := range m.sorted() {
+		if u.DeletionScheduledAt != nil && !u.DeletionScheduledAt.After(t) {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (m *UserRepository) Delete(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.users[id]; !ok {
+		return errors.New("user not found")
+	}
+	delete(m.users, id)
+	return nil
+}
+
+// update changes a user if it exists; like a plain UPDATE, a missing user is not an error.
+func (m *UserRepository) update(id uint, fn func(u *model.User)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_ = m.updateLocked(id, fn)
+}
+
+func (m *UserRepository) updateExisting(id uint, fn func(u *model.User)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateLocked(id, fn)
+}
+
+func (m *UserRepository) updateLocked(id uint, fn func(u *model.User)) error {
+	u, ok := m.users[id]
+	if !ok {
+		return errors.New("user not found")
+	}
+	fn(&u)
+	m.users[id] = u
+	return nil
+}
+
+// checkUnique enforces the unique username and email columns for any user but id.
+func (m *UserRepository) checkUnique(id uint, username, email string) error {
+	for _, u := range m.users {
+		if u.ID == id {
+			continue
+		}
+		if username != "" && u.Username == username {
+			return fmt.Errorf("username %q already exists", username)
+		}
+		if email != "" && u.Email == email {
+			return fmt.Errorf("email %q already exists", email)
+		}
+	}
+	return nil
+}
+
+// sorted returns copies of the users ordered by id.
+func (m *UserRepository) sorted() []model.User {
+	users := make([]model.User, 0, len(m.users))
+	for _, u := range m.users {
+		users = append(users, copyUser(u))
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+func copyUser(u model.User) model.User {
+	if u.DeletionScheduledAt != nil {
+		t := *u.DeletionScheduledAt
+		u.DeletionScheduledAt = &t
+	}
+	return u
+}
+
+// page applies LIMIT and OFFSET the way SQL does.
+func page[T any](items []T, limit, offset int) []T {
+	offset = max(offset, 0)
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type UserTokenRepository struct {
+	mu     sync.Mutex
+	tokens []model.UserToken
+	nextID int64
+}
+
+func NewUserTokenRepository() repository.UserTokenRepository {
+	return &UserTokenRepository{}
+}
+
+func (m *UserTokenRepository) Create(ctx context.Context, t model.UserToken) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.tokens {
+		if existing.TokenHash == t.TokenHash {
+			return fmt.Errorf("token already exists")
+		}
+	}
+	m.nextID++
+	t.ID, t.UsedAt = m.nextID, nil
+	m.tokens = append(m.tokens, t)
+	return nil
+}
+
+func (m *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, at time.Time) (model.UserToken, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, t := range m.tokens {
+		if t.TokenHash == tokenHash && t.Purpose == purpose && t.UsedAt == nil && t.ExpiresAt.After(at) {
+			used := at
+			m.tokens[i].UsedAt = &used
+			t.UsedAt = &at
+			return t, nil
+		}
+	}
+	return model.UserToken{}, sql.ErrNoRows
+}
+
+func (m *UserTokenRepository) InvalidateAll(ctx context.Context, userID uint, purpose string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, t := range m.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			used := at
+			m.tokens[i].UsedAt = &used
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// stubProvider is a minimal OpenID Connect provider: discovery, keys and a token endpoint
+// that checks the PKCE verifier against the challenge of the last authorization.
+type stubProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	challenge string
+	nonce     string
+	// claims overrides the ID token claims issued by the token endpoint.
+	claims func(jwt.MapClaims)
+	// issuer overrides the issuer announced by discovery.
+	issuer string
+}
+
+func newStubProvider(t *testing.T) *stubProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	s := &stubProvider{t: t, key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		issuer := s.server.URL
+		if s.issuer != "" {
+			issuer = s.issuer
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 issuer,
+			"authorization_endpoint": s.server.URL + "/authorize",
+			"token_endpoint":         s.server.URL + "/token",
+			"jwks_uri":               s.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "stub-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", s.token)
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *stubProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if id, secret, _ := r.BasicAuth(); id != "our-client" || secret != "client-secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != s.challenge {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            s.server.URL,
+		"sub":            "external-42",
+		"aud":            "our-client",
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          s.nonce,
+		"email":          "Erin@Example.com",
+		"email_verified": "true",
+		"name":           "Erin",
+	}
+	if s.claims != nil {
+		s.claims(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "stub-key"
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		s.t.Errorf("sign ID token: %v", err)
+	}
+	json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "id_token": idToken, "token_type": "Bearer"})
+}
+
+func (s *stubProvider) provider() *Provider {
+	return NewProvider(s.server.Client(), Config{
+		Name:         "stub",
+		Issuer:       s.server.URL,
+		ClientID:     "our-client",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/api/oauth/stub/callback",
+	})
+}
+
+// authorize starts a login like a browser following AuthCodeURL would.
+func (s *stubProvider) authorize(t *testing.T, p *Provider, verifier string) {
+	t.Helper()
+	sum := sha256.Sum256([]byte(verifier))
+	authURL, err := p.AuthCodeURL(context.Background(), "the-state", "the-nonce", base64.RawURLEncoding.EncodeToString(sum[:]))
+	if err != nil {
+		t.Fatalf("AuthCodeURL() error = %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("AuthCodeURL() = %q: %v", authURL, err)
+	}
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("state") != "the-state" || q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "our-client" {
+		t.Fatalf("AuthCodeURL() = %q", authURL)
+	}
+	s.challenge = q.Get("code_challenge")
+	s.nonce = q.Get("nonce")
+}
+
+func TestProviderExchangeReturnsVerifiedIdentity(t *testing.T) {
+	stub := newStubProvider(t)
+	p := stub.provider()
+	stub.authorize(t, p, "the-verifier")
+
+	identity, err := p.Exchange(context.Background(), "good-code", "the-verifier", "the-nonce")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	if identity.Subject != "external-42" || identity.Email != "Erin@Example.com" || !identity.EmailVerified || identity.Name != "Erin" {
+		t.Fatalf("identity = %+v", identity)
+	}
+}
+
+func TestProviderExchangeRejectsWrongVerifier(t *testing.T) {
+	stub := newStubProvider(t)
+	p := stub.provider()
+	stub.authorize(t, p, "the-verifier")
+
+	if _, err := p.Exchange(context.Background(), "good-code", "another-verifier", "the-nonce"); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Fatalf("Exchange() error = %v, want invalid_grant", err)
+	}
+}
+
+func TestProviderExchangeValidatesIDToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		nonce  string
+		claims func(jwt.MapClaims)
+	}{
+		{name: "nonce mismatch", nonce: "replayed-nonce"},
+		{name: "wrong audience", nonce: "the-nonce", claims: func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{name: "wrong issuer", nonce: "the-nonce", claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "expired", nonce: "the-nonce", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "missing subject", nonce: "the-nonce", claims: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "other authorized party", nonce: "the-nonce", claims: func(c jwt.MapClaims) {
+			c["aud"] = []string{"our-client", "another-client"}
+			c["azp"] = "another-client"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stub := newStubProvider(t)
+			stub.claims = tt.claims
+			p := stub.provider()
+			stub.authorize(t, p, "the-verifier")
+
+			_, err := p.Exchange(context.Background(), "good-code", "the-verifier", tt.nonce)
+			if !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("Exchange() error = %v, want %v", err, ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestProviderRejectsMismatchedDiscoveryIssuer(t *testing.T) {
+	stub := newStubProvider(t)
+	stub.issuer = "https://evil.example.com"
+
+	_, err := stub.provider().AuthCodeURL(context.Background(), "s", "n", "c")
+	if err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Fatalf("AuthCodeURL() error = %v, want issuer mismatch", err)
+	}
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, scopes, rate_limit, created_at, expires_at, last_used_at, revoked_at`
+
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+func NewAPIKeyRepository(db *sql.DB) repository.APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+func (p *APIKeyRepository) Create(ctx context.Context, k model.APIKey) error {
+	query := `INSERT INTO api_keys (id, user_id, name, prefix, key_hash, scopes, rate_limit, created_at, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	_, err := p.db.ExecContext(ctx, query, k.ID, k.UserID, k.Name, k.Prefix, k.KeyHash, pq.Array(k.Scopes), k.RateLimit, k.CreatedAt, k.ExpiresAt)
+	return err
+}
+
+func (p *APIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix=$1`
+	return scanAPIKey(p.db.QueryRowContext(ctx, query, prefix))
+}
+
+func (p *APIKeyRepository) ListByUser(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys
+			  WHERE user_id=$1 AND revoked_at IS NULL ORDER BY created_at DESC`
+	rows, err := p.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []model.APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, rows.Err()
+}
+
+func (p *APIKeyRepository) Revoke(ctx context.Context, userID uint, id string, at time.Time) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE api_keys SET revoked_at=$1 WHERE id=$2 AND user_id=$3 AND revoked_at IS NULL`, at, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *APIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE api_keys SET last_used_at=$1 WHERE id=$2`, at, id)
+	return err
+}
+
+func scanAPIKey(row rowScanner) (model.APIKey, error) {
+	var k model.APIKey
+	var expiresAt, lastUsedAt, revokedAt sql.NullTime
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, pq.Array(&k.Scopes), &k.RateLimit,
+		&k.CreatedAt, &expiresAt, &lastUsedAt, &revokedAt)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	if revokedAt.Valid {
+		k.RevokedAt = &revokedAt.Time
+	}
+	return k, nil
+}
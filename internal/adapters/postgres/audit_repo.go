@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (p *AuditRepository) Record(ctx context.Context, e model.AuditEvent) error {
+	query := `INSERT INTO audit_events (actor_id, action, target_type, target_id, ip, user_agent, result, detail, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+	var actor sql.NullInt64
+	if e.ActorID != nil {
+		actor = sql.NullInt64{Int64: int64(*e.ActorID), Valid: true}
+	}
+	_, err := p.db.ExecContext(ctx, query, actor, e.Action, nullString(e.TargetType), nullString(e.TargetID),
+		nullString(e.IP), nullString(e.UserAgent), e.Result, nullString(e.Detail), e.CreatedAt)
+	return err
+}
+
+func (p *AuditRepository) Query(ctx context.Context, f repository.AuditFilter) ([]model.AuditEvent, error) {
+	var where []string
+	var args []any
+	add := func(cond string, v any) {
+		args = append(args, v)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if f.ActorID != nil {
+		add("actor_id=$%d", *f.ActorID)
+	}
+	if f.Action != "" {
+		add("action=$%d", f.Action)
+	}
+	if f.TargetType != "" {
+		add("target_type=$%d", f.TargetType)
+	}
+	if f.TargetID != "" {
+		add("target_id=$%d", f.TargetID)
+	}
+	if f.Result != "" {
+		add("result=$%d", f.Result)
+	}
+	if !f.Since.IsZero() {
+		add("created_at >= $%d", f.Since)
+	}
+	if !f.Until.IsZero() {
+		add("created_at < $%d", f.Until)
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	args = append(args, f.Limit, f.Offset)
+	query := fmt.Sprintf(`SELECT id, actor_id, action, target_type, target_id, ip, user_agent, result, detail, created_at
+			  FROM audit_events%s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`, clause, len(args)-1, len(args))
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []model.AuditEvent
+	for rows.Next() {
+		var e model.AuditEvent
+		var actor sql.NullInt64
+		var targetType, targetID, ip, userAgent, detail sql.NullString
+		if err := rows.Scan(&e.ID, &actor, &e.Action, &targetType, &targetID, &ip, &userAgent, &e.Result, &detail, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if actor.Valid {
+			id := uint(actor.Int64)
+			e.ActorID = &id
+		}
+		e.TargetType, e.TargetID = targetType.String, targetID.String
+		e.IP, e.UserAgent, e.Detail = ip.String, userAgent.String, detail.String
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (p *AuditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM audit_events WHERE created_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
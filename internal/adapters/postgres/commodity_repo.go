@@ -4,10 +4,19 @@ import (
 	"backend/internal/domain/repository"
 	"context"
 	"database/sql"
+	"time"
 
 	"backend/internal/domain/model"
+
+	"github.com/lib/pq"
 )
 
+// pricesOf selects the prices of commodity $1: raw prices, and the daily rows left of the
+// months past their retention.
+const pricesOf = `SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit FROM commodities WHERE name=$1
+			  UNION ALL
+			  SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit FROM commodity_daily_prices WHERE name=$1`
+
 type CommodityRepository struct {
 	db *sql.DB
 }
@@ -16,48 +25,93 @@ func NewCommodityRepository(db *sql.DB) repository.CommodityRepository {
 	return &CommodityRepository{db: db}
 }
 
-func (p *CommodityRepository) Migrate() error {
-	query := `CREATE TABLE IF NOT EXISTS commodities (
-		id					SERIAL PRIMARY KEY,
-		name			VARCHAR(50) NOT NULL,
-		date 			TIMESTAMP NOT NULL,
-		price_kg 			FLOAT,
-		unit 			VARCHAR(50) NOT NULL,
-		fetched_at 			TIMESTAMP NOT NULL DEFAULT NOW(),
-		UNIQUE(name, date)
-	);`
-
-	_, err := p.db.Exec(query)
-	return err
-}
-
 func (p *CommodityRepository) Save(ctx context.Context, stock model.Commodity) error {
-	query := `INSERT INTO commodities (name, date, price_kg, unit, fetched_at) 
-			  VALUES ($1, $2, $3, $4, $5) 
+	query := `INSERT INTO commodities (name, date, price_kg, unit, fetched_at, native_price, native_unit) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7) 
 			  ON CONFLICT (name, date) 
 			  DO UPDATE SET 
 			  	price_kg = EXCLUDED.price_kg,
 			  	unit = EXCLUDED.unit,
-			  	fetched_at = EXCLUDED.fetched_at`
-	_, err := p.db.ExecContext(ctx, query, stock.Name, stock.Date, stock.PriceKg, stock.Unit, stock.FetchedAt)
+			  	fetched_at = EXCLUDED.fetched_at,
+			  	native_price = EXCLUDED.native_price,
+			  	native_unit = EXCLUDED.native_unit`
+	_, err := p.db.ExecContext(ctx, query, stock.Name, stock.Date, stock.PriceKg, stock.Unit, stock.FetchedAt,
+		nullFloat(stock.NativePrice), nullString(stock.NativeUnit))
 	return err
 }
 
-func (p *CommodityRepository) GetLatestPrice(ctx context.Context, commodity string) (model.Commodity, error) {
-	query := `SELECT id, name, date, price_kg, unit, fetched_at
-			  FROM commodities WHERE name=$1 ORDER BY date DESC LIMIT 1`
-	row := p.db.QueryRowContext(ctx, query, commodity)
-	var c model.Commodity
-	err := row.Scan(&c.ID, &c.Name, &c.Date, &c.PriceKg, &c.Unit, &c.FetchedAt)
+// SaveBatch streams the prices into a temporary table with COPY and upserts them with a
+// single statement, in one transaction.
+func (p *CommodityRepository) SaveBatch(ctx context.Context, stocks []model.Commodity) error {
+	stocks = dedupeCommodities(stocks)
+	if len(stocks) == 0 {
+		return nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
 	if err != nil {
-		return model.Commodity{}, err
+		return err
 	}
-	return c, nil
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `CREATE TEMP TABLE commodities_staging (
+		name			VARCHAR(50) NOT NULL,
+		date			TIMESTAMP NOT NULL,
+		price_kg		FLOAT,
+		unit			VARCHAR(50) NOT NULL,
+		fetched_at		TIMESTAMP NOT NULL,
+		native_price	FLOAT,
+		native_unit		VARCHAR(10)
+	) ON COMMIT DROP`)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("commodities_staging",
+		"name", "date", "price_kg", "unit", "fetched_at", "native_price", "native_unit"))
+	if err != nil {
+		return err
+	}
+	for _, c := range stocks {
+		if _, err := stmt.ExecContext(ctx, c.Name, c.Date, c.PriceKg, c.Unit, c.FetchedAt,
+			nullFloat(c.NativePrice), nullString(c.NativeUnit)); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	// An Exec without arguments ends the COPY.
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO commodities (name, date, price_kg, unit, fetched_at, native_price, native_unit)
+			  SELECT name, date, price_kg, unit, fetched_at, native_price, native_unit FROM commodities_staging
+			  ON CONFLICT (name, date)
+			  DO UPDATE SET
+			  	price_kg = EXCLUDED.price_kg,
+			  	unit = EXCLUDED.unit,
+			  	fetched_at = EXCLUDED.fetched_at,
+			  	native_price = EXCLUDED.native_price,
+			  	native_unit = EXCLUDED.native_unit`)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *CommodityRepository) GetLatestPrice(ctx context.Context, commodity string) (model.Commodity, error) {
+	query := `SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit
+			  FROM (` + pricesOf + `) prices ORDER BY date DESC LIMIT 1`
+	return scanCommodity(p.db.QueryRowContext(ctx, query, commodity))
 }
 
 func (p *CommodityRepository) GetPriceHistory(ctx context.Context, commodity string, limit int) ([]model.Commodity, error) {
-	query := `SELECT id, name, date, price_kg, unit, fetched_at
-			  FROM commodities WHERE name=$1 ORDER BY date DESC LIMIT $2`
+	query := `SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit
+			  FROM (` + pricesOf + `) prices ORDER BY date DESC LIMIT $2`
 	rows, err := p.db.QueryContext(ctx, query, commodity, limit)
 	if err != nil {
 		return nil, err
@@ -66,8 +120,8 @@ func (p *CommodityRepository) GetPriceHistory(ctx context.Context, commodity str
 
 	var history []model.Commodity
 	for rows.Next() {
-		var c model.Commodity
-		if err := rows.Scan(&c.ID, &c.Name, &c.Date, &c.PriceKg, &c.Unit, &c.FetchedAt); err != nil {
+		c, err := scanCommodity(rows)
+		if err != nil {
 			return nil, err
 		}
 		history = append(history, c)
@@ -78,6 +132,29 @@ func (p *CommodityRepository) GetPriceHistory(ctx context.Context, commodity str
 	return history, nil
 }
 
+func (p *CommodityRepository) GetDailyCloses(ctx context.Context, commodity string, days int) ([]model.Commodity, error) {
+	query := `SELECT DISTINCT ON (date_trunc('day', date)) id, name, date, price_kg, unit, fetched_at, native_price, native_unit
+			  FROM (` + pricesOf + `) prices ORDER BY date_trunc('day', date) DESC, date DESC LIMIT $2`
+	rows, err := p.db.QueryContext(ctx, query, commodity, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var closes []model.Commodity
+	for rows.Next() {
+		c, err := scanCommodity(rows)
+		if err != nil {
+			return nil, err
+		}
+		closes = append(closes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return closes, nil
+}
+
 func (p *CommodityRepository) HasRecentData(ctx context.Context) (bool, error) {
 	var count int
 	err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM commodities WHERE date > NOW() - INTERVAL '2 days'").Scan(&count)
@@ -85,4 +162,46 @@ func (p *CommodityRepository) HasRecentData(ctx context.Context) (bool, error) {
 		return false, err
 	}
 	return count > 0, nil
-}
\ No newline at end of file
+}
+
+// dedupeCommodities keeps the last price given for each (name, date). One upsert statement
+// cannot update the same row twice.
+func dedupeCommodities(stocks []model.Commodity) []model.Commodity {
+	type key struct {
+		name string
+		date time.Time
+	}
+	index := make(map[key]int, len(stocks))
+	out := make([]model.Commodity, 0, len(stocks))
+	for _, c := range stocks {
+		k := key{c.Name, c.Date.UTC()}
+		if i, ok := index[k]; ok {
+			out[i] = c
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, c)
+	}
+	return out
+}
+
+func scanCommodity(row rowScanner) (model.Commodity, error) {
+	var c model.Commodity
+	var nativePrice sql.NullFloat64
+	var nativeUnit sql.NullString
+	err := row.Scan(&c.ID, &c.Name, &c.Date, &c.PriceKg, &c.Unit, &c.FetchedAt, &nativePrice, &nativeUnit)
+	if err != nil {
+		return model.Commodity{}, err
+	}
+	c.NativePrice = nativePrice.Float64
+	c.NativeUnit = nativeUnit.String
+	return c, nil
+}
+
+func nullFloat(v float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: v, Valid: v != 0}
+}
+
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"backend/internal/adapters/repotest"
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// emptyDB returns the test database with the tables the conformance tests use emptied.
+func emptyDB(t *testing.T) *sql.DB {
+	db := testDB(t)
+	_, err := db.Exec(`TRUNCATE users, login_attempts, commodities, commodity_daily_prices, correlations, correlation_events RESTART IDENTITY CASCADE`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return NewUserRepository(emptyDB(t))
+	})
+}
+
+func TestCommodityRepositoryConformance(t *testing.T) {
+	repotest.RunCommodityRepositoryTests(t, func(t *testing.T) repository.CommodityRepository {
+		return NewCommodityRepository(emptyDB(t))
+	})
+}
+
+func TestCorrelationRepositoryConformance(t *testing.T) {
+	repotest.RunCorrelationRepositoryTests(t, func(t *testing.T) repository.CorrelationRepository {
+		return NewCorrelationRepository(emptyDB(t))
+	})
+}
+
+// withTwoUsers adds users 1 and 2, which sessions and emailed tokens reference.
+func withTwoUsers(t *testing.T, db *sql.DB) *sql.DB {
+	users := NewUserRepository(db)
+	for _, name := range []string{"alice", "bob"} {
+		if err := users.Save(context.Background(), model.User{Username: name, Email: name + "@example.com", Password: "x", Role: model.RoleUser}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestSessionRepositoryConformance(t *testing.T) {
+	repotest.RunSessionRepositoryTests(t, func(t *testing.T) repository.SessionRepository {
+		return NewSessionRepository(withTwoUsers(t, emptyDB(t)))
+	})
+}
+
+func TestUserTokenRepositoryConformance(t *testing.T) {
+	repotest.RunUserTokenRepositoryTests(t, func(t *testing.T) repository.UserTokenRepository {
+		return NewUserTokenRepository(withTwoUsers(t, emptyDB(t)))
+	})
+}
+
+func TestLoginAttemptRepositoryConformance(t *testing.T) {
+	repotest.RunLoginAttemptRepositoryTests(t, func(t *testing.T) repository.LoginAttemptRepository {
+		return NewLoginAttemptRepository(withTwoUsers(t, emptyDB(t)))
+	})
+}
@@ -17,22 +17,6 @@ func NewCorrelationRepository(db *sql.DB) repository.CorrelationRepository {
 	return &CorrelationRepository{db}
 }
 
-func (p *CorrelationRepository) Migrate() error {
-	query := `CREATE TABLE IF NOT EXISTS correlations (
-		id					SERIAL PRIMARY KEY,
-		commodity_a			VARCHAR(50) NOT NULL,
-		commodity_b 		VARCHAR(50) NOT NULL,
-		correlationDate 	TIMESTAMP NOT NULL,
-		pearsonR 			FLOAT,
-		spearmanRho 		FLOAT,
-		dataPoints 			INT NOT NULL,
-		createdAt 			TIMESTAMP NOT NULL DEFAULT NOW()
-	);`
-
-	_, err := p.db.Exec(query)
-	return err
-}
-
 
 func (p *CorrelationRepository) Save(ctx context.Context, correlation *model.Correlation) error {
 	query := `INSERT INTO correlations(commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints) VALUES ($1, $2, $3, $4, $5, $6)`
@@ -118,4 +102,57 @@ func (p *CorrelationRepository) GetTopCorrelated(ctx context.Context, commodity
 		return nil, err
 	}
 	return correlations, nil
-}
\ No newline at end of file
+}
+
+// SaveEvents stores detected regime events. Events already recorded by a previous
+// detection run are skipped, which makes re-scanning the whole history idempotent.
+func (p *CorrelationRepository) SaveEvents(ctx context.Context, events []*model.CorrelationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	b.WriteString("INSERT INTO correlation_events(commodity_a, commodity_b, start_date, kind, baseline, value, magnitude, window_size) VALUES ")
+
+	args := make([]interface{}, 0, len(events)*8)
+	for i, e := range events {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		base := i * 8
+		fmt.Fprintf(&b, "($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)", base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8)
+		args = append(args, e.CommodityA, e.CommodityB, e.StartDate, e.Kind, e.Baseline, e.Value, e.Magnitude, e.Window)
+	}
+	b.WriteString(" ON CONFLICT (commodity_a, commodity_b, start_date, kind) DO NOTHING")
+
+	_, err := p.db.ExecContext(ctx, b.String(), args...)
+	return err
+}
+
+// GetEvents returns the most recent regime events, newest first. When both commodities are
+// given only that pair is returned, in either order; otherwise events for every pair are listed.
+func (p *CorrelationRepository) GetEvents(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.CorrelationEvent, error) {
+	query := `SELECT id, commodity_a, commodity_b, start_date, kind, baseline, value, magnitude, window_size, created_at
+			  FROM correlation_events
+			  WHERE $1 = '' OR (commodity_a=$1 AND commodity_b=$2) OR (commodity_a=$2 AND commodity_b=$1)
+			  ORDER BY start_date DESC LIMIT $3`
+
+	rows, err := p.db.QueryContext(ctx, query, commodityA, commodityB, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var events []*model.CorrelationEvent
+	for rows.Next() {
+		var e model.CorrelationEvent
+		err := rows.Scan(&e.ID, &e.CommodityA, &e.CommodityB, &e.StartDate, &e.Kind, &e.Baseline, &e.Value, &e.Magnitude, &e.Window, &e.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
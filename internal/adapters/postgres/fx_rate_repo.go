@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+)
+
+type FXRateRepository struct {
+	db *sql.DB
+}
+
+func NewFXRateRepository(db *sql.DB) repository.FXRateRepository {
+	return &FXRateRepository{db: db}
+}
+
+func (p *FXRateRepository) Save(ctx context.Context, rate model.FXRate) error {
+	query := `INSERT INTO fx_rates (base, quote, date, rate, fetched_at)
+			  VALUES ($1, $2, $3, $4, $5)
+			  ON CONFLICT (base, quote, date)
+			  DO UPDATE SET
+			  	rate = EXCLUDED.rate,
+			  	fetched_at = EXCLUDED.fetched_at`
+	_, err := p.db.ExecContext(ctx, query, rate.Base, rate.Quote, rate.Date, rate.Rate, rate.FetchedAt)
+	return err
+}
+
+func (p *FXRateRepository) GetLatest(ctx context.Context, base, quote string) (model.FXRate, error) {
+	query := `SELECT id, base, quote, date, rate, fetched_at
+			  FROM fx_rates WHERE base=$1 AND quote=$2 ORDER BY date DESC LIMIT 1`
+	var r model.FXRate
+	err := p.db.QueryRowContext(ctx, query, base, quote).Scan(&r.ID, &r.Base, &r.Quote, &r.Date, &r.Rate, &r.FetchedAt)
+	if err != nil {
+		return model.FXRate{}, err
+	}
+	return r, nil
+}
+
+// ListRates returns every stored daily rate for the pair in chronological order.
+func (p *FXRateRepository) ListRates(ctx context.Context, base, quote string) ([]model.FXRate, error) {
+	query := `SELECT id, base, quote, date, rate, fetched_at
+			  FROM fx_rates WHERE base=$1 AND quote=$2 ORDER BY date ASC`
+	rows, err := p.db.QueryContext(ctx, query, base, quote)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []model.FXRate
+	for rows.Next() {
+		var r model.FXRate
+		if err := rows.Scan(&r.ID, &r.Base, &r.Quote, &r.Date, &r.Rate, &r.FetchedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type IdentityRepository struct {
+	db *sql.DB
+}
+
+func NewIdentityRepository(db *sql.DB) repository.IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+const identityColumns = `provider, subject, user_id, email, created_at, last_login_at`
+
+func scanIdentity(row rowScanner) (model.UserIdentity, error) {
+	var i model.UserIdentity
+	var email sql.NullString
+	var lastLogin sql.NullTime
+	if err := row.Scan(&i.Provider, &i.Subject, &i.UserID, &email, &i.CreatedAt, &lastLogin); err != nil {
+		return model.UserIdentity{}, err
+	}
+	i.Email = email.String
+	if lastLogin.Valid {
+		i.LastLoginAt = &lastLogin.Time
+	}
+	return i, nil
+}
+
+func (p *IdentityRepository) Find(ctx context.Context, provider, subject string) (model.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE provider=$1 AND subject=$2`
+	return scanIdentity(p.db.QueryRowContext(ctx, query, provider, subject))
+}
+
+func (p *IdentityRepository) Create(ctx context.Context, i model.UserIdentity) error {
+	query := `INSERT INTO user_identities (provider, subject, user_id, email, created_at) VALUES ($1, $2, $3, $4, $5)`
+	_, err := p.db.ExecContext(ctx, query, i.Provider, i.Subject, i.UserID, nullString(i.Email), i.CreatedAt)
+	return err
+}
+
+func (p *IdentityRepository) ListByUser(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	query := `SELECT ` + identityColumns + ` FROM user_identities WHERE user_id=$1 ORDER BY provider`
+	rows, err := p.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identities []model.UserIdentity
+	for rows.Next() {
+		i, err := scanIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, i)
+	}
+	return identities, rows.Err()
+}
+
+func (p *IdentityRepository) TouchLogin(ctx context.Context, provider, subject string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE user_identities SET last_login_at=$3 WHERE provider=$1 AND subject=$2`, provider, subject, at)
+	return err
+}
@@ -0,0 +1,334 @@
+package postgres
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the pg_advisory_lock key held while migrations run, so two runners
+// never apply the same migration.
+const migrationLockKey int64 = 0x636f6d6d6f64 // "commod"
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is a numbered schema change read from migrations/NNNN_name.up.sql and its
+// matching .down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Checksum identifies the up script, so a migration edited after it was applied is caught.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// MigrationStatus is a known migration and when it was applied, if it was.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type appliedMigration struct {
+	Version   int
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Migrator applies the embedded migrations and records them in schema_migrations.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction, and returns
+// the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := readApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		pending, err := pendingMigrations(m.migrations, applied)
+		if err != nil {
+			return err
+		}
+		for _, mig := range pending {
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Up); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+					mig.Version, mig.Name, mig.Checksum())
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the last steps applied migrations, newest first, and returns the ones it
+// reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := readApplied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if _, err := pendingMigrations(m.migrations, applied); err != nil {
+			return err
+		}
+		known := make(map[int]Migration, len(m.migrations))
+		for _, mig := range m.migrations {
+			known[mig.Version] = mig
+		}
+		for i := len(applied) - 1; i >= 0 && len(done) < steps; i-- {
+			mig := known[applied[i].Version]
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, mig.Down); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("revert migration %04d_%s: %w", mig.Version, mig.Name, err)
+			}
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Status lists the known migrations and when each was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := ensureMigrationsTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := readApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pendingMigrations(m.migrations, applied); err != nil {
+		return nil, err
+	}
+	appliedAt := make(map[int]time.Time, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+	status := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := MigrationStatus{Migration: mig}
+		if at, ok := appliedAt[mig.Version]; ok {
+			s.AppliedAt = &at
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+// Pending returns the migrations not applied yet. It fails when the applied ones do not
+// match this build, e.g. a migration file was edited or the database is from a newer release.
+func (m *Migrator) Pending(ctx context.Context) ([]Migration, error) {
+	if err := ensureMigrationsTable(ctx, m.db); err != nil {
+		return nil, err
+	}
+	applied, err := readApplied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	return pendingMigrations(m.migrations, applied)
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	return withAdvisoryLock(ctx, m.db, migrationLockKey, func(conn *sql.Conn) error {
+		if err := ensureMigrationsTable(ctx, conn); err != nil {
+			return err
+		}
+		return fn(conn)
+	})
+}
+
+// withAdvisoryLock runs fn on a single connection while it holds the session advisory lock
+// key, so concurrent runners across instances take turns.
+func withAdvisoryLock(ctx context.Context, db *sql.DB, key int64, fn func(conn *sql.Conn) error) error {
+	// Session-level advisory locks belong to a connection, so everything runs on one.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, key); err != nil {
+		return fmt.Errorf("acquire advisory lock: %w", err)
+	}
+	// Unlock even when ctx is cancelled; the connection could otherwise return to the pool
+	// still holding the lock.
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, key)
+
+	return fn(conn)
+}
+
+type execQuerier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+func ensureMigrationsTable(ctx context.Context, db execQuerier) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version		INT PRIMARY KEY,
+		name		VARCHAR(100) NOT NULL,
+		checksum	VARCHAR(64) NOT NULL,
+		applied_at	TIMESTAMP NOT NULL DEFAULT NOW()
+	)`)
+	return err
+}
+
+func readApplied(ctx context.Context, db execQuerier) ([]appliedMigration, error) {
+	rows, err := db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applied []appliedMigration
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+	return applied, rows.Err()
+}
+
+func inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// pendingMigrations checks the applied migrations against the known ones and returns those
+// left to apply, in order.
+func pendingMigrations(migrations []Migration, applied []appliedMigration) ([]Migration, error) {
+	known := make(map[int]Migration, len(migrations))
+	for _, mig := range migrations {
+		known[mig.Version] = mig
+	}
+	done := make(map[int]bool, len(applied))
+	for _, a := range applied {
+		mig, ok := known[a.Version]
+		if !ok {
+			return nil, fmt.Errorf("database has migration %04d_%s, which this build does not know", a.Version, a.Name)
+		}
+		if a.Checksum != mig.Checksum() {
+			return nil, fmt.Errorf("migration %04d_%s was changed after it was applied", a.Version, a.Name)
+		}
+		done[a.Version] = true
+	}
+
+	var pending []Migration
+	for _, mig := range migrations {
+		if !done[mig.Version] {
+			pending = append(pending, mig)
+		}
+	}
+	// An older migration left out while newer ones ran would apply out of order.
+	if len(pending) > 0 && len(applied) > 0 && pending[0].Version < applied[len(applied)-1].Version {
+		return nil, fmt.Errorf("migration %04d_%s is older than the last applied one", pending[0].Version, pending[0].Name)
+	}
+	return pending, nil
+}
+
+// loadMigrations reads the NNNN_name.up.sql and NNNN_name.down.sql pairs in dir, sorted by
+// version.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		parts := migrationFileName.FindStringSubmatch(e.Name())
+		if parts == nil {
+			return nil, fmt.Errorf("migration file %s: name must look like 0001_name.up.sql", e.Name())
+		}
+		version, _ := strconv.Atoi(parts[1])
+		if version <= 0 {
+			return nil, fmt.Errorf("migration file %s: version must be positive", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: parts[2]}
+			byVersion[version] = mig
+		} else if mig.Name != parts[2] {
+			return nil, fmt.Errorf("migration %04d has two names: %s and %s", version, mig.Name, parts[2])
+		}
+		script := &mig.Up
+		if parts[3] == "down" {
+			script = &mig.Down
+		}
+		if *script != "" {
+			return nil, fmt.Errorf("migration %04d_%s has two %s files", version, mig.Name, parts[3])
+		}
+		*script = string(body)
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", mig.Version, mig.Name)
+		}
+		migrations = append(migrations, *mig)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
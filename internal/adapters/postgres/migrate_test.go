@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 {
+		t.Fatalf("migrations should start at version 1, got %+v", migrations)
+	}
+	for i, m := range migrations {
+		if i > 0 && m.Version <= migrations[i-1].Version {
+			t.Errorf("migration %d is out of order", m.Version)
+		}
+	}
+}
+
+func TestLoadMigrationsPairsAndSorts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0002_add_index.up.sql":   {Data: []byte("CREATE INDEX i ON t(a);")},
+		"m/0002_add_index.down.sql": {Data: []byte("DROP INDEX i;")},
+		"m/0001_create.up.sql":      {Data: []byte("CREATE TABLE t (a INT);")},
+		"m/0001_create.down.sql":    {Data: []byte("DROP TABLE t;")},
+	}
+	migrations, err := loadMigrations(fsys, "m")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(migrations))
+	}
+	first := migrations[0]
+	if first.Version != 1 || first.Name != "create" || first.Up != "CREATE TABLE t (a INT);" || first.Down != "DROP TABLE t;" {
+		t.Errorf("unexpected first migration: %+v", first)
+	}
+	if migrations[1].Version != 2 {
+		t.Errorf("expected version 2 second, got %d", migrations[1].Version)
+	}
+}
+
+func TestLoadMigrationsRejectsBadSets(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {
+			"m/0001_create.up.sql": {Data: []byte("CREATE TABLE t (a INT);")},
+		},
+		"bad name": {
+			"m/create.sql": {Data: []byte("CREATE TABLE t (a INT);")},
+		},
+		"two names for a version": {
+			"m/0001_create.up.sql":   {Data: []byte("CREATE TABLE t (a INT);")},
+			"m/0001_other.down.sql":  {Data: []byte("DROP TABLE t;")},
+			"m/0001_create.down.sql": {Data: []byte("DROP TABLE t;")},
+		},
+		"same version twice": {
+			"m/1_create.up.sql":      {Data: []byte("CREATE TABLE t (a INT);")},
+			"m/0001_create.up.sql":   {Data: []byte("CREATE TABLE t (a INT);")},
+			"m/0001_create.down.sql": {Data: []byte("DROP TABLE t;")},
+		},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := loadMigrations(fsys, "m"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestPendingMigrations(t *testing.T) {
+	migrations := []Migration{
+		{Version: 1, Name: "create", Up: "CREATE TABLE t (a INT);", Down: "DROP TABLE t;"},
+		{Version: 2, Name: "add_index", Up: "CREATE INDEX i ON t(a);", Down: "DROP INDEX i;"},
+	}
+	applied := func(versions ...int) []appliedMigration {
+		var out []appliedMigration
+		for _, v := range versions {
+			m := migrations[v-1]
+			out = append(out, appliedMigration{Version: m.Version, Name: m.Name, Checksum: m.Checksum()})
+		}
+		return out
+	}
+
+	pending, err := pendingMigrations(migrations, nil)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("fresh database: expected 2 pending, got %v (%v)", pending, err)
+	}
+	pending, err = pendingMigrations(migrations, applied(1))
+	if err != nil || len(pending) != 1 || pending[0].Version != 2 {
+		t.Fatalf("expected version 2 pending, got %v (%v)", pending, err)
+	}
+	pending, err = pendingMigrations(migrations, applied(1, 2))
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("expected nothing pending, got %v (%v)", pending, err)
+	}
+
+	t.Run("edited after applied", func(t *testing.T) {
+		edited := applied(1)
+		edited[0].Checksum = Migration{Up: "CREATE TABLE t (a BIGINT);"}.Checksum()
+		_, err := pendingMigrations(migrations, edited)
+		if err == nil || !strings.Contains(err.Error(), "changed") {
+			t.Errorf("expected a checksum error, got %v", err)
+		}
+	})
+	t.Run("unknown applied version", func(t *testing.T) {
+		_, err := pendingMigrations(migrations[:1], applied(1, 2))
+		if err == nil || !strings.Contains(err.Error(), "does not know") {
+			t.Errorf("expected an unknown version error, got %v", err)
+		}
+	})
+	t.Run("gap before applied version", func(t *testing.T) {
+		_, err := pendingMigrations(migrations, applied(2))
+		if err == nil {
+			t.Error("expected an out of order error")
+		}
+	})
+}
@@ -0,0 +1,21 @@
+-- Drops every table of the baseline, and the data in them.
+
+DROP TABLE IF EXISTS correlation_events;
+DROP TABLE IF EXISTS correlations;
+DROP TABLE IF EXISTS fx_rates;
+DROP TABLE IF EXISTS price_quarantine;
+DROP TABLE IF EXISTS commodities;
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS audit_events_append_only();
+DROP TABLE IF EXISTS user_preferences;
+DROP TABLE IF EXISTS user_identities;
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS user_tokens;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS user_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS users;
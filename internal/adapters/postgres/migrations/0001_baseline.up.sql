@@ -0,0 +1,227 @@
+-- Schema as it stood when versioned migrations were introduced. Every statement is
+-- idempotent so databases created by earlier releases adopt this version unchanged.
+
+CREATE TABLE IF NOT EXISTS users (
+	id                      SERIAL PRIMARY KEY,
+	username                VARCHAR(50) NOT NULL UNIQUE,
+	email                   VARCHAR(100) NOT NULL UNIQUE,
+	password                VARCHAR(255) NOT NULL,
+	role                    VARCHAR(50) NOT NULL DEFAULT 'user',
+	created_at              TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_login              TIMESTAMP
+);
+-- Columns added to users after the table was first created.
+ALTER TABLE users
+	ADD COLUMN IF NOT EXISTS role VARCHAR(50) NOT NULL DEFAULT 'user',
+	ADD COLUMN IF NOT EXISTS email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS disabled BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT FALSE,
+	ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS sessions (
+	id              VARCHAR(64) PRIMARY KEY,
+	user_id         INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	device          VARCHAR(100) NOT NULL DEFAULT '',
+	ip              VARCHAR(64) NOT NULL DEFAULT '',
+	user_agent      TEXT NOT NULL DEFAULT '',
+	created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_seen_at    TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at      TIMESTAMP NOT NULL,
+	revoked_at      TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON sessions(user_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id          VARCHAR(64) PRIMARY KEY,
+	session_id  VARCHAR(64) NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+	token_hash  VARCHAR(64) NOT NULL UNIQUE,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at  TIMESTAMP NOT NULL,
+	rotated_at  TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_id_idx ON refresh_tokens(session_id);
+
+CREATE TABLE IF NOT EXISTS user_totp (
+	user_id         INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	secret          VARCHAR(64) NOT NULL,
+	enabled         BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	enabled_at      TIMESTAMP,
+	last_used_step  BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS user_recovery_codes (
+	id          SERIAL PRIMARY KEY,
+	user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	code_hash   VARCHAR(64) NOT NULL,
+	used_at     TIMESTAMP,
+	UNIQUE(user_id, code_hash)
+);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+	id          BIGSERIAL PRIMARY KEY,
+	account_key VARCHAR(150) NOT NULL,
+	identifier  VARCHAR(150) NOT NULL,
+	user_id     INT REFERENCES users(id) ON DELETE SET NULL,
+	ip          VARCHAR(64) NOT NULL DEFAULT '',
+	user_agent  TEXT NOT NULL DEFAULT '',
+	outcome     VARCHAR(32) NOT NULL,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS login_attempts_account_idx ON login_attempts(account_key, created_at);
+CREATE INDEX IF NOT EXISTS login_attempts_ip_idx ON login_attempts(ip, created_at);
+
+CREATE TABLE IF NOT EXISTS roles (
+	name        VARCHAR(50) PRIMARY KEY,
+	built_in    BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+	role        VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+	permission  VARCHAR(64) NOT NULL,
+	PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_tokens (
+	id          BIGSERIAL PRIMARY KEY,
+	user_id     INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	purpose     VARCHAR(32) NOT NULL,
+	token_hash  VARCHAR(64) NOT NULL UNIQUE,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at  TIMESTAMP NOT NULL,
+	used_at     TIMESTAMP,
+	payload     VARCHAR(255) NOT NULL DEFAULT ''
+);
+ALTER TABLE user_tokens ADD COLUMN IF NOT EXISTS payload VARCHAR(255) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS user_tokens_user_purpose_idx ON user_tokens(user_id, purpose);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id              VARCHAR(64) PRIMARY KEY,
+	user_id         INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name            VARCHAR(100) NOT NULL,
+	prefix          VARCHAR(32) NOT NULL UNIQUE,
+	key_hash        VARCHAR(64) NOT NULL,
+	scopes          TEXT[] NOT NULL DEFAULT '{}',
+	rate_limit      INT NOT NULL,
+	created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	expires_at      TIMESTAMP,
+	last_used_at    TIMESTAMP,
+	revoked_at      TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS api_keys_user_idx ON api_keys(user_id);
+
+CREATE TABLE IF NOT EXISTS user_identities (
+	provider        VARCHAR(50) NOT NULL,
+	subject         VARCHAR(255) NOT NULL,
+	user_id         INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	email           VARCHAR(255),
+	created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	last_login_at   TIMESTAMP,
+	PRIMARY KEY (provider, subject),
+	UNIQUE (provider, user_id)
+);
+CREATE INDEX IF NOT EXISTS user_identities_user_idx ON user_identities(user_id);
+
+CREATE TABLE IF NOT EXISTS user_preferences (
+	user_id         INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+	display_name    VARCHAR(64) NOT NULL DEFAULT '',
+	timezone        VARCHAR(64) NOT NULL DEFAULT '',
+	currency        VARCHAR(3) NOT NULL DEFAULT '',
+	unit            VARCHAR(16) NOT NULL DEFAULT '',
+	chart_interval  VARCHAR(8) NOT NULL DEFAULT '',
+	theme           VARCHAR(16) NOT NULL DEFAULT '',
+	updated_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS audit_events (
+	id          BIGSERIAL PRIMARY KEY,
+	actor_id    INT,
+	action      VARCHAR(64) NOT NULL,
+	target_type VARCHAR(32),
+	target_id   VARCHAR(64),
+	ip          VARCHAR(64),
+	user_agent  TEXT,
+	result      VARCHAR(16) NOT NULL,
+	detail      TEXT,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS audit_events_created_idx ON audit_events(created_at);
+CREATE INDEX IF NOT EXISTS audit_events_actor_idx ON audit_events(actor_id, created_at);
+
+-- Events are append-only; only the retention job deletes them.
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS audit_events_no_update ON audit_events;
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events
+	FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TABLE IF NOT EXISTS commodities (
+	id              SERIAL PRIMARY KEY,
+	name            VARCHAR(50) NOT NULL,
+	date            TIMESTAMP NOT NULL,
+	price_kg        FLOAT,
+	unit            VARCHAR(50) NOT NULL,
+	fetched_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(name, date)
+);
+-- Raw provider quote, added after the table was first created.
+ALTER TABLE commodities
+	ADD COLUMN IF NOT EXISTS native_price FLOAT,
+	ADD COLUMN IF NOT EXISTS native_unit VARCHAR(10);
+
+CREATE TABLE IF NOT EXISTS price_quarantine (
+	id          SERIAL PRIMARY KEY,
+	name        VARCHAR(50) NOT NULL,
+	date        TIMESTAMP NOT NULL,
+	price_kg    FLOAT NOT NULL,
+	unit        VARCHAR(50) NOT NULL,
+	fetched_at  TIMESTAMP NOT NULL,
+	reason      TEXT NOT NULL,
+	status      VARCHAR(20) NOT NULL DEFAULT 'pending',
+	reviewed_by INT,
+	reviewed_at TIMESTAMP,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(name, date, price_kg)
+);
+ALTER TABLE price_quarantine
+	ADD COLUMN IF NOT EXISTS native_price FLOAT,
+	ADD COLUMN IF NOT EXISTS native_unit VARCHAR(10);
+
+CREATE TABLE IF NOT EXISTS fx_rates (
+	id          SERIAL PRIMARY KEY,
+	base        VARCHAR(3) NOT NULL,
+	quote       VARCHAR(3) NOT NULL,
+	date        DATE NOT NULL,
+	rate        FLOAT NOT NULL,
+	fetched_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(base, quote, date)
+);
+
+CREATE TABLE IF NOT EXISTS correlations (
+	id              SERIAL PRIMARY KEY,
+	commodity_a     VARCHAR(50) NOT NULL,
+	commodity_b     VARCHAR(50) NOT NULL,
+	correlationDate TIMESTAMP NOT NULL,
+	pearsonR        FLOAT,
+	spearmanRho     FLOAT,
+	dataPoints      INT NOT NULL,
+	createdAt       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS correlation_events (
+	id          SERIAL PRIMARY KEY,
+	commodity_a VARCHAR(50) NOT NULL,
+	commodity_b VARCHAR(50) NOT NULL,
+	start_date  TIMESTAMP NOT NULL,
+	kind        VARCHAR(20) NOT NULL,
+	baseline    FLOAT NOT NULL,
+	value       FLOAT NOT NULL,
+	magnitude   FLOAT NOT NULL,
+	window_size INT NOT NULL,
+	created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+	UNIQUE(commodity_a, commodity_b, start_date, kind)
+);
@@ -0,0 +1,43 @@
+-- Moves prices and correlations back into plain tables. Daily rows of downsampled months
+-- are kept as prices.
+
+CREATE TABLE commodities_plain (
+	id              INT NOT NULL DEFAULT nextval('commodities_id_seq'),
+	name            VARCHAR(50) NOT NULL,
+	date            TIMESTAMP NOT NULL,
+	price_kg        FLOAT,
+	unit            VARCHAR(50) NOT NULL,
+	fetched_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	native_price    FLOAT,
+	native_unit     VARCHAR(10)
+);
+INSERT INTO commodities_plain (id, name, date, price_kg, unit, fetched_at, native_price, native_unit)
+	SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit FROM commodities;
+ALTER SEQUENCE commodities_id_seq OWNED BY NONE;
+DROP TABLE commodities;
+ALTER TABLE commodities_plain RENAME TO commodities;
+ALTER SEQUENCE commodities_id_seq OWNED BY commodities.id;
+ALTER TABLE commodities ADD PRIMARY KEY (id), ADD UNIQUE (name, date);
+
+INSERT INTO commodities (id, name, date, price_kg, unit, fetched_at, native_price, native_unit)
+	SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit FROM commodity_daily_prices
+	ON CONFLICT DO NOTHING;
+DROP TABLE commodity_daily_prices;
+
+CREATE TABLE correlations_plain (
+	id              INT NOT NULL DEFAULT nextval('correlations_id_seq'),
+	commodity_a     VARCHAR(50) NOT NULL,
+	commodity_b     VARCHAR(50) NOT NULL,
+	correlationDate TIMESTAMP NOT NULL,
+	pearsonR        FLOAT,
+	spearmanRho     FLOAT,
+	dataPoints      INT NOT NULL,
+	createdAt       TIMESTAMP NOT NULL DEFAULT NOW()
+);
+INSERT INTO correlations_plain (id, commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints, createdAt)
+	SELECT id, commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints, createdAt FROM correlations;
+ALTER SEQUENCE correlations_id_seq OWNED BY NONE;
+DROP TABLE correlations;
+ALTER TABLE correlations_plain RENAME TO correlations;
+ALTER SEQUENCE correlations_id_seq OWNED BY correlations.id;
+ALTER TABLE correlations ADD PRIMARY KEY (id);
@@ -0,0 +1,87 @@
+-- Prices and correlations become range partitioned by month. The maintenance job creates
+-- upcoming partitions ahead of time; rows outside every partition land in the default one.
+-- Raw prices past their retention are reduced to one row per commodity and day in
+-- commodity_daily_prices before their partition is dropped.
+
+ALTER TABLE commodities RENAME TO commodities_unpartitioned;
+ALTER SEQUENCE commodities_id_seq OWNED BY NONE;
+
+CREATE TABLE commodities (
+	id              INT NOT NULL DEFAULT nextval('commodities_id_seq'),
+	name            VARCHAR(50) NOT NULL,
+	date            TIMESTAMP NOT NULL,
+	price_kg        FLOAT,
+	unit            VARCHAR(50) NOT NULL,
+	fetched_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+	native_price    FLOAT,
+	native_unit     VARCHAR(10)
+) PARTITION BY RANGE (date);
+CREATE TABLE commodities_default PARTITION OF commodities DEFAULT;
+
+ALTER TABLE correlations RENAME TO correlations_unpartitioned;
+ALTER SEQUENCE correlations_id_seq OWNED BY NONE;
+
+CREATE TABLE correlations (
+	id              INT NOT NULL DEFAULT nextval('correlations_id_seq'),
+	commodity_a     VARCHAR(50) NOT NULL,
+	commodity_b     VARCHAR(50) NOT NULL,
+	correlationDate TIMESTAMP NOT NULL,
+	pearsonR        FLOAT,
+	spearmanRho     FLOAT,
+	dataPoints      INT NOT NULL,
+	createdAt       TIMESTAMP NOT NULL DEFAULT NOW()
+) PARTITION BY RANGE (correlationDate);
+CREATE TABLE correlations_default PARTITION OF correlations DEFAULT;
+
+-- One partition per month from the oldest stored row through three months ahead, named
+-- <table>_pYYYY_MM.
+DO $$
+DECLARE
+	t RECORD;
+	oldest TIMESTAMP;
+	m DATE;
+BEGIN
+	FOR t IN SELECT * FROM (VALUES
+		('commodities', 'commodities_unpartitioned', 'date'),
+		('correlations', 'correlations_unpartitioned', 'correlationdate')
+	) AS v(parent, source, col)
+	LOOP
+		EXECUTE format('SELECT MIN(%I) FROM %I', t.col, t.source) INTO oldest;
+		FOR m IN SELECT generate_series(
+			date_trunc('month', LEAST(COALESCE(oldest, NOW()), NOW())),
+			date_trunc('month', NOW()) + INTERVAL '3 months',
+			INTERVAL '1 month')::date
+		LOOP
+			EXECUTE format('CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+				t.parent || '_p' || to_char(m, 'YYYY_MM'), t.parent, m, (m + INTERVAL '1 month')::date);
+		END LOOP;
+	END LOOP;
+END
+$$;
+
+INSERT INTO commodities (id, name, date, price_kg, unit, fetched_at, native_price, native_unit)
+	SELECT id, name, date, price_kg, unit, fetched_at, native_price, native_unit FROM commodities_unpartitioned;
+DROP TABLE commodities_unpartitioned;
+ALTER SEQUENCE commodities_id_seq OWNED BY commodities.id;
+-- Unique keys of a partitioned table must include the partition column.
+ALTER TABLE commodities ADD PRIMARY KEY (id, date), ADD UNIQUE (name, date);
+
+INSERT INTO correlations (id, commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints, createdAt)
+	SELECT id, commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints, createdAt FROM correlations_unpartitioned;
+DROP TABLE correlations_unpartitioned;
+ALTER SEQUENCE correlations_id_seq OWNED BY correlations.id;
+ALTER TABLE correlations ADD PRIMARY KEY (id, correlationDate);
+CREATE INDEX correlations_pair_date_idx ON correlations (commodity_a, commodity_b, correlationDate);
+
+-- A daily row keeps the id and quote of the day's last raw price, dated at midnight.
+CREATE TABLE commodity_daily_prices (
+	id              INT PRIMARY KEY,
+	name            VARCHAR(50) NOT NULL,
+	date            TIMESTAMP NOT NULL,
+	price_kg        FLOAT,
+	unit            VARCHAR(50) NOT NULL,
+	fetched_at      TIMESTAMP NOT NULL,
+	native_price    FLOAT,
+	native_unit     VARCHAR(10),
+	UNIQUE(name, date)
+);
@@ -0,0 +1,3 @@
+-- Grants removed by the up migration are not restored; roles can be edited again instead.
+
+SELECT 1;
@@ -0,0 +1,3 @@
+-- imports:run guarded no endpoint and is no longer a known permission.
+
+DELETE FROM role_permissions WHERE permission = 'imports:run';
@@ -0,0 +1,221 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// partitionLockKey serialises partition maintenance across server instances.
+const partitionLockKey int64 = 0x7061727469 // "parti"
+
+// partitionedTable is a table range partitioned by month on column, with partitions named
+// <name>_pYYYY_MM and a <name>_default partition for rows outside them.
+type partitionedTable struct {
+	name   string
+	column string
+}
+
+var (
+	pricePartitions       = partitionedTable{name: "commodities", column: "date"}
+	correlationPartitions = partitionedTable{name: "correlations", column: "correlationdate"}
+)
+
+var partitionSuffix = regexp.MustCompile(`_p(\d{4})_(\d{2})$`)
+
+type PartitionRepository struct {
+	db *sql.DB
+}
+
+func NewPartitionRepository(db *sql.DB) repository.PartitionRepository {
+	return &PartitionRepository{db: db}
+}
+
+func (p *PartitionRepository) EnsurePartitions(ctx context.Context, from, through time.Time) ([]string, error) {
+	var created []string
+	err := withAdvisoryLock(ctx, p.db, partitionLockKey, func(conn *sql.Conn) error {
+		for _, t := range []partitionedTable{pricePartitions, correlationPartitions} {
+			for month := monthStart(from); !month.After(through); month = month.AddDate(0, 1, 0) {
+				name := partitionName(t.name, month)
+				var exists bool
+				if err := conn.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, name).Scan(&exists); err != nil {
+					return err
+				}
+				if exists {
+					continue
+				}
+				if err := createPartition(ctx, conn, t, name, month); err != nil {
+					return fmt.Errorf("create partition %s: %w", name, err)
+				}
+				created = append(created, name)
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+// createPartition builds the partition for month and attaches it. Rows of that month that
+// were stored in the default partition, because no partition existed yet, are moved into it;
+// Postgres refuses the new partition otherwise.
+func createPartition(ctx context.Context, conn *sql.Conn, t partitionedTable, name string, month time.Time) error {
+	next := month.AddDate(0, 1, 0)
+	return inTx(ctx, conn, func(tx *sql.Tx) error {
+		stmts := []struct {
+			query string
+			args  []any
+		}{
+			{fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS)`, pq.QuoteIdentifier(name), pq.QuoteIdentifier(t.name)), nil},
+			{fmt.Sprintf(`WITH moved AS (DELETE FROM %s WHERE %s >= $1 AND %s < $2 RETURNING *) INSERT INTO %s SELECT * FROM moved`,
+				pq.QuoteIdentifier(t.name+"_default"), t.column, t.column, pq.QuoteIdentifier(name)), []any{month, next}},
+			{fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`,
+				pq.QuoteIdentifier(t.name), pq.QuoteIdentifier(name), month.Format("2006-01-02"), next.Format("2006-01-02")), nil},
+		}
+		for _, s := range stmts {
+			if _, err := tx.ExecContext(ctx, s.query, s.args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// downsampleQuery copies the last price of each commodity and day from %s into the daily
+// table. A day already downsampled keeps its row.
+const downsampleQuery = `INSERT INTO commodity_daily_prices (id, name, date, price_kg, unit, fetched_at, native_price, native_unit)
+	SELECT DISTINCT ON (name, date_trunc('day', date))
+		id, name, date_trunc('day', date), price_kg, unit, fetched_at, native_price, native_unit
+	FROM %s %s
+	ORDER BY name, date_trunc('day', date), date DESC
+	ON CONFLICT DO NOTHING`
+
+func (p *PartitionRepository) DownsamplePrices(ctx context.Context, before time.Time) ([]string, error) {
+	cutoff := monthStart(before)
+	var dropped []string
+	err := withAdvisoryLock(ctx, p.db, partitionLockKey, func(conn *sql.Conn) error {
+		names, err := expiredPartitions(ctx, conn, pricePartitions, cutoff)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			err := inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, fmt.Sprintf(downsampleQuery, pq.QuoteIdentifier(name), "")); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DROP TABLE `+pq.QuoteIdentifier(name))
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("downsample partition %s: %w", name, err)
+			}
+			dropped = append(dropped, name)
+		}
+
+		// Old rows that never had a partition of their own.
+		return inTx(ctx, conn, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(downsampleQuery, "commodities_default", "WHERE date < $1"), cutoff); err != nil {
+				return fmt.Errorf("downsample default partition: %w", err)
+			}
+			_, err := tx.ExecContext(ctx, `DELETE FROM commodities_default WHERE date < $1`, cutoff)
+			return err
+		})
+	})
+	return dropped, err
+}
+
+func (p *PartitionRepository) DropCorrelations(ctx context.Context, before time.Time) ([]string, error) {
+	cutoff := monthStart(before)
+	var dropped []string
+	err := withAdvisoryLock(ctx, p.db, partitionLockKey, func(conn *sql.Conn) error {
+		names, err := expiredPartitions(ctx, conn, correlationPartitions, cutoff)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if _, err := conn.ExecContext(ctx, `DROP TABLE `+pq.QuoteIdentifier(name)); err != nil {
+				return fmt.Errorf("drop partition %s: %w", name, err)
+			}
+			dropped = append(dropped, name)
+		}
+		_, err = conn.ExecContext(ctx, `DELETE FROM correlations_default WHERE correlationDate < $1`, cutoff)
+		return err
+	})
+	return dropped, err
+}
+
+func (p *PartitionRepository) List(ctx context.Context) ([]model.Partition, error) {
+	query := `SELECT parent.relname, child.relname, pg_get_expr(child.relpartbound, child.oid),
+			  GREATEST(child.reltuples, 0)::BIGINT, pg_total_relation_size(child.oid)
+			  FROM pg_inherits i
+			  JOIN pg_class parent ON parent.oid = i.inhparent
+			  JOIN pg_class child ON child.oid = i.inhrelid
+			  WHERE parent.relname = ANY($1)
+			  ORDER BY parent.relname, child.relname`
+	rows, err := p.db.QueryContext(ctx, query, pq.Array([]string{pricePartitions.name, correlationPartitions.name}))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var partitions []model.Partition
+	for rows.Next() {
+		var part model.Partition
+		if err := rows.Scan(&part.Table, &part.Name, &part.Bounds, &part.Rows, &part.Bytes); err != nil {
+			return nil, err
+		}
+		partitions = append(partitions, part)
+	}
+	return partitions, rows.Err()
+}
+
+// expiredPartitions lists the monthly partitions of t whose month ended by cutoff.
+func expiredPartitions(ctx context.Context, conn *sql.Conn, t partitionedTable, cutoff time.Time) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT child.relname FROM pg_inherits i
+		JOIN pg_class parent ON parent.oid = i.inhparent
+		JOIN pg_class child ON child.oid = i.inhrelid
+		WHERE parent.relname = $1 ORDER BY child.relname`, t.name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		if month, ok := partitionMonth(name); ok && !month.AddDate(0, 1, 0).After(cutoff) {
+			names = append(names, name)
+		}
+	}
+	return names, rows.Err()
+}
+
+func partitionName(table string, month time.Time) string {
+	return fmt.Sprintf("%s_p%04d_%02d", table, month.Year(), int(month.Month()))
+}
+
+// partitionMonth returns the month a partition named by partitionName covers.
+func partitionMonth(name string) (time.Time, bool) {
+	m := partitionSuffix.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("2006_01", m[1]+"_"+m[2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
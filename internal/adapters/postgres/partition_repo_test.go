@@ -0,0 +1,35 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionNameRoundTrip(t *testing.T) {
+	month := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	name := partitionName("commodities", month)
+	if name != "commodities_p2026_03" {
+		t.Fatalf("unexpected partition name %q", name)
+	}
+	got, ok := partitionMonth(name)
+	if !ok || !got.Equal(month) {
+		t.Errorf("partitionMonth(%q) = %v, %v", name, got, ok)
+	}
+}
+
+func TestPartitionMonthIgnoresOtherPartitions(t *testing.T) {
+	for _, name := range []string{"commodities_default", "commodities", "commodities_p2026_13", "commodities_p26_01"} {
+		if _, ok := partitionMonth(name); ok {
+			t.Errorf("%q should not be a monthly partition", name)
+		}
+	}
+}
+
+func TestMonthStartUsesUTC(t *testing.T) {
+	// 00:30 on 1 April in Paris is still March in UTC.
+	paris := time.FixedZone("CEST", 2*3600)
+	got := monthStart(time.Date(2026, time.April, 1, 0, 30, 0, 0, paris))
+	if want := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("monthStart = %v, want %v", got, want)
+	}
+}
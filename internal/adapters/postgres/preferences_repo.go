@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+)
+
+type PreferencesRepository struct {
+	db *sql.DB
+}
+
+func NewPreferencesRepository(db *sql.DB) repository.PreferencesRepository {
+	return &PreferencesRepository{db: db}
+}
+
+func (p *PreferencesRepository) Get(ctx context.Context, userID uint) (model.UserPreferences, error) {
+	query := `SELECT user_id, display_name, timezone, currency, unit, chart_interval, theme, updated_at
+			  FROM user_preferences WHERE user_id=$1`
+	var prefs model.UserPreferences
+	err := p.db.QueryRowContext(ctx, query, userID).Scan(&prefs.UserID, &prefs.DisplayName, &prefs.Timezone,
+		&prefs.Currency, &prefs.Unit, &prefs.ChartInterval, &prefs.Theme, &prefs.UpdatedAt)
+	if err != nil {
+		return model.UserPreferences{}, err
+	}
+	return prefs, nil
+}
+
+func (p *PreferencesRepository) Save(ctx context.Context, prefs model.UserPreferences) error {
+	query := `INSERT INTO user_preferences (user_id, display_name, timezone, currency, unit, chart_interval, theme, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			  ON CONFLICT (user_id) DO UPDATE SET
+				display_name=EXCLUDED.display_name, timezone=EXCLUDED.timezone, currency=EXCLUDED.currency,
+				unit=EXCLUDED.unit, chart_interval=EXCLUDED.chart_interval, theme=EXCLUDED.theme,
+				updated_at=EXCLUDED.updated_at`
+	_, err := p.db.ExecContext(ctx, query, prefs.UserID, prefs.DisplayName, prefs.Timezone, prefs.Currency,
+		prefs.Unit, prefs.ChartInterval, prefs.Theme, prefs.UpdatedAt)
+	return err
+}
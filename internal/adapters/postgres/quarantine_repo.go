@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type QuarantineRepository struct {
+	db *sql.DB
+}
+
+func NewQuarantineRepository(db *sql.DB) repository.QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// Save stores a suspicious price. The same value fetched again on a later refresh is
+// ignored so a glitch that persists upstream does not flood the review queue.
+func (p *QuarantineRepository) Save(ctx context.Context, price model.QuarantinedPrice) error {
+	query := `INSERT INTO price_quarantine (name, date, price_kg, unit, fetched_at, reason, status, native_price, native_unit)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			  ON CONFLICT (name, date, price_kg) DO NOTHING`
+	_, err := p.db.ExecContext(ctx, query, price.Name, price.Date, price.PriceKg, price.Unit, price.FetchedAt, price.Reason, price.Status,
+		nullFloat(price.NativePrice), nullString(price.NativeUnit))
+	return err
+}
+
+func (p *QuarantineRepository) FindByID(ctx context.Context, id int64) (model.QuarantinedPrice, error) {
+	query := `SELECT id, name, date, price_kg, unit, fetched_at, reason, status, reviewed_by, reviewed_at, created_at, native_price, native_unit
+			  FROM price_quarantine WHERE id=$1`
+	return scanQuarantinedPrice(p.db.QueryRowContext(ctx, query, id))
+}
+
+// List returns quarantined prices newest first. An empty status returns every entry.
+func (p *QuarantineRepository) List(ctx context.Context, status string, limit int) ([]model.QuarantinedPrice, error) {
+	query := `SELECT id, name, date, price_kg, unit, fetched_at, reason, status, reviewed_by, reviewed_at, created_at, native_price, native_unit
+			  FROM price_quarantine WHERE ($1 = '' OR status = $1) ORDER BY created_at DESC LIMIT $2`
+	rows, err := p.db.QueryContext(ctx, query, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []model.QuarantinedPrice
+	for rows.Next() {
+		q, err := scanQuarantinedPrice(rows)
+		if err != nil {
+			return nil, err
+		}
+		prices = append(prices, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (p *QuarantineRepository) CountPending(ctx context.Context, commodity string) (int, error) {
+	var count int
+	err := p.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM price_quarantine WHERE name=$1 AND status='pending'`, commodity).Scan(&count)
+	return count, err
+}
+
+// UpdateStatus records a review decision. Only pending entries can be reviewed;
+// sql.ErrNoRows is returned otherwise.
+func (p *QuarantineRepository) UpdateStatus(ctx context.Context, id int64, status string, reviewerID uint, reviewedAt time.Time) error {
+	query := `UPDATE price_quarantine SET status=$1, reviewed_by=$2, reviewed_at=$3 WHERE id=$4 AND status='pending'`
+	res, err := p.db.ExecContext(ctx, query, status, reviewerID, reviewedAt, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanQuarantinedPrice(row rowScanner) (model.QuarantinedPrice, error) {
+	var q model.QuarantinedPrice
+	var reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+	var nativePrice sql.NullFloat64
+	var nativeUnit sql.NullString
+	err := row.Scan(&q.ID, &q.Name, &q.Date, &q.PriceKg, &q.Unit, &q.FetchedAt, &q.Reason, &q.Status, &reviewedBy, &reviewedAt, &q.CreatedAt, &nativePrice, &nativeUnit)
+	if err != nil {
+		return model.QuarantinedPrice{}, err
+	}
+	q.NativePrice = nativePrice.Float64
+	q.NativeUnit = nativeUnit.String
+	if reviewedBy.Valid {
+		id := uint(reviewedBy.Int64)
+		q.ReviewedBy = &id
+	}
+	if reviewedAt.Valid {
+		q.ReviewedAt = &reviewedAt.Time
+	}
+	return q, nil
+}
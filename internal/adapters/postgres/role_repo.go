@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+)
+
+type RoleRepository struct {
+	db *sql.DB
+}
+
+func NewRoleRepository(db *sql.DB) repository.RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// SeedDefaults creates the built-in roles that do not exist yet and grants the admin role
+// any permission it lacks.
+func (p *RoleRepository) SeedDefaults(ctx context.Context) error {
+	// Seed built-in roles once; later edits by admins are kept across restarts.
+	for _, role := range model.DefaultRoles() {
+		res, err := p.db.ExecContext(ctx, `INSERT INTO roles (name, built_in) VALUES ($1, TRUE) ON CONFLICT (name) DO NOTHING`, role.Name)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		for _, perm := range role.Permissions {
+			if _, err := p.db.ExecContext(ctx, `INSERT INTO role_permissions (role, permission) VALUES ($1, $2)`, role.Name, perm); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The admin role always holds every permission, including ones added in later releases.
+	for _, perm := range model.AllPermissions {
+		if _, err := p.db.ExecContext(ctx, `INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`, model.RoleAdmin, perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *RoleRepository) List(ctx context.Context) ([]model.Role, error) {
+	query := `SELECT r.name, r.built_in, rp.permission
+			  FROM roles r LEFT JOIN role_permissions rp ON rp.role = r.name
+			  ORDER BY r.name, rp.permission`
+	rows, err := p.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []model.Role
+	for rows.Next() {
+		var name string
+		var builtIn bool
+		var perm sql.NullString
+		if err := rows.Scan(&name, &builtIn, &perm); err != nil {
+			return nil, err
+		}
+		if len(roles) == 0 || roles[len(roles)-1].Name != name {
+			roles = append(roles, model.Role{Name: name, BuiltIn: builtIn, Permissions: []string{}})
+		}
+		if perm.Valid {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, perm.String)
+		}
+	}
+	return roles, rows.Err()
+}
+
+func (p *RoleRepository) Get(ctx context.Context, name string) (model.Role, error) {
+	role := model.Role{Name: name, Permissions: []string{}}
+	if err := p.db.QueryRowContext(ctx, `SELECT built_in FROM roles WHERE name=$1`, name).Scan(&role.BuiltIn); err != nil {
+		return model.Role{}, err
+	}
+
+	rows, err := p.db.QueryContext(ctx, `SELECT permission FROM role_permissions WHERE role=$1 ORDER BY permission`, name)
+	if err != nil {
+		return model.Role{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var perm string
+		if err := rows.Scan(&perm); err != nil {
+			return model.Role{}, err
+		}
+		role.Permissions = append(role.Permissions, perm)
+	}
+	return role, rows.Err()
+}
+
+func (p *RoleRepository) Save(ctx context.Context, role model.Role) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO roles (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, role.Name); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM role_permissions WHERE role=$1`, role.Name); err != nil {
+		return err
+	}
+	for _, perm := range role.Permissions {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO role_permissions (role, permission) VALUES ($1, $2) ON CONFLICT DO NOTHING`, role.Name, perm); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (p *RoleRepository) Delete(ctx context.Context, name string) error {
+	res, err := p.db.ExecContext(ctx, `DELETE FROM roles WHERE name=$1`, name)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
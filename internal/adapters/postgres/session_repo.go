@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) repository.SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (p *SessionRepository) Create(ctx context.Context, s model.Session) error {
+	query := `INSERT INTO sessions (id, user_id, device, ip, user_agent, created_at, last_seen_at, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := p.db.ExecContext(ctx, query, s.ID, s.UserID, s.Device, s.IP, s.UserAgent, s.CreatedAt, s.LastSeenAt, s.ExpiresAt)
+	return err
+}
+
+const sessionColumns = `id, user_id, device, ip, user_agent, created_at, last_seen_at, expires_at, revoked_at`
+
+func scanSession(row rowScanner) (model.Session, error) {
+	var s model.Session
+	var revokedAt sql.NullTime
+	if err := row.Scan(&s.ID, &s.UserID, &s.Device, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastSeenAt, &s.ExpiresAt, &revokedAt); err != nil {
+		return model.Session{}, err
+	}
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.Time
+	}
+	return s, nil
+}
+
+func (p *SessionRepository) FindByID(ctx context.Context, id string) (model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id=$1`
+	return scanSession(p.db.QueryRowContext(ctx, query, id))
+}
+
+func (p *SessionRepository) ListActive(ctx context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions
+			  WHERE user_id=$1 AND revoked_at IS NULL AND expires_at > $2
+			  ORDER BY last_seen_at DESC`
+	rows, err := p.db.QueryContext(ctx, query, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []model.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+	return sessions, rows.Err()
+}
+
+func (p *SessionRepository) Touch(ctx context.Context, id string, seenAt time.Time, ip string, expiresAt time.Time) error {
+	query := `UPDATE sessions SET last_seen_at=$1, ip=$2, expires_at=GREATEST(expires_at, $3) WHERE id=$4`
+	_, err := p.db.ExecContext(ctx, query, seenAt, ip, expiresAt, id)
+	return err
+}
+
+func (p *SessionRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	_, err := p.db.ExecContext(ctx, `UPDATE sessions SET revoked_at=$1 WHERE id=$2 AND revoked_at IS NULL`, at, id)
+	return err
+}
+
+func (p *SessionRepository) RevokeAllExcept(ctx context.Context, userID uint, keepID string, at time.Time) ([]string, error) {
+	query := `UPDATE sessions SET revoked_at=$1
+			  WHERE user_id=$2 AND id<>$3 AND revoked_at IS NULL
+			  RETURNING id`
+	rows, err := p.db.QueryContext(ctx, query, at, userID, keepID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (p *SessionRepository) CreateRefreshToken(ctx context.Context, t model.RefreshToken) error {
+	query := `INSERT INTO refresh_tokens (id, session_id, token_hash, created_at, expires_at)
+			  VALUES ($1, $2, $3, $4, $5)`
+	_, err := p.db.ExecContext(ctx, query, t.ID, t.SessionID, t.TokenHash, t.CreatedAt, t.ExpiresAt)
+	return err
+}
+
+func (p *SessionRepository) FindRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, error) {
+	query := `SELECT id, session_id, token_hash, created_at, expires_at, rotated_at
+			  FROM refresh_tokens WHERE token_hash=$1`
+	var t model.RefreshToken
+	var rotatedAt sql.NullTime
+	err := p.db.QueryRowContext(ctx, query, tokenHash).Scan(&t.ID, &t.SessionID, &t.TokenHash, &t.CreatedAt, &t.ExpiresAt, &rotatedAt)
+	if err != nil {
+		return model.RefreshToken{}, err
+	}
+	if rotatedAt.Valid {
+		t.RotatedAt = &rotatedAt.Time
+	}
+	return t, nil
+}
+
+func (p *SessionRepository) MarkRefreshTokenRotated(ctx context.Context, id string, at time.Time) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE refresh_tokens SET rotated_at=$1 WHERE id=$2 AND rotated_at IS NULL`, at, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+func NewTwoFactorRepository(db *sql.DB) repository.TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func (p *TwoFactorRepository) Get(ctx context.Context, userID uint) (model.TwoFactor, error) {
+	query := `SELECT user_id, secret, enabled, created_at, enabled_at, last_used_step
+			  FROM user_totp WHERE user_id=$1`
+	var tf model.TwoFactor
+	var enabledAt sql.NullTime
+	err := p.db.QueryRowContext(ctx, query, userID).Scan(&tf.UserID, &tf.Secret, &tf.Enabled, &tf.CreatedAt, &enabledAt, &tf.LastUsedStep)
+	if err != nil {
+		return model.TwoFactor{}, err
+	}
+	if enabledAt.Valid {
+		tf.EnabledAt = &enabledAt.Time
+	}
+	return tf, nil
+}
+
+func (p *TwoFactorRepository) SavePending(ctx context.Context, userID uint, secret string, at time.Time) error {
+	query := `INSERT INTO user_totp (user_id, secret, enabled, created_at)
+			  VALUES ($1, $2, FALSE, $3)
+			  ON CONFLICT (user_id) DO UPDATE
+			  SET secret=EXCLUDED.secret, enabled=FALSE, created_at=EXCLUDED.created_at, enabled_at=NULL, last_used_step=0`
+	_, err := p.db.ExecContext(ctx, query, userID, secret, at)
+	return err
+}
+
+func (p *TwoFactorRepository) Enable(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string, at time.Time) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE user_totp SET enabled=TRUE, enabled_at=$1, last_used_step=$2 WHERE user_id=$3`, at, step, userID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+	for _, hash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO user_recovery_codes (user_id, code_hash) VALUES ($1, $2)`, userID, hash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (p *TwoFactorRepository) UseStep(ctx context.Context, userID uint, step int64) error {
+	res, err := p.db.ExecContext(ctx, `UPDATE user_totp SET last_used_step=$1 WHERE user_id=$2 AND last_used_step < $1`, step, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, codeHash string, at time.Time) error {
+	query := `UPDATE user_recovery_codes SET used_at=$1
+			  WHERE user_id=$2 AND code_hash=$3 AND used_at IS NULL`
+	res, err := p.db.ExecContext(ctx, query, at, userID, codeHash)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (p *TwoFactorRepository) Delete(ctx context.Context, userID uint) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_recovery_codes WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_totp WHERE user_id=$1`, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
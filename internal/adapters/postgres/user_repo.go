@@ -6,8 +6,13 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
+const userColumns = `id, username, email, password, role, email_verified, disabled, password_reset_required, created_at, deletion_scheduled_at`
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -16,26 +21,6 @@ func NewUserRepository(db *sql.DB) repository.UserRepository {
 	return &UserRepository{db}
 }
 
-func (p *UserRepository) Migrate() error {
-	query := `CREATE TABLE IF NOT EXISTS users (
-		id          SERIAL PRIMARY KEY,
-		username    VARCHAR(50) NOT NULL UNIQUE,
-		email       VARCHAR(100) NOT NULL UNIQUE,
-		password    VARCHAR(255) NOT NULL,
-		role        VARCHAR(50) NOT NULL DEFAULT 'user',
-		created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
-		last_login  TIMESTAMP
-	);`
-
-	if _, err := p.db.Exec(query); err != nil {
-		return err
-	}
-
-	// Add role column for existing tables
-	_, _ = p.db.Exec(`ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(50) NOT NULL DEFAULT 'user'`)
-	return nil
-}
-
 func (p *UserRepository) Save(ctx context.Context, user model.User) error {
 	query := `INSERT INTO users(username, email, password, role) VALUES ($1, $2, $3, $4)`
 	_, err := p.db.ExecContext(ctx, query, user.Username, user.Email, user.Password, user.Role)
@@ -43,10 +28,8 @@ func (p *UserRepository) Save(ctx context.Context, user model.User) error {
 }
 
 func (p *UserRepository) FindByUsernameOrEmail(ctx context.Context, identifier string) (model.User, error) {
-	var user model.User
-	query := `SELECT id, username, email, password, role FROM users WHERE username=$1 OR email=$2 LIMIT 1`
-	row := p.db.QueryRowContext(ctx, query, identifier, identifier)
-	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role)
+	query := `SELECT ` + userColumns + ` FROM users WHERE username=$1 OR email=$2 LIMIT 1`
+	user, err := scanUser(p.db.QueryRowContext(ctx, query, identifier, identifier))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.User{}, errors.New("user not found")
@@ -57,10 +40,8 @@ func (p *UserRepository) FindByUsernameOrEmail(ctx context.Context, identifier s
 }
 
 func (p *UserRepository) FindByID(ctx context.Context, id uint) (model.User, error) {
-	var user model.User
-	query := `SELECT id, username, email, password, role FROM users WHERE id=$1 LIMIT 1`
-	row := p.db.QueryRowContext(ctx, query, id)
-	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id=$1 LIMIT 1`
+	user, err := scanUser(p.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return model.User{}, errors.New("user not found")
@@ -71,7 +52,137 @@ func (p *UserRepository) FindByID(ctx context.Context, id uint) (model.User, err
 }
 
 func (p *UserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
-	query := `UPDATE users SET password=$1 WHERE id=$2`
+	query := `UPDATE users SET password=$1, password_reset_required=FALSE WHERE id=$2`
 	_, err := p.db.ExecContext(ctx, query, hashedPassword, id)
 	return err
 }
+
+func (p *UserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	query := `UPDATE users SET email_verified=TRUE WHERE id=$1`
+	_, err := p.db.ExecContext(ctx, query, id)
+	return err
+}
+
+func (p *UserRepository) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int, error) {
+	var where []string
+	var args []any
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		args = append(args, "%"+escapeLike(strings.ToLower(q))+"%")
+		where = append(where, fmt.Sprintf("(LOWER(username) LIKE $%d OR LOWER(email) LIKE $%d)", len(args), len(args)))
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		where = append(where, fmt.Sprintf("role=$%d", len(args)))
+	}
+	if filter.Disabled != nil {
+		args = append(args, *filter.Disabled)
+		where = append(where, fmt.Sprintf("disabled=$%d", len(args)))
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := p.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+clause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`SELECT %s FROM users%s ORDER BY id LIMIT $%d OFFSET $%d`, userColumns, clause, len(args)-1, len(args))
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	return users, total, rows.Err()
+}
+
+func (p *UserRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	return p.execForUser(ctx, `UPDATE users SET role=$1 WHERE id=$2`, role, id)
+}
+
+func (p *UserRepository) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	return p.execForUser(ctx, `UPDATE users SET disabled=$1 WHERE id=$2`, disabled, id)
+}
+
+func (p *UserRepository) RequirePasswordReset(ctx context.Context, id uint) error {
+	return p.execForUser(ctx, `UPDATE users SET password_reset_required=TRUE WHERE id=$1`, id)
+}
+
+func (p *UserRepository) UpdateEmail(ctx context.Context, id uint, email string, verified bool) error {
+	return p.execForUser(ctx, `UPDATE users SET email=$1, email_verified=$2 WHERE id=$3`, email, verified, id)
+}
+
+func (p *UserRepository) UpdateUsername(ctx context.Context, id uint, username string) error {
+	return p.execForUser(ctx, `UPDATE users SET username=$1 WHERE id=$2`, username, id)
+}
+
+func (p *UserRepository) ScheduleDeletion(ctx context.Context, id uint, at *time.Time) error {
+	return p.execForUser(ctx, `UPDATE users SET deletion_scheduled_at=$1 WHERE id=$2`, at, id)
+}
+
+func (p *UserRepository) ListDueForDeletion(ctx context.Context, t time.Time) ([]uint, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT id FROM users WHERE deletion_scheduled_at <= $1 ORDER BY id`, t)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Delete removes the user. Sessions, tokens and two-factor data go with it through
+// ON DELETE CASCADE; login attempts are kept with their user_id cleared.
+func (p *UserRepository) Delete(ctx context.Context, id uint) error {
+	return p.execForUser(ctx, `DELETE FROM users WHERE id=$1`, id)
+}
+
+// execForUser runs a single-user statement and reports a missing user.
+func (p *UserRepository) execForUser(ctx context.Context, query string, args ...any) error {
+	res, err := p.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func scanUser(row rowScanner) (model.User, error) {
+	var user model.User
+	var deletionAt sql.NullTime
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role,
+		&user.EmailVerified, &user.Disabled, &user.PasswordResetRequired, &user.CreatedAt, &deletionAt)
+	if deletionAt.Valid {
+		user.DeletionScheduledAt = &deletionAt.Time
+	}
+	return user, err
+}
+
+// escapeLike escapes LIKE wildcards so user input only matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
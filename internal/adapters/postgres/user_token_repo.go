@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type UserTokenRepository struct {
+	db *sql.DB
+}
+
+func NewUserTokenRepository(db *sql.DB) repository.UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+func (p *UserTokenRepository) Create(ctx context.Context, t model.UserToken) error {
+	query := `INSERT INTO user_tokens (user_id, purpose, token_hash, created_at, expires_at, payload)
+			  VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := p.db.ExecContext(ctx, query, t.UserID, t.Purpose, t.TokenHash, t.CreatedAt, t.ExpiresAt, t.Payload)
+	return err
+}
+
+func (p *UserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, at time.Time) (model.UserToken, error) {
+	query := `UPDATE user_tokens SET used_at=$1
+			  WHERE token_hash=$2 AND purpose=$3 AND used_at IS NULL AND expires_at > $1
+			  RETURNING id, user_id, purpose, token_hash, created_at, expires_at, used_at, payload`
+	var t model.UserToken
+	var usedAt sql.NullTime
+	err := p.db.QueryRowContext(ctx, query, at, tokenHash, purpose).Scan(&t.ID, &t.UserID, &t.Purpose, &t.TokenHash, &t.CreatedAt, &t.ExpiresAt, &usedAt, &t.Payload)
+	if err != nil {
+		return model.UserToken{}, err
+	}
+	if usedAt.Valid {
+		t.UsedAt = &usedAt.Time
+	}
+	return t, nil
+}
+
+func (p *UserTokenRepository) InvalidateAll(ctx context.Context, userID uint, purpose string, at time.Time) error {
+	query := `UPDATE user_tokens SET used_at=$1 WHERE user_id=$2 AND purpose=$3 AND used_at IS NULL`
+	_, err := p.db.ExecContext(ctx, query, at, userID, purpose)
+	return err
+}
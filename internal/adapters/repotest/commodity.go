@@ -0,0 +1,177 @@
+package repotest
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// RunCommodityRepositoryTests checks a CommodityRepository. newRepo must return an empty
+// repository.
+func RunCommodityRepositoryTests(t *testing.T, newRepo func(t *testing.T) repository.CommodityRepository) {
+	ctx := context.Background()
+	day := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("UpsertOnNameAndDate", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSavePrice(t, repo, price("gold", day, 1))
+		first, err := repo.GetLatestPrice(ctx, "gold")
+		if err != nil {
+			t.Fatal(err)
+		}
+		mustSavePrice(t, repo, price("gold", day, 2))
+
+		history, err := repo.GetPriceHistory(ctx, "gold", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 1 || history[0].PriceKg != 2 || history[0].ID != first.ID {
+			t.Errorf("expected one replaced price keeping id %d, got %+v", first.ID, history)
+		}
+	})
+
+	t.Run("StoredFields", func(t *testing.T) {
+		repo := newRepo(t)
+		in := price("gold", day, 75000)
+		in.Price = 123
+		mustSavePrice(t, repo, in)
+
+		got, err := repo.GetLatestPrice(ctx, "gold")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID == 0 || got.Name != "gold" || !got.Date.Equal(day) || got.PriceKg != 75000 || got.Unit != "USD/kg" ||
+			!got.FetchedAt.Equal(in.FetchedAt) || got.NativePrice != in.NativePrice || got.NativeUnit != "oz" {
+			t.Errorf("stored price differs: %+v", got)
+		}
+		if got.Price != 0 {
+			t.Errorf("the display price is not stored, got %v", got.Price)
+		}
+	})
+
+	t.Run("HistoryNewestFirst", func(t *testing.T) {
+		repo := newRepo(t)
+		for i, offset := range []int{1, 3, 0, 2} {
+			mustSavePrice(t, repo, price("gold", day.AddDate(0, 0, offset), float64(i)))
+		}
+		mustSavePrice(t, repo, price("silver", day.AddDate(0, 0, 9), 9))
+
+		history, err := repo.GetPriceHistory(ctx, "gold", 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 3 {
+			t.Fatalf("expected the limit to apply, got %d prices", len(history))
+		}
+		for i, want := range []time.Time{day.AddDate(0, 0, 3), day.AddDate(0, 0, 2), day.AddDate(0, 0, 1)} {
+			if !history[i].Date.Equal(want) || history[i].Name != "gold" {
+				t.Errorf("history[%d] = %s %v, want gold %v", i, history[i].Name, history[i].Date, want)
+			}
+		}
+		latest, err := repo.GetLatestPrice(ctx, "gold")
+		if err != nil || !latest.Date.Equal(day.AddDate(0, 0, 3)) {
+			t.Errorf("latest: %+v, %v", latest, err)
+		}
+	})
+
+	t.Run("DailyCloses", func(t *testing.T) {
+		repo := newRepo(t)
+		// Intraday ticks over three days, with a gap day, saved out of order.
+		for _, at := range []struct {
+			offset time.Duration
+			price  float64
+		}{
+			{9 * time.Hour, 1}, {17 * time.Hour, 2}, {12 * time.Hour, 3},
+			{48*time.Hour + 10*time.Hour, 4},
+			{72*time.Hour + 23*time.Hour + 59*time.Minute, 6}, {72*time.Hour + 8*time.Hour, 5},
+		} {
+			mustSavePrice(t, repo, price("gold", day.Add(at.offset), at.price))
+		}
+		mustSavePrice(t, repo, price("silver", day.AddDate(0, 0, 9), 9))
+
+		closes, err := repo.GetDailyCloses(ctx, "gold", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []float64{6, 4, 2}
+		if len(closes) != len(want) {
+			t.Fatalf("expected one close per day with prices, got %+v", closes)
+		}
+		for i, c := range closes {
+			if c.PriceKg != want[i] || c.Name != "gold" {
+				t.Errorf("closes[%d] = %s %v at %v, want gold %v", i, c.Name, c.PriceKg, c.Date, want[i])
+			}
+		}
+		if closes, _ := repo.GetDailyCloses(ctx, "gold", 2); len(closes) != 2 || closes[1].PriceKg != 4 {
+			t.Errorf("the limit counts days: %+v", closes)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.GetLatestPrice(ctx, "gold"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+		if history, err := repo.GetPriceHistory(ctx, "gold", 10); err != nil || len(history) != 0 {
+			t.Errorf("expected no history, got %v, %v", history, err)
+		}
+	})
+
+	t.Run("SaveBatchLaterDuplicateWins", func(t *testing.T) {
+		repo := newRepo(t)
+		mustSavePrice(t, repo, price("gold", day, 1))
+		err := repo.SaveBatch(ctx, []model.Commodity{
+			price("gold", day, 2),
+			price("gold", day.AddDate(0, 0, 1), 3),
+			price("gold", day, 4),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		history, _ := repo.GetPriceHistory(ctx, "gold", 10)
+		if len(history) != 2 || history[0].PriceKg != 3 || history[1].PriceKg != 4 {
+			t.Errorf("unexpected prices after batch: %+v", history)
+		}
+		if err := repo.SaveBatch(ctx, nil); err != nil {
+			t.Errorf("an empty batch should be a no-op: %v", err)
+		}
+	})
+
+	t.Run("HasRecentData", func(t *testing.T) {
+		repo := newRepo(t)
+		if recent, err := repo.HasRecentData(ctx); err != nil || recent {
+			t.Errorf("empty repository: %v, %v", recent, err)
+		}
+		mustSavePrice(t, repo, price("gold", time.Now().UTC().AddDate(0, 0, -5).Truncate(time.Second), 1))
+		if recent, _ := repo.HasRecentData(ctx); recent {
+			t.Error("a price from five days ago is not recent")
+		}
+		mustSavePrice(t, repo, price("gold", time.Now().UTC().Add(-time.Hour).Truncate(time.Second), 1))
+		if recent, _ := repo.HasRecentData(ctx); !recent {
+			t.Error("a price from an hour ago is recent")
+		}
+	})
+}
+
+func price(name string, date time.Time, priceKg float64) model.Commodity {
+	return model.Commodity{
+		Name:        name,
+		Date:        date,
+		PriceKg:     priceKg,
+		Unit:        "USD/kg",
+		FetchedAt:   date.Add(time.Hour),
+		NativePrice: priceKg / 32.1507,
+		NativeUnit:  "oz",
+	}
+}
+
+func mustSavePrice(t *testing.T, repo repository.CommodityRepository, c model.Commodity) {
+	t.Helper()
+	if err := repo.Save(context.Background(), c); err != nil {
+		t.Fatalf("save %s: %v", c.Name, err)
+	}
+}
@@ -0,0 +1,140 @@
+package repotest
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+// RunCorrelationRepositoryTests checks a CorrelationRepository. newRepo must return an empty
+// repository.
+func RunCorrelationRepositoryTests(t *testing.T, newRepo func(t *testing.T) repository.CorrelationRepository) {
+	ctx := context.Background()
+	day := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("LatestAndHistory", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.SaveBatch(ctx, []*model.Correlation{
+			correlation("gold", "silver", day, 0.5),
+			correlation("gold", "silver", day.AddDate(0, 0, 2), 0.7),
+			correlation("gold", "silver", day.AddDate(0, 0, 1), 0.6),
+			correlation("gold", "copper", day.AddDate(0, 0, 5), 0.1),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := repo.GetLatest(ctx, "gold", "silver")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if latest.ID == 0 || latest.CreatedAt.IsZero() || !latest.CorrelationDate.Equal(day.AddDate(0, 0, 2)) ||
+			latest.PearsonR != 0.7 || latest.SpearmanRho != 0.35 || latest.DataPoints != 30 {
+			t.Errorf("unexpected latest correlation: %+v", latest)
+		}
+
+		history, err := repo.GetHistory(ctx, "gold", "silver", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 2 || history[0].PearsonR != 0.7 || history[1].PearsonR != 0.6 {
+			t.Errorf("expected the two newest, newest first, got %+v", history)
+		}
+	})
+
+	t.Run("PairOrderMatters", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Save(ctx, correlation("gold", "silver", day, 0.5)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.GetLatest(ctx, "silver", "gold"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows for the reversed pair, got %v", err)
+		}
+		if history, _ := repo.GetHistory(ctx, "silver", "gold", 10); len(history) != 0 {
+			t.Errorf("expected no history for the reversed pair, got %d", len(history))
+		}
+	})
+
+	t.Run("TopCorrelatedByStrength", func(t *testing.T) {
+		repo := newRepo(t)
+		err := repo.SaveBatch(ctx, []*model.Correlation{
+			correlation("gold", "silver", day, 0.3),
+			correlation("copper", "gold", day, -0.9),
+			correlation("gold", "oil", day, 0.6),
+			correlation("copper", "oil", day, 0.99),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		top, err := repo.GetTopCorrelated(ctx, "gold", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(top) != 2 || top[0].PearsonR != -0.9 || top[1].PearsonR != 0.6 {
+			t.Errorf("expected the strongest pairs involving gold by absolute value, got %+v", top)
+		}
+	})
+
+	t.Run("Events", func(t *testing.T) {
+		repo := newRepo(t)
+		events := []*model.CorrelationEvent{
+			event("gold", "silver", day, "breakdown"),
+			event("gold", "silver", day.AddDate(0, 0, 3), "recovery"),
+			event("copper", "oil", day.AddDate(0, 0, 1), "breakdown"),
+		}
+		if err := repo.SaveEvents(ctx, events); err != nil {
+			t.Fatal(err)
+		}
+		// Saving the same events again stores nothing new.
+		if err := repo.SaveEvents(ctx, events[:1]); err != nil {
+			t.Fatal(err)
+		}
+
+		pair, err := repo.GetEvents(ctx, "silver", "gold", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pair) != 2 || pair[0].Kind != "recovery" || pair[1].Kind != "breakdown" {
+			t.Errorf("expected the pair's events in either order, newest first, got %+v", pair)
+		}
+		if pair[0].ID == 0 || pair[0].Magnitude != 0.4 || pair[0].Window != 30 {
+			t.Errorf("stored event differs: %+v", pair[0])
+		}
+
+		all, err := repo.GetEvents(ctx, "", "", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 2 || !all[0].StartDate.Equal(day.AddDate(0, 0, 3)) || all[1].CommodityA != "copper" {
+			t.Errorf("expected the two newest events of every pair, got %+v", all)
+		}
+	})
+}
+
+func correlation(a, b string, date time.Time, pearson float64) *model.Correlation {
+	return &model.Correlation{
+		CommodityA:      a,
+		CommodityB:      b,
+		CorrelationDate: date,
+		PearsonR:        pearson,
+		SpearmanRho:     pearson / 2,
+		DataPoints:      30,
+	}
+}
+
+func event(a, b string, start time.Time, kind string) *model.CorrelationEvent {
+	return &model.CorrelationEvent{
+		CommodityA: a,
+		CommodityB: b,
+		StartDate:  start,
+		Kind:       kind,
+		Baseline:   0.8,
+		Value:      0.4,
+		Magnitude:  0.4,
+		Window:     30,
+	}
+}
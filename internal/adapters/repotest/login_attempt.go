@@ -0,0 +1,95 @@
+package repotest
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+// RunLoginAttemptRepositoryTests checks a LoginAttemptRepository. newRepo must return an
+// empty repository in which users 1 and 2 exist, for storage that enforces the reference.
+func RunLoginAttemptRepositoryTests(t *testing.T, newRepo func(t *testing.T) repository.LoginAttemptRepository) {
+	ctx := context.Background()
+	start := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+	alice, bob := uint(1), uint(2)
+
+	record := func(t *testing.T, repo repository.LoginAttemptRepository, key, ip, outcome string, userID *uint, at time.Time) {
+		t.Helper()
+		err := repo.Record(ctx, model.LoginAttempt{AccountKey: key, Identifier: key, UserID: userID, IP: ip, UserAgent: "test-agent", Outcome: outcome, CreatedAt: at})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("AccountFailuresSinceLastSuccess", func(t *testing.T) {
+		repo := newRepo(t)
+		record(t, repo, "alice", "10.0.0.1", model.LoginOutcomeInvalidCredentials, &alice, start)
+		record(t, repo, "alice", "10.0.0.1", model.LoginOutcomeSuccess, &alice, start.Add(time.Minute))
+		record(t, repo, "alice", "10.0.0.2", model.LoginOutcomeInvalidCredentials, &alice, start.Add(2*time.Minute))
+		record(t, repo, "alice", "10.0.0.2", model.LoginOutcomeInvalidTwoFactor, &alice, start.Add(3*time.Minute))
+		record(t, repo, "alice", "10.0.0.2", model.LoginOutcomeAccountBlocked, &alice, start.Add(4*time.Minute))
+		record(t, repo, "bob", "10.0.0.2", model.LoginOutcomeInvalidCredentials, &bob, start.Add(5*time.Minute))
+
+		f, err := repo.AccountFailures(ctx, "alice", start.Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Count != 2 || !f.Last.Equal(start.Add(3*time.Minute)) {
+			t.Errorf("expected the 2 failures after the success, got %+v", f)
+		}
+		if f, _ := repo.AccountFailures(ctx, "alice", start.Add(2*time.Minute)); f.Count != 1 {
+			t.Errorf("since bounds the count too, got %+v", f)
+		}
+		if f, _ := repo.AccountFailures(ctx, "nobody", start.Add(-time.Hour)); f.Count != 0 || !f.Last.IsZero() {
+			t.Errorf("unknown account: %+v", f)
+		}
+	})
+
+	t.Run("IPFailuresAcrossAccounts", func(t *testing.T) {
+		repo := newRepo(t)
+		record(t, repo, "alice", "10.0.0.2", model.LoginOutcomeInvalidCredentials, &alice, start)
+		record(t, repo, "alice", "10.0.0.2", model.LoginOutcomeSuccess, &alice, start.Add(time.Minute))
+		record(t, repo, "ghost", "10.0.0.2", model.LoginOutcomeInvalidCredentials, nil, start.Add(2*time.Minute))
+		record(t, repo, "bob", "10.0.0.3", model.LoginOutcomeInvalidCredentials, &bob, start.Add(3*time.Minute))
+
+		f, err := repo.IPFailures(ctx, "10.0.0.2", start.Add(-time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.Count != 2 || !f.Last.Equal(start.Add(2*time.Minute)) {
+			t.Errorf("a success does not reset the address count, got %+v", f)
+		}
+		if f, _ := repo.IPFailures(ctx, "10.0.0.2", start); f.Count != 1 {
+			t.Errorf("only attempts after since count, got %+v", f)
+		}
+	})
+
+	t.Run("ListAndDeleteByUser", func(t *testing.T) {
+		repo := newRepo(t)
+		record(t, repo, "alice", "10.0.0.1", model.LoginOutcomeInvalidCredentials, &alice, start)
+		record(t, repo, "alice", "10.0.0.1", model.LoginOutcomeSuccess, &alice, start.Add(time.Minute))
+		record(t, repo, "bob", "10.0.0.1", model.LoginOutcomeSuccess, &bob, start)
+
+		attempts, err := repo.ListByUser(ctx, alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(attempts) != 2 || attempts[0].Outcome != model.LoginOutcomeSuccess || attempts[0].ID == 0 ||
+			attempts[0].UserID == nil || *attempts[0].UserID != alice || attempts[0].IP != "10.0.0.1" ||
+			attempts[0].UserAgent != "test-agent" || !attempts[0].CreatedAt.Equal(start.Add(time.Minute)) {
+			t.Errorf("expected alice's attempts newest first, got %+v", attempts)
+		}
+
+		if err := repo.DeleteByUser(ctx, alice); err != nil {
+			t.Fatal(err)
+		}
+		if attempts, _ := repo.ListByUser(ctx, alice); len(attempts) != 0 {
+			t.Errorf("expected alice's attempts to be gone, got %+v", attempts)
+		}
+		if attempts, _ := repo.ListByUser(ctx, bob); len(attempts) != 1 {
+			t.Errorf("other users are untouched, got %+v", attempts)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package repotest
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// RunSessionRepositoryTests checks a SessionRepository. newRepo must return an empty
+// repository in which users 1 and 2 exist, for storage that enforces the reference.
+func RunSessionRepositoryTests(t *testing.T, newRepo func(t *testing.T) repository.SessionRepository) {
+	ctx := context.Background()
+	start := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+
+	t.Run("CreateFindAndTouch", func(t *testing.T) {
+		repo := newRepo(t)
+		in := session("s1", 1, start, start.Add(time.Hour))
+		if err := repo.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Create(ctx, in); err == nil {
+			t.Error("expected a duplicate session id to be rejected")
+		}
+
+		got, err := repo.FindByID(ctx, "s1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.UserID != 1 || got.Device != "laptop" || got.IP != "10.0.0.1" || got.UserAgent != "test-agent" ||
+			!got.CreatedAt.Equal(start) || !got.LastSeenAt.Equal(start) || !got.ExpiresAt.Equal(start.Add(time.Hour)) || got.RevokedAt != nil {
+			t.Errorf("unexpected session: %+v", got)
+		}
+		if _, err := repo.FindByID(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("missing session: expected sql.ErrNoRows, got %v", err)
+		}
+
+		seen := start.Add(10 * time.Minute)
+		if err := repo.Touch(ctx, "s1", seen, "10.0.0.2", start.Add(30*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		got, _ = repo.FindByID(ctx, "s1")
+		if !got.LastSeenAt.Equal(seen) || got.IP != "10.0.0.2" || !got.ExpiresAt.Equal(start.Add(time.Hour)) {
+			t.Errorf("touch must record activity without shortening the expiry: %+v", got)
+		}
+		if err := repo.Touch(ctx, "s1", seen, "10.0.0.2", start.Add(2*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ = repo.FindByID(ctx, "s1"); !got.ExpiresAt.Equal(start.Add(2 * time.Hour)) {
+			t.Errorf("touch should extend the expiry, got %v", got.ExpiresAt)
+		}
+	})
+
+	t.Run("ListActiveAndRevoke", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, s := range []model.Session{
+			session("old", 1, start, start.Add(time.Hour)),
+			session("new", 1, start.Add(time.Minute), start.Add(time.Hour)),
+			session("expired", 1, start, start),
+			session("other", 1, start, start.Add(time.Hour)),
+			session("bob", 2, start, start.Add(time.Hour)),
+		} {
+			if err := repo.Create(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := repo.Revoke(ctx, "other", start); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := repo.FindByID(ctx, "other"); got.RevokedAt == nil || !got.RevokedAt.Equal(start) {
+			t.Errorf("revocation not stored: %+v", got)
+		}
+
+		active, err := repo.ListActive(ctx, 1, start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := sessionIDs(active); !slices.Equal(got, []string{"new", "old"}) {
+			t.Errorf("expected the live sessions most recently seen first, got %v", got)
+		}
+
+		revoked, err := repo.RevokeAllExcept(ctx, 1, "new", start.Add(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(revoked)
+		if !slices.Equal(revoked, []string{"expired", "old"}) {
+			t.Errorf("expected the other unrevoked sessions to be revoked, got %v", revoked)
+		}
+		if active, _ := repo.ListActive(ctx, 1, start); !slices.Equal(sessionIDs(active), []string{"new"}) {
+			t.Errorf("only the kept session should stay active, got %v", sessionIDs(active))
+		}
+		if active, _ := repo.ListActive(ctx, 2, start); !slices.Equal(sessionIDs(active), []string{"bob"}) {
+			t.Errorf("other users are untouched, got %v", sessionIDs(active))
+		}
+	})
+
+	t.Run("RefreshTokensRotateOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(ctx, session("s1", 1, start, start.Add(time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+		in := model.RefreshToken{ID: "r1", SessionID: "s1", TokenHash: "hash-1", CreatedAt: start, ExpiresAt: start.Add(time.Hour)}
+		if err := repo.CreateRefreshToken(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := repo.FindRefreshToken(ctx, "hash-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != "r1" || got.SessionID != "s1" || !got.CreatedAt.Equal(start) || !got.ExpiresAt.Equal(start.Add(time.Hour)) || got.RotatedAt != nil {
+			t.Errorf("unexpected refresh token: %+v", got)
+		}
+		if _, err := repo.FindRefreshToken(ctx, "missing"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("missing token: expected sql.ErrNoRows, got %v", err)
+		}
+
+		if err := repo.MarkRefreshTokenRotated(ctx, "r1", start.Add(time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.MarkRefreshTokenRotated(ctx, "r1", start.Add(2*time.Minute)); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("a second rotation must fail with sql.ErrNoRows, got %v", err)
+		}
+		if got, _ := repo.FindRefreshToken(ctx, "hash-1"); got.RotatedAt == nil || !got.RotatedAt.Equal(start.Add(time.Minute)) {
+			t.Errorf("rotation time not stored: %+v", got)
+		}
+	})
+}
+
+// RunUserTokenRepositoryTests checks a UserTokenRepository. newRepo must return an empty
+// repository in which users 1 and 2 exist, for storage that enforces the reference.
+func RunUserTokenRepositoryTests(t *testing.T, newRepo func(t *testing.T) repository.UserTokenRepository) {
+	ctx := context.Background()
+	start := time.Date(2026, time.May, 4, 9, 0, 0, 0, time.UTC)
+
+	t.Run("ConsumeOnce", func(t *testing.T) {
+		repo := newRepo(t)
+		in := userToken(1, model.TokenPurposeEmailChange, "hash-1", start)
+		in.Payload = "new@example.com"
+		if err := repo.Create(ctx, in); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.Create(ctx, userToken(2, model.TokenPurposePasswordReset, "hash-1", start)); err == nil {
+			t.Error("expected a duplicate token hash to be rejected")
+		}
+
+		if _, err := repo.Consume(ctx, model.TokenPurposePasswordReset, "hash-1", start); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("another purpose: expected sql.ErrNoRows, got %v", err)
+		}
+		if _, err := repo.Consume(ctx, model.TokenPurposeEmailChange, "hash-1", start.Add(time.Hour)); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expired token: expected sql.ErrNoRows, got %v", err)
+		}
+
+		at := start.Add(time.Minute)
+		got, err := repo.Consume(ctx, model.TokenPurposeEmailChange, "hash-1", at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID == 0 || got.UserID != 1 || got.Payload != "new@example.com" || !got.ExpiresAt.Equal(start.Add(time.Hour)) ||
+			got.UsedAt == nil || !got.UsedAt.Equal(at) {
+			t.Errorf("unexpected consumed token: %+v", got)
+		}
+		if _, err := repo.Consume(ctx, model.TokenPurposeEmailChange, "hash-1", at); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("a used token: expected sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("InvalidateAll", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, tok := range []model.UserToken{
+			userToken(1, model.TokenPurposePasswordReset, "reset-1", start),
+			userToken(1, model.TokenPurposePasswordReset, "reset-2", start),
+			userToken(1, model.TokenPurposeEmailVerification, "verify-1", start),
+			userToken(2, model.TokenPurposePasswordReset, "reset-bob", start),
+		} {
+			if err := repo.Create(ctx, tok); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := repo.InvalidateAll(ctx, 1, model.TokenPurposePasswordReset, start); err != nil {
+			t.Fatal(err)
+		}
+
+		for hash, purpose := range map[string]string{"reset-1": model.TokenPurposePasswordReset, "reset-2": model.TokenPurposePasswordReset} {
+			if _, err := repo.Consume(ctx, purpose, hash, start); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("%s: expected an invalidated token, got %v", hash, err)
+			}
+		}
+		if _, err := repo.Consume(ctx, model.TokenPurposeEmailVerification, "verify-1", start); err != nil {
+			t.Errorf("other purposes are untouched: %v", err)
+		}
+		if _, err := repo.Consume(ctx, model.TokenPurposePasswordReset, "reset-bob", start); err != nil {
+			t.Errorf("other users are untouched: %v", err)
+		}
+	})
+}
+
+func session(id string, userID uint, seen, expires time.Time) model.Session {
+	return model.Session{
+		ID: id, UserID: userID, Device: "laptop", IP: "10.0.0.1", UserAgent: "test-agent",
+		CreatedAt: seen, LastSeenAt: seen, ExpiresAt: expires,
+	}
+}
+
+func sessionIDs(sessions []model.Session) []string {
+	out := make([]string, len(sessions))
+	for i, s := range sessions {
+		out[i] = s.ID
+	}
+	return out
+}
+
+func userToken(userID uint, purpose, hash string, created time.Time) model.UserToken {
+	return model.UserToken{UserID: userID, Purpose: purpose, TokenHash: hash, CreatedAt: created, ExpiresAt: created.Add(time.Hour)}
+}
@@ -0,0 +1,231 @@
+// Package repotest holds the conformance tests every storage adapter runs, so the
+// adapters keep the same semantics: upserts, ordering, paging and not-found errors.
+package repotest
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"testing"
+	"time"
+)
+
+// RunUserRepositoryTests checks a UserRepository. newRepo must return an empty repository.
+func RunUserRepositoryTests(t *testing.T, newRepo func(t *testing.T) repository.UserRepository) {
+	ctx := context.Background()
+
+	t.Run("SaveAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := saveUser(t, repo, "alice")
+
+		if alice.ID == 0 || alice.CreatedAt.IsZero() {
+			t.Errorf("expected an id and creation time, got %+v", alice)
+		}
+		if alice.Role != model.RoleUser || alice.EmailVerified || alice.Disabled || alice.DeletionScheduledAt != nil {
+			t.Errorf("unexpected defaults: %+v", alice)
+		}
+		byEmail, err := repo.FindByUsernameOrEmail(ctx, "alice@example.com")
+		if err != nil || byEmail.ID != alice.ID {
+			t.Errorf("find by email: %+v, %v", byEmail, err)
+		}
+		byID, err := repo.FindByID(ctx, alice.ID)
+		if err != nil || byID.Username != "alice" || byID.Password != "hash-alice" {
+			t.Errorf("find by id: %+v, %v", byID, err)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		const missing = 4242
+
+		if _, err := repo.FindByID(ctx, missing); !isUserNotFound(err) {
+			t.Errorf("FindByID: expected user not found, got %v", err)
+		}
+		if _, err := repo.FindByUsernameOrEmail(ctx, "nobody"); !isUserNotFound(err) {
+			t.Errorf("FindByUsernameOrEmail: expected user not found, got %v", err)
+		}
+		for name, err := range map[string]error{
+			"UpdateRole":           repo.UpdateRole(ctx, missing, model.RoleAdmin),
+			"SetDisabled":          repo.SetDisabled(ctx, missing, true),
+			"RequirePasswordReset": repo.RequirePasswordReset(ctx, missing),
+			"UpdateEmail":          repo.UpdateEmail(ctx, missing, "x@example.com", true),
+			"UpdateUsername":       repo.UpdateUsername(ctx, missing, "x"),
+			"ScheduleDeletion":     repo.ScheduleDeletion(ctx, missing, nil),
+			"Delete":               repo.Delete(ctx, missing),
+		} {
+			if !isUserNotFound(err) {
+				t.Errorf("%s: expected user not found, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("UniqueUsernameAndEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := saveUser(t, repo, "alice")
+		bob := saveUser(t, repo, "bob")
+
+		if err := repo.Save(ctx, model.User{Username: "alice", Email: "other@example.com", Password: "x", Role: model.RoleUser}); err == nil {
+			t.Error("expected a duplicate username to be rejected")
+		}
+		if err := repo.Save(ctx, model.User{Username: "other", Email: "alice@example.com", Password: "x", Role: model.RoleUser}); err == nil {
+			t.Error("expected a duplicate email to be rejected")
+		}
+		if err := repo.UpdateEmail(ctx, bob.ID, "alice@example.com", false); err == nil {
+			t.Error("expected taking another user's email to be rejected")
+		}
+		if err := repo.UpdateUsername(ctx, bob.ID, "alice"); err == nil {
+			t.Error("expected taking another user's username to be rejected")
+		}
+		if err := repo.UpdateUsername(ctx, alice.ID, "alice"); err != nil {
+			t.Errorf("keeping one's own username should work: %v", err)
+		}
+	})
+
+	t.Run("Updates", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := saveUser(t, repo, "alice")
+
+		steps := []struct {
+			name string
+			err  error
+		}{
+			{"RequirePasswordReset", repo.RequirePasswordReset(ctx, alice.ID)},
+			{"UpdateRole", repo.UpdateRole(ctx, alice.ID, model.RoleAdmin)},
+			{"SetDisabled", repo.SetDisabled(ctx, alice.ID, true)},
+			{"UpdateEmail", repo.UpdateEmail(ctx, alice.ID, "alice@example.org", false)},
+			{"MarkEmailVerified", repo.MarkEmailVerified(ctx, alice.ID)},
+			{"UpdateUsername", repo.UpdateUsername(ctx, alice.ID, "alice2")},
+		}
+		for _, s := range steps {
+			if s.err != nil {
+				t.Fatalf("%s: %v", s.name, s.err)
+			}
+		}
+		got, err := repo.FindByID(ctx, alice.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.PasswordResetRequired || got.Role != model.RoleAdmin || !got.Disabled ||
+			got.Email != "alice@example.org" || !got.EmailVerified || got.Username != "alice2" {
+			t.Errorf("updates not applied: %+v", got)
+		}
+
+		// A new password clears the forced reset.
+		if err := repo.UpdatePassword(ctx, alice.ID, "new-hash"); err != nil {
+			t.Fatal(err)
+		}
+		got, _ = repo.FindByID(ctx, alice.ID)
+		if got.Password != "new-hash" || got.PasswordResetRequired {
+			t.Errorf("UpdatePassword: %+v", got)
+		}
+	})
+
+	t.Run("ListFiltersAndPages", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := saveUser(t, repo, "alice")
+		bob := saveUser(t, repo, "bob")
+		carol := saveUser(t, repo, "carol")
+		if err := repo.UpdateRole(ctx, bob.ID, model.RoleAdmin); err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.SetDisabled(ctx, carol.ID, true); err != nil {
+			t.Fatal(err)
+		}
+
+		users, total, err := repo.List(ctx, repository.UserFilter{Limit: 2})
+		if err != nil || total != 3 || len(users) != 2 || users[0].ID != alice.ID || users[1].ID != bob.ID {
+			t.Errorf("first page: %v users, total %d, %v", ids(users), total, err)
+		}
+		users, total, _ = repo.List(ctx, repository.UserFilter{Limit: 2, Offset: 2})
+		if total != 3 || len(users) != 1 || users[0].ID != carol.ID {
+			t.Errorf("second page: %v, total %d", ids(users), total)
+		}
+		users, total, _ = repo.List(ctx, repository.UserFilter{Query: "AL", Limit: 10})
+		if total != 1 || len(users) != 1 || users[0].ID != alice.ID {
+			t.Errorf("query is a case-insensitive substring: %v, total %d", ids(users), total)
+		}
+		users, _, _ = repo.List(ctx, repository.UserFilter{Role: model.RoleAdmin, Limit: 10})
+		if len(users) != 1 || users[0].ID != bob.ID {
+			t.Errorf("role filter: %v", ids(users))
+		}
+		disabled := true
+		users, _, _ = repo.List(ctx, repository.UserFilter{Disabled: &disabled, Limit: 10})
+		if len(users) != 1 || users[0].ID != carol.ID {
+			t.Errorf("disabled filter: %v", ids(users))
+		}
+		users, total, _ = repo.List(ctx, repository.UserFilter{Query: "100%", Limit: 10})
+		if total != 0 || len(users) != 0 {
+			t.Errorf("wildcards in the query should match literally: %v", ids(users))
+		}
+	})
+
+	t.Run("ScheduledDeletion", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := saveUser(t, repo, "alice")
+		bob := saveUser(t, repo, "bob")
+		at := time.Date(2026, time.November, 1, 12, 0, 0, 0, time.UTC)
+
+		for _, id := range []uint{bob.ID, alice.ID} {
+			if err := repo.ScheduleDeletion(ctx, id, &at); err != nil {
+				t.Fatal(err)
+			}
+		}
+		got, _ := repo.FindByID(ctx, alice.ID)
+		if got.DeletionScheduledAt == nil || !got.DeletionScheduledAt.Equal(at) {
+			t.Errorf("deletion date not stored: %v", got.DeletionScheduledAt)
+		}
+		if due, _ := repo.ListDueForDeletion(ctx, at.Add(-time.Second)); len(due) != 0 {
+			t.Errorf("nothing is due before the date, got %v", due)
+		}
+		if due, _ := repo.ListDueForDeletion(ctx, at); len(due) != 2 || due[0] != alice.ID || due[1] != bob.ID {
+			t.Errorf("expected both accounts due in id order, got %v", due)
+		}
+
+		if err := repo.ScheduleDeletion(ctx, alice.ID, nil); err != nil {
+			t.Fatal(err)
+		}
+		if due, _ := repo.ListDueForDeletion(ctx, at); len(due) != 1 || due[0] != bob.ID {
+			t.Errorf("a cancelled deletion is not due, got %v", due)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		alice := saveUser(t, repo, "alice")
+
+		if err := repo.Delete(ctx, alice.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.FindByID(ctx, alice.ID); !isUserNotFound(err) {
+			t.Errorf("expected the user to be gone, got %v", err)
+		}
+	})
+}
+
+func saveUser(t *testing.T, repo repository.UserRepository, name string) model.User {
+	t.Helper()
+	ctx := context.Background()
+	err := repo.Save(ctx, model.User{Username: name, Email: name + "@example.com", Password: "hash-" + name, Role: model.RoleUser})
+	if err != nil {
+		t.Fatalf("save %s: %v", name, err)
+	}
+	user, err := repo.FindByUsernameOrEmail(ctx, name)
+	if err != nil {
+		t.Fatalf("find %s: %v", name, err)
+	}
+	return user
+}
+
+// isUserNotFound matches the error user repositories return for a missing user, which
+// callers compare by message.
+func isUserNotFound(err error) bool {
+	return err != nil && err.Error() == "user not found"
+}
+
+func ids(users []model.User) []uint {
+	out := make([]uint, len(users))
+	for i, u := range users {
+		out[i] = u.ID
+	}
+	return out
+}
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+const commodityColumns = `id, name, date, price_kg, unit, fetched_at, native_price, native_unit`
+
+const upsertCommodity = `INSERT INTO commodities (name, date, price_kg, unit, fetched_at, native_price, native_unit)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT (name, date)
+			  DO UPDATE SET
+			  	price_kg = excluded.price_kg,
+			  	unit = excluded.unit,
+			  	fetched_at = excluded.fetched_at,
+			  	native_price = excluded.native_price,
+			  	native_unit = excluded.native_unit`
+
+type CommodityRepository struct {
+	db *sql.DB
+}
+
+func NewCommodityRepository(db *sql.DB) repository.CommodityRepository {
+	return &CommodityRepository{db: db}
+}
+
+func (s *CommodityRepository) Save(ctx context.Context, stock model.Commodity) error {
+	_, err := s.db.ExecContext(ctx, upsertCommodity, commodityArgs(stock)...)
+	return err
+}
+
+// SaveBatch upserts the prices one by one in a single transaction, which SQLite writes
+// in one go. Later duplicates simply overwrite earlier ones.
+func (s *CommodityRepository) SaveBatch(ctx context.Context, stocks []model.Commodity) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, upsertCommodity)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, c := range stocks {
+		if _, err := stmt.ExecContext(ctx, commodityArgs(c)...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *CommodityRepository) GetLatestPrice(ctx context.Context, commodity string) (model.Commodity, error) {
+	query := `SELECT ` + commodityColumns + ` FROM commodities WHERE name=? ORDER BY date DESC LIMIT 1`
+	return scanCommodity(s.db.QueryRowContext(ctx, query, commodity))
+}
+
+func (s *CommodityRepository) GetPriceHistory(ctx context.Context, commodity string, limit int) ([]model.Commodity, error) {
+	query := `SELECT ` + commodityColumns + ` FROM commodities WHERE name=? ORDER BY date DESC LIMIT ?`
+	rows, err := s.db.QueryContext(ctx, query, commodity, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []model.Commodity
+	for rows.Next() {
+		c, err := scanCommodity(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// GetDailyCloses groups on the date part of the stored text, which is the UTC day.
+func (s *CommodityRepository) GetDailyCloses(ctx context.Context, commodity string, days int) ([]model.Commodity, error) {
+	query := `SELECT ` + commodityColumns + ` FROM (
+			  	SELECT ` + commodityColumns + `, ROW_NUMBER() OVER (PARTITION BY substr(date, 1, 10) ORDER BY date DESC) AS rn
+			  	FROM commodities WHERE name=?
+			  ) WHERE rn = 1 ORDER BY date DESC LIMIT ?`
+	rows, err := s.db.QueryContext(ctx, query, commodity, days)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var closes []model.Commodity
+	for rows.Next() {
+		c, err := scanCommodity(rows)
+		if err != nil {
+			return nil, err
+		}
+		closes = append(closes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return closes, nil
+}
+
+func (s *CommodityRepository) HasRecentData(ctx context.Context) (bool, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM commodities WHERE date > ?`, formatTime(now().Add(-48*time.Hour))).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func commodityArgs(c model.Commodity) []any {
+	return []any{c.Name, formatTime(c.Date), c.PriceKg, c.Unit, formatTime(c.FetchedAt),
+		nullFloat(c.NativePrice), nullString(c.NativeUnit)}
+}
+
+func scanCommodity(row rowScanner) (model.Commodity, error) {
+	var c model.Commodity
+	var nativePrice sql.NullFloat64
+	var nativeUnit sql.NullString
+	err := row.Scan(&c.ID, &c.Name, timeColumn{&c.Date}, &c.PriceKg, &c.Unit, timeColumn{&c.FetchedAt}, &nativePrice, &nativeUnit)
+	if err != nil {
+		return model.Commodity{}, err
+	}
+	c.NativePrice = nativePrice.Float64
+	c.NativeUnit = nativeUnit.String
+	return c, nil
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"backend/internal/adapters/repotest"
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+)
+
+// testDB returns a migrated in-memory database.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestUserRepositoryConformance(t *testing.T) {
+	repotest.RunUserRepositoryTests(t, func(t *testing.T) repository.UserRepository {
+		return NewUserRepository(testDB(t))
+	})
+}
+
+func TestCommodityRepositoryConformance(t *testing.T) {
+	repotest.RunCommodityRepositoryTests(t, func(t *testing.T) repository.CommodityRepository {
+		return NewCommodityRepository(testDB(t))
+	})
+}
+
+func TestCorrelationRepositoryConformance(t *testing.T) {
+	repotest.RunCorrelationRepositoryTests(t, func(t *testing.T) repository.CorrelationRepository {
+		return NewCorrelationRepository(testDB(t))
+	})
+}
+
+func TestLoginAttemptRepositoryConformance(t *testing.T) {
+	repotest.RunLoginAttemptRepositoryTests(t, func(t *testing.T) repository.LoginAttemptRepository {
+		db := testDB(t)
+		users := NewUserRepository(db)
+		for _, name := range []string{"alice", "bob"} {
+			if err := users.Save(context.Background(), model.User{Username: name, Email: name + "@example.com", Password: "x", Role: model.RoleUser}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return NewLoginAttemptRepository(db)
+	})
+}
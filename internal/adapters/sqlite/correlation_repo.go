@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+)
+
+const correlationColumns = `id, commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints, createdAt`
+
+type CorrelationRepository struct {
+	db *sql.DB
+}
+
+func NewCorrelationRepository(db *sql.DB) repository.CorrelationRepository {
+	return &CorrelationRepository{db}
+}
+
+func (s *CorrelationRepository) Save(ctx context.Context, correlation *model.Correlation) error {
+	return s.SaveBatch(ctx, []*model.Correlation{correlation})
+}
+
+func (s *CorrelationRepository) SaveBatch(ctx context.Context, correlations []*model.Correlation) error {
+	if len(correlations) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO correlations(commodity_a, commodity_b, correlationDate, pearsonR, spearmanRho, dataPoints, createdAt)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	createdAt := formatTime(now())
+	for _, c := range correlations {
+		if _, err := stmt.ExecContext(ctx, c.CommodityA, c.CommodityB, formatTime(c.CorrelationDate), c.PearsonR, c.SpearmanRho, c.DataPoints, createdAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *CorrelationRepository) GetLatest(ctx context.Context, commodityA, commodityB string) (*model.Correlation, error) {
+	query := `SELECT ` + correlationColumns + ` FROM correlations WHERE commodity_a=? AND commodity_b=? ORDER BY correlationDate DESC LIMIT 1`
+	return scanCorrelation(s.db.QueryRowContext(ctx, query, commodityA, commodityB))
+}
+
+func (s *CorrelationRepository) GetHistory(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.Correlation, error) {
+	query := `SELECT ` + correlationColumns + ` FROM correlations WHERE commodity_a=? AND commodity_b=? ORDER BY correlationDate DESC LIMIT ?`
+	return s.queryCorrelations(ctx, query, commodityA, commodityB, limit)
+}
+
+func (s *CorrelationRepository) GetTopCorrelated(ctx context.Context, commodity string, limit int) ([]*model.Correlation, error) {
+	query := `SELECT ` + correlationColumns + ` FROM correlations WHERE commodity_a=? OR commodity_b=? ORDER BY ABS(pearsonR) DESC LIMIT ?`
+	return s.queryCorrelations(ctx, query, commodity, commodity, limit)
+}
+
+// SaveEvents stores detected regime events, skipping the ones already recorded.
+func (s *CorrelationRepository) SaveEvents(ctx context.Context, events []*model.CorrelationEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT INTO correlation_events(commodity_a, commodity_b, start_date, kind, baseline, value, magnitude, window_size, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+			  ON CONFLICT (commodity_a, commodity_b, start_date, kind) DO NOTHING`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	createdAt := formatTime(now())
+	for _, e := range events {
+		if _, err := stmt.ExecContext(ctx, e.CommodityA, e.CommodityB, formatTime(e.StartDate), e.Kind,
+			e.Baseline, e.Value, e.Magnitude, e.Window, createdAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetEvents returns the most recent regime events, newest first, for one pair in either
+// order or, without commodityA, for every pair.
+func (s *CorrelationRepository) GetEvents(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.CorrelationEvent, error) {
+	query := `SELECT id, commodity_a, commodity_b, start_date, kind, baseline, value, magnitude, window_size, created_at
+			  FROM correlation_events
+			  WHERE ? = '' OR (commodity_a=? AND commodity_b=?) OR (commodity_a=? AND commodity_b=?)
+			  ORDER BY start_date DESC LIMIT ?`
+	rows, err := s.db.QueryContext(ctx, query, commodityA, commodityA, commodityB, commodityB, commodityA, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*model.CorrelationEvent
+	for rows.Next() {
+		var e model.CorrelationEvent
+		err := rows.Scan(&e.ID, &e.CommodityA, &e.CommodityB, timeColumn{&e.StartDate}, &e.Kind,
+			&e.Baseline, &e.Value, &e.Magnitude, &e.Window, timeColumn{&e.CreatedAt})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+func (s *CorrelationRepository) queryCorrelations(ctx context.Context, query string, args ...any) ([]*model.Correlation, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var correlations []*model.Correlation
+	for rows.Next() {
+		c, err := scanCorrelation(rows)
+		if err != nil {
+			return nil, err
+		}
+		correlations = append(correlations, c)
+	}
+	return correlations, rows.Err()
+}
+
+func scanCorrelation(row rowScanner) (*model.Correlation, error) {
+	var c model.Correlation
+	err := row.Scan(&c.ID, &c.CommodityA, &c.CommodityB, timeColumn{&c.CorrelationDate},
+		&c.PearsonR, &c.SpearmanRho, &c.DataPoints, timeColumn{&c.CreatedAt})
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
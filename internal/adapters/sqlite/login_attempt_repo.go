@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// failedLoginOutcomes are the outcomes that count towards throttling and lockout.
+const failedLoginOutcomes = `('` + model.LoginOutcomeInvalidCredentials + `', '` + model.LoginOutcomeInvalidTwoFactor + `')`
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptRepository(db *sql.DB) repository.LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+func (s *LoginAttemptRepository) Record(ctx context.Context, a model.LoginAttempt) error {
+	query := `INSERT INTO login_attempts (account_key, identifier, user_id, ip, user_agent, outcome, created_at)
+			  VALUES (?, ?, ?, ?, ?, ?, ?)`
+	var userID sql.NullInt64
+	if a.UserID != nil {
+		userID = sql.NullInt64{Int64: int64(*a.UserID), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, query, a.AccountKey, a.Identifier, userID, a.IP, a.UserAgent, a.Outcome, formatTime(a.CreatedAt))
+	return err
+}
+
+func (s *LoginAttemptRepository) AccountFailures(ctx context.Context, accountKey string, since time.Time) (repository.AttemptFailures, error) {
+	// Stored times are fixed-width text, so MAX picks the latest one.
+	query := `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+			  WHERE account_key=? AND outcome IN ` + failedLoginOutcomes + `
+			  AND created_at > MAX(?, COALESCE(
+				(SELECT MAX(created_at) FROM login_attempts WHERE account_key=? AND outcome=?), ?))`
+	sinceText := formatTime(since)
+	return scanFailures(s.db.QueryRowContext(ctx, query, accountKey, sinceText, accountKey, model.LoginOutcomeSuccess, sinceText))
+}
+
+func (s *LoginAttemptRepository) IPFailures(ctx context.Context, ip string, since time.Time) (repository.AttemptFailures, error) {
+	query := `SELECT COUNT(*), MAX(created_at) FROM login_attempts
+			  WHERE ip=? AND outcome IN ` + failedLoginOutcomes + ` AND created_at > ?`
+	return scanFailures(s.db.QueryRowContext(ctx, query, ip, formatTime(since)))
+}
+
+func (s *LoginAttemptRepository) ListByUser(ctx context.Context, userID uint) ([]model.LoginAttempt, error) {
+	query := `SELECT id, account_key, identifier, user_id, ip, user_agent, outcome, created_at
+			  FROM login_attempts WHERE user_id=? ORDER BY created_at DESC, id DESC`
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attempts []model.LoginAttempt
+	for rows.Next() {
+		var a model.LoginAttempt
+		var uid sql.NullInt64
+		if err := rows.Scan(&a.ID, &a.AccountKey, &a.Identifier, &uid, &a.IP, &a.UserAgent, &a.Outcome, timeColumn{&a.CreatedAt}); err != nil {
+			return nil, err
+		}
+		if uid.Valid {
+			id := uint(uid.Int64)
+			a.UserID = &id
+		}
+		attempts = append(attempts, a)
+	}
+	return attempts, rows.Err()
+}
+
+// DeleteByUser erases the attempts attributed to a user. Deleting the user alone would
+// keep them with user_id cleared.
+func (s *LoginAttemptRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM login_attempts WHERE user_id=?`, userID)
+	return err
+}
+
+func scanFailures(row rowScanner) (repository.AttemptFailures, error) {
+	var f repository.AttemptFailures
+	var last *time.Time
+	if err := row.Scan(&f.Count, nullTimeColumn{&last}); err != nil {
+		return repository.AttemptFailures{}, err
+	}
+	if last != nil {
+		f.Last = *last
+	}
+	return f, nil
+}
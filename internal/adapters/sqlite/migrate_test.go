@@ -0,0 +1,43 @@
+package sqlite
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles, "migrations")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 {
+		t.Fatalf("migrations should start at version 1, got %+v", migrations)
+	}
+}
+
+func TestLoadMigrationsSortsAndRejectsBadSets(t *testing.T) {
+	migrations, err := loadMigrations(fstest.MapFS{
+		"m/0002_add_index.sql": {Data: []byte("CREATE INDEX i ON t(a);")},
+		"m/0001_create.sql":    {Data: []byte("CREATE TABLE t (a INT);")},
+	}, "m")
+	if err != nil {
+		t.Fatalf("loadMigrations: %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Name != "create" || migrations[1].Version != 2 {
+		t.Errorf("unexpected migrations: %+v", migrations)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"bad name": {
+			"m/create.sql": {Data: []byte("CREATE TABLE t (a INT);")},
+		},
+		"same version twice": {
+			"m/0001_create.sql": {Data: []byte("CREATE TABLE t (a INT);")},
+			"m/0001_other.sql":  {Data: []byte("CREATE TABLE u (a INT);")},
+		},
+	} {
+		if _, err := loadMigrations(fsys, "m"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
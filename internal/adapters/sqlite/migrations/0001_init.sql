@@ -0,0 +1,54 @@
+-- Tables of the SQLite adapter: the equivalent of the Postgres users, commodities,
+-- correlations and correlation_events. Times are UTC text written by the adapter.
+
+CREATE TABLE users (
+	id                      INTEGER PRIMARY KEY AUTOINCREMENT,
+	username                TEXT NOT NULL UNIQUE,
+	email                   TEXT NOT NULL UNIQUE,
+	password                TEXT NOT NULL,
+	role                    TEXT NOT NULL DEFAULT 'user',
+	email_verified          INTEGER NOT NULL DEFAULT 0,
+	disabled                INTEGER NOT NULL DEFAULT 0,
+	password_reset_required INTEGER NOT NULL DEFAULT 0,
+	created_at              TEXT NOT NULL,
+	deletion_scheduled_at   TEXT
+);
+
+CREATE TABLE commodities (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	name         TEXT NOT NULL,
+	date         TEXT NOT NULL,
+	price_kg     REAL,
+	unit         TEXT NOT NULL,
+	fetched_at   TEXT NOT NULL,
+	native_price REAL,
+	native_unit  TEXT,
+	UNIQUE(name, date)
+);
+CREATE INDEX commodities_date_idx ON commodities(date);
+
+CREATE TABLE correlations (
+	id              INTEGER PRIMARY KEY AUTOINCREMENT,
+	commodity_a     TEXT NOT NULL,
+	commodity_b     TEXT NOT NULL,
+	correlationDate TEXT NOT NULL,
+	pearsonR        REAL,
+	spearmanRho     REAL,
+	dataPoints      INTEGER NOT NULL,
+	createdAt       TEXT NOT NULL
+);
+CREATE INDEX correlations_pair_date_idx ON correlations(commodity_a, commodity_b, correlationDate);
+
+CREATE TABLE correlation_events (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	commodity_a TEXT NOT NULL,
+	commodity_b TEXT NOT NULL,
+	start_date  TEXT NOT NULL,
+	kind        TEXT NOT NULL,
+	baseline    REAL NOT NULL,
+	value       REAL NOT NULL,
+	magnitude   REAL NOT NULL,
+	window_size INTEGER NOT NULL,
+	created_at  TEXT NOT NULL,
+	UNIQUE(commodity_a, commodity_b, start_date, kind)
+);
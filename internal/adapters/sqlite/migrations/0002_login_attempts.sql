@@ -0,0 +1,15 @@
+-- Login attempts, for brute-force throttling and lockout, as in Postgres.
+
+CREATE TABLE login_attempts (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	account_key TEXT NOT NULL,
+	identifier  TEXT NOT NULL,
+	user_id     INTEGER REFERENCES users(id) ON DELETE SET NULL,
+	ip          TEXT NOT NULL DEFAULT '',
+	user_agent  TEXT NOT NULL DEFAULT '',
+	outcome     TEXT NOT NULL,
+	created_at  TEXT NOT NULL
+);
+CREATE INDEX login_attempts_account_idx ON login_attempts(account_key, created_at);
+CREATE INDEX login_attempts_ip_idx ON login_attempts(ip, created_at);
+CREATE INDEX login_attempts_user_idx ON login_attempts(user_id);
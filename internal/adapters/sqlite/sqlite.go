@@ -0,0 +1,119 @@
+// Package sqlite stores users, prices and correlations in a SQLite file, for local
+// development and small single-user deployments. It follows the semantics of the postgres
+// adapter and passes the same conformance tests.
+//
+// The package only uses database/sql; the server links the pure Go modernc.org/sqlite
+// driver, which registers itself under DriverName.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// DriverName is the database/sql driver the adapter opens.
+const DriverName = "sqlite"
+
+// timeLayout stores times as fixed-width UTC text, which SQLite compares in time order.
+const timeLayout = "2006-01-02 15:04:05.000000"
+
+// now is the clock used for timestamps the database would set by default.
+var now = time.Now
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Open opens the database file at path, creating it if needed.
+func Open(path string) (*sql.DB, error) {
+	if !slices.Contains(sql.Drivers(), DriverName) {
+		return nil, fmt.Errorf("no %q database driver is linked into this binary", DriverName)
+	}
+	db, err := sql.Open(DriverName, path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite has a single writer; one connection avoids "database is locked" errors and
+	// keeps the pragmas below, which are per connection.
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, pragma := range []string{`PRAGMA foreign_keys = ON`, `PRAGMA journal_mode = WAL`, `PRAGMA busy_timeout = 5000`} {
+		if _, err := db.ExecContext(ctx, pragma); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("%s: %w", pragma, err)
+		}
+	}
+
+	log.Printf("Opened SQLite database %s", path)
+	return db, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func formatNullTime(t *time.Time) sql.NullString {
+	if t == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: formatTime(*t), Valid: true}
+}
+
+// timeColumn scans a time stored with formatTime.
+type timeColumn struct {
+	t *time.Time
+}
+
+func (c timeColumn) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case time.Time:
+		// Drivers may parse the text themselves.
+		*c.t = v.UTC()
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into a time", src)
+	}
+	t, err := time.ParseInLocation(timeLayout, s, time.UTC)
+	if err != nil {
+		return err
+	}
+	*c.t = t
+	return nil
+}
+
+// nullTimeColumn scans a nullable time stored with formatTime.
+type nullTimeColumn struct {
+	t **time.Time
+}
+
+func (c nullTimeColumn) Scan(src any) error {
+	if src == nil {
+		*c.t = nil
+		return nil
+	}
+	var t time.Time
+	if err := (timeColumn{&t}).Scan(src); err != nil {
+		return err
+	}
+	*c.t = &t
+	return nil
+}
+
+func nullFloat(v float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: v, Valid: v != 0}
+}
+
+func nullString(v string) sql.NullString {
+	return sql.NullString{String: v, Valid: v != ""}
+}
@@ -0,0 +1,28 @@
+package sqlite
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStoredTimesRoundTripAndSortAsText(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	earlier := time.Date(2026, 3, 2, 9, 0, 0, 123456000, cet)
+	later := time.Date(2026, 3, 2, 8, 30, 0, 0, time.UTC)
+
+	var got time.Time
+	if err := (timeColumn{&got}).Scan(formatTime(earlier)); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(earlier) || got.Location() != time.UTC {
+		t.Errorf("round trip: got %v, want %v in UTC", got, earlier)
+	}
+	if formatTime(earlier) >= formatTime(later) {
+		t.Errorf("%q should sort before %q", formatTime(earlier), formatTime(later))
+	}
+
+	var deletion *time.Time = &got
+	if err := (nullTimeColumn{&deletion}).Scan(nil); err != nil || deletion != nil {
+		t.Errorf("NULL should scan to nil, got %v, %v", deletion, err)
+	}
+}
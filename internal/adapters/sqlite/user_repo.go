@@ -0,0 +1,177 @@
+package sqlite
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+)
+
+const userColumns = `id, username, email, password, role, email_verified, disabled, password_reset_required, created_at, deletion_scheduled_at`
+
+type UserRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) repository.UserRepository {
+	return &UserRepository{db}
+}
+
+func (s *UserRepository) Save(ctx context.Context, user model.User) error {
+	query := `INSERT INTO users(username, email, password, role, created_at) VALUES (?, ?, ?, ?, ?)`
+	_, err := s.db.ExecContext(ctx, query, user.Username, user.Email, user.Password, user.Role, formatTime(now()))
+	return err
+}
+
+func (s *UserRepository) FindByUsernameOrEmail(ctx context.Context, identifier string) (model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE username=? OR email=? LIMIT 1`
+	return findUser(s.db.QueryRowContext(ctx, query, identifier, identifier))
+}
+
+func (s *UserRepository) FindByID(ctx context.Context, id uint) (model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id=? LIMIT 1`
+	return findUser(s.db.QueryRowContext(ctx, query, id))
+}
+
+func (s *UserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET password=?, password_reset_required=0 WHERE id=?`, hashedPassword, id)
+	return err
+}
+
+func (s *UserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE users SET email_verified=1 WHERE id=?`, id)
+	return err
+}
+
+func (s *UserRepository) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int, error) {
+	var where []string
+	var args []any
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern := "%" + escapeLike(strings.ToLower(q)) + "%"
+		args = append(args, pattern, pattern)
+		where = append(where, `(LOWER(username) LIKE ? ESCAPE '\' OR LOWER(email) LIKE ? ESCAPE '\')`)
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		where = append(where, "role=?")
+	}
+	if filter.Disabled != nil {
+		args = append(args, *filter.Disabled)
+		where = append(where, "disabled=?")
+	}
+	clause := ""
+	if len(where) > 0 {
+		clause = " WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM users`+clause, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	rows, err := s.db.QueryContext(ctx, `SELECT `+userColumns+` FROM users`+clause+` ORDER BY id LIMIT ? OFFSET ?`, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	return users, total, rows.Err()
+}
+
+func (s *UserRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	return s.execForUser(ctx, `UPDATE users SET role=? WHERE id=?`, role, id)
+}
+
+func (s *UserRepository) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	return s.execForUser(ctx, `UPDATE users SET disabled=? WHERE id=?`, disabled, id)
+}
+
+func (s *UserRepository) RequirePasswordReset(ctx context.Context, id uint) error {
+	return s.execForUser(ctx, `UPDATE users SET password_reset_required=1 WHERE id=?`, id)
+}
+
+func (s *UserRepository) UpdateEmail(ctx context.Context, id uint, email string, verified bool) error {
+	return s.execForUser(ctx, `UPDATE users SET email=?, email_verified=? WHERE id=?`, email, verified, id)
+}
+
+func (s *UserRepository) UpdateUsername(ctx context.Context, id uint, username string) error {
+	return s.execForUser(ctx, `UPDATE users SET username=? WHERE id=?`, username, id)
+}
+
+func (s *UserRepository) ScheduleDeletion(ctx context.Context, id uint, at *time.Time) error {
+	return s.execForUser(ctx, `UPDATE users SET deletion_scheduled_at=? WHERE id=?`, formatNullTime(at), id)
+}
+
+func (s *UserRepository) ListDueForDeletion(ctx context.Context, t time.Time) ([]uint, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM users WHERE deletion_scheduled_at <= ? ORDER BY id`, formatTime(t))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uint
+	for rows.Next() {
+		var id uint
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *UserRepository) Delete(ctx context.Context, id uint) error {
+	return s.execForUser(ctx, `DELETE FROM users WHERE id=?`, id)
+}
+
+// execForUser runs a single-user statement and reports a missing user.
+func (s *UserRepository) execForUser(ctx context.Context, query string, args ...any) error {
+	res, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func findUser(row rowScanner) (model.User, error) {
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, errors.New("user not found")
+	}
+	if err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+func scanUser(row rowScanner) (model.User, error) {
+	var user model.User
+	err := row.Scan(&user.ID, &user.Username, &user.Email, &user.Password, &user.Role,
+		&user.EmailVerified, &user.Disabled, &user.PasswordResetRequired,
+		timeColumn{&user.CreatedAt}, nullTimeColumn{&user.DeletionScheduledAt})
+	return user, err
+}
+
+// escapeLike escapes LIKE wildcards so user input only matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,194 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultAccountDeletionGrace is how long a deletion request can be cancelled when not
+// configured.
+const DefaultAccountDeletionGrace = 30 * 24 * time.Hour
+
+var ErrAccountDeletionNotScheduled = errors.New("account deletion is not scheduled")
+
+// WithAccountDeletionGrace sets how long after a deletion request the account is erased.
+func WithAccountDeletionGrace(grace time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		if grace > 0 {
+			s.deletionGrace = grace
+		}
+	}
+}
+
+// AccountExport is everything stored about a user, for the personal data export.
+type AccountExport struct {
+	ExportedAt       time.Time
+	User             model.User
+	Preferences      model.UserPreferences
+	TwoFactorEnabled bool
+	Sessions         []model.Session
+	APIKeys          []model.APIKey
+	Identities       []model.UserIdentity
+	LoginAttempts    []model.LoginAttempt
+	AuditEvents      []model.AuditEvent
+}
+
+// ExportAccountData collects the data held about a user across the repositories.
+func (s *UserService) ExportAccountData(ctx context.Context, userID uint) (AccountExport, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return AccountExport{}, err
+	}
+	export := AccountExport{ExportedAt: s.clock.Now(), User: user}
+
+	if export.Preferences, err = s.GetPreferences(ctx, userID); err != nil {
+		return AccountExport{}, fmt.Errorf("export preferences: %w", err)
+	}
+	if s.twoFactorRepo != nil {
+		tf, err := s.twoFactorRepo.Get(ctx, userID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return AccountExport{}, fmt.Errorf("export two-factor status: %w", err)
+		}
+		export.TwoFactorEnabled = err == nil && tf.Enabled
+	}
+	if s.sessionRepo != nil {
+		if export.Sessions, err = s.sessionRepo.ListActive(ctx, userID, s.clock.Now()); err != nil {
+			return AccountExport{}, fmt.Errorf("export sessions: %w", err)
+		}
+	}
+	if s.apiKeyRepo != nil {
+		if export.APIKeys, err = s.apiKeyRepo.ListByUser(ctx, userID); err != nil {
+			return AccountExport{}, fmt.Errorf("export API keys: %w", err)
+		}
+	}
+	if s.identityRepo != nil {
+		if export.Identities, err = s.identityRepo.ListByUser(ctx, userID); err != nil {
+			return AccountExport{}, fmt.Errorf("export identities: %w", err)
+		}
+	}
+	if s.attemptRepo != nil {
+		if export.LoginAttempts, err = s.attemptRepo.ListByUser(ctx, userID); err != nil {
+			return AccountExport{}, fmt.Errorf("export login attempts: %w", err)
+		}
+	}
+	if export.AuditEvents, err = s.audit.EventsOfUser(ctx, userID); err != nil {
+		return AccountExport{}, fmt.Errorf("export audit events: %w", err)
+	}
+	return export, nil
+}
+
+// RequestAccountDeletion schedules the erasure of the user's account after the grace period,
+// once the password, or reauthToken from a fresh external login, is confirmed. The user is signed out everywhere and their API keys are
+// revoked; signing in again and calling CancelAccountDeletion keeps the account.
+func (s *UserService) RequestAccountDeletion(ctx context.Context, userID uint, password, reauthToken string) (time.Time, error) {
+	user, err := s.reauthenticate(ctx, userID, password, reauthToken, model.AuditAccountDeletionRequest)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// Asking again does not push the date back.
+	if user.DeletionScheduledAt != nil {
+		return *user.DeletionScheduledAt, nil
+	}
+
+	at := s.clock.Now().Add(s.deletionGrace)
+	err = s.userRepo.ScheduleDeletion(ctx, user.ID, &at)
+	s.auditUserAction(ctx, user.ID, user.ID, model.AuditAccountDeletionRequest, "erase at "+at.UTC().Format(time.RFC3339), err)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.revokeAllSessions(ctx, user.ID); err != nil {
+		return time.Time{}, err
+	}
+	if err := s.revokeAllAPIKeys(ctx, user.ID); err != nil {
+		return time.Time{}, err
+	}
+	if s.mailer != nil {
+		if err := s.mailer.Send(ctx, Email{
+			To:      user.Email,
+			Subject: "Your account will be deleted",
+			Body: fmt.Sprintf("Hello %s,\n\nYour account and its data will be deleted on %s. To keep it, sign in and cancel the deletion from your settings before then.\n",
+				user.Username, at.UTC().Format("2 January 2006")),
+		}); err != nil {
+			log.Printf("Error notifying user %d of the account deletion: %v", user.ID, err)
+		}
+	}
+	return at, nil
+}
+
+// CancelAccountDeletion keeps an account whose deletion is scheduled.
+func (s *UserService) CancelAccountDeletion(ctx context.Context, userID uint) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.DeletionScheduledAt == nil {
+		return ErrAccountDeletionNotScheduled
+	}
+	err = s.userRepo.ScheduleDeletion(ctx, userID, nil)
+	s.auditUserAction(ctx, userID, userID, model.AuditAccountDeletionCancel, "", err)
+	return err
+}
+
+// PurgeDeletedAccounts erases the accounts whose grace period is over and returns how many
+// were erased. An account that fails is logged and retried on the next run.
+func (s *UserService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	ids, err := s.userRepo.ListDueForDeletion(ctx, s.clock.Now())
+	if err != nil {
+		return 0, err
+	}
+	erased := 0
+	for _, id := range ids {
+		if err := s.eraseAccount(ctx, id); err != nil {
+			log.Printf("Error erasing account %d: %v", id, err)
+			continue
+		}
+		erased++
+	}
+	return erased, nil
+}
+
+// eraseAccount removes a user and the data kept about them. Sessions, tokens, two-factor
+// data, API keys, identities and preferences go with the user row; login attempts would
+// outlive it, so they are deleted first. Audit events stay until the audit retention ends.
+func (s *UserService) eraseAccount(ctx context.Context, id uint) error {
+	if err := s.revokeAllSessions(ctx, id); err != nil {
+		return err
+	}
+	if s.attemptRepo != nil {
+		if err := s.attemptRepo.DeleteByUser(ctx, id); err != nil {
+			return fmt.Errorf("delete login attempts: %w", err)
+		}
+	}
+	err := s.userRepo.Delete(ctx, id)
+	s.auditUserAction(ctx, id, id, model.AuditAccountErase, "", err)
+	if err != nil {
+		return err
+	}
+	s.userStatusCache.set(userStatusKey(id), false)
+	return nil
+}
+
+func (s *UserService) revokeAllAPIKeys(ctx context.Context, userID uint) error {
+	if s.apiKeyRepo == nil {
+		return nil
+	}
+	keys, err := s.apiKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("revoke API keys: %w", err)
+	}
+	for _, k := range keys {
+		if k.RevokedAt != nil {
+			continue
+		}
+		if err := s.apiKeyRepo.Revoke(ctx, userID, k.ID, s.clock.Now()); err != nil {
+			return fmt.Errorf("revoke API key %s: %w", k.ID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type accountDataFixture struct {
+	svc      *UserService
+	user     *model.User
+	deleted  bool
+	clock    *fakeClock
+	sessions *fakeSessionRepository
+	attempts *fakeLoginAttemptRepository
+	apiKeys  *fakeAPIKeyRepository
+	audit    *fakeAuditRepository
+}
+
+// newAccountDataFixture signs user dana (ID 11) in once and gives them an API key, so
+// every repository holds some of their data.
+func newAccountDataFixture(t *testing.T) *accountDataFixture {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	f := &accountDataFixture{
+		user:     &model.User{ID: 11, Username: "dana", Email: "dana@example.com", Password: string(hash), Role: model.RoleUser},
+		clock:    &fakeClock{now: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)},
+		sessions: newFakeSessionRepository(),
+		attempts: &fakeLoginAttemptRepository{},
+		apiKeys:  &fakeAPIKeyRepository{},
+		audit:    &fakeAuditRepository{},
+	}
+	find := func() (model.User, error) {
+		if f.deleted {
+			return model.User{}, errors.New("user not found")
+		}
+		return *f.user, nil
+	}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(string) (model.User, error) { return find() },
+		findByIDFn:              func(uint) (model.User, error) { return find() },
+		scheduleDeletionFn: func(id uint, at *time.Time) error {
+			f.user.DeletionScheduledAt = at
+			return nil
+		},
+		listDueForDeletionFn: func(t time.Time) ([]uint, error) {
+			if !f.deleted && f.user.DeletionScheduledAt != nil && !f.user.DeletionScheduledAt.After(t) {
+				return []uint{f.user.ID}, nil
+			}
+			return nil, nil
+		},
+		deleteFn: func(id uint) error {
+			f.deleted = true
+			return nil
+		},
+	}
+	auditLog := NewAuditLog(f.audit, 0)
+	auditLog.clock = f.clock
+	f.svc = NewUserService(repo, loginTestKeys,
+		WithSessionRepository(f.sessions),
+		WithLoginAttempts(f.attempts, DefaultLoginThrottlePolicy()),
+		WithAPIKeys(f.apiKeys),
+		WithPreferences(newFakePreferencesRepository()),
+		WithAuditLog(auditLog),
+		WithAccountDeletionGrace(7*24*time.Hour),
+		WithClock(f.clock),
+	)
+
+	ctx := context.Background()
+	if _, _, err := f.svc.Login(ctx, LoginInput{Identifier: "dana", Password: correctPassword, IP: "203.0.113.9"}); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if _, _, err := f.svc.CreateAPIKey(ctx, f.user.ID, CreateAPIKeyInput{Name: "script", Scopes: []string{model.PermCommodityRead}}); err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	return f
+}
+
+func TestExportAccountDataCollectsEveryRepository(t *testing.T) {
+	f := newAccountDataFixture(t)
+
+	export, err := f.svc.ExportAccountData(context.Background(), f.user.ID)
+	if err != nil {
+		t.Fatalf("ExportAccountData() error = %v", err)
+	}
+	if export.User.Username != "dana" || export.Preferences.Currency != BaseCurrency {
+		t.Fatalf("export profile = %+v, preferences = %+v", export.User, export.Preferences)
+	}
+	if len(export.Sessions) != 1 || len(export.APIKeys) != 1 || len(export.LoginAttempts) != 1 {
+		t.Fatalf("export has %d sessions, %d API keys, %d login attempts, want 1 each",
+			len(export.Sessions), len(export.APIKeys), len(export.LoginAttempts))
+	}
+	actions := map[string]bool{}
+	for _, e := range export.AuditEvents {
+		actions[e.Action] = true
+	}
+	if !actions[model.AuditLogin] || !actions[model.AuditAPIKeyCreate] {
+		t.Fatalf("exported audit actions = %v, want the login and the API key creation", actions)
+	}
+}
+
+func TestAccountDeletionGracePeriodAndCancel(t *testing.T) {
+	f := newAccountDataFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.svc.RequestAccountDeletion(ctx, f.user.ID, "wrong", ""); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("RequestAccountDeletion(wrong password) error = %v, want %v", err, ErrIncorrectPassword)
+	}
+
+	at, err := f.svc.RequestAccountDeletion(ctx, f.user.ID, correctPassword, "")
+	if err != nil {
+		t.Fatalf("RequestAccountDeletion() error = %v", err)
+	}
+	if want := f.clock.now.Add(7 * 24 * time.Hour); !at.Equal(want) {
+		t.Fatalf("deletion at %v, want %v", at, want)
+	}
+	if active, _ := f.sessions.ListActive(ctx, f.user.ID, f.clock.Now()); len(active) != 0 {
+		t.Fatalf("%d sessions still active after the deletion request", len(active))
+	}
+	if keys, _ := f.apiKeys.ListByUser(ctx, f.user.ID); len(keys) != 0 {
+		t.Fatalf("%d API keys still active after the deletion request", len(keys))
+	}
+
+	// Asking again keeps the original date.
+	f.clock.Advance(24 * time.Hour)
+	if again, err := f.svc.RequestAccountDeletion(ctx, f.user.ID, correctPassword, ""); err != nil || !again.Equal(at) {
+		t.Fatalf("second RequestAccountDeletion() = %v, %v, want %v", again, err, at)
+	}
+
+	if err := f.svc.CancelAccountDeletion(ctx, f.user.ID); err != nil {
+		t.Fatalf("CancelAccountDeletion() error = %v", err)
+	}
+	if f.user.DeletionScheduledAt != nil {
+		t.Fatal("deletion still scheduled after cancelling")
+	}
+	if err := f.svc.CancelAccountDeletion(ctx, f.user.ID); !errors.Is(err, ErrAccountDeletionNotScheduled) {
+		t.Fatalf("second CancelAccountDeletion() error = %v, want %v", err, ErrAccountDeletionNotScheduled)
+	}
+}
+
+func TestPurgeDeletedAccountsErasesAfterGracePeriod(t *testing.T) {
+	f := newAccountDataFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.svc.RequestAccountDeletion(ctx, f.user.ID, correctPassword, ""); err != nil {
+		t.Fatalf("RequestAccountDeletion() error = %v", err)
+	}
+
+	f.clock.Advance(6 * 24 * time.Hour)
+	if n, err := f.svc.PurgeDeletedAccounts(ctx); err != nil || n != 0 {
+		t.Fatalf("PurgeDeletedAccounts() inside the grace period = %d, %v, want 0", n, err)
+	}
+
+	f.clock.Advance(24 * time.Hour)
+	if n, err := f.svc.PurgeDeletedAccounts(ctx); err != nil || n != 1 {
+		t.Fatalf("PurgeDeletedAccounts() = %d, %v, want 1", n, err)
+	}
+	if !f.deleted {
+		t.Fatal("user not deleted")
+	}
+	if attempts, _ := f.attempts.ListByUser(ctx, f.user.ID); len(attempts) != 0 {
+		t.Fatalf("%d login attempts kept after erasing the account", len(attempts))
+	}
+	if active, _ := f.svc.IsUserActive(ctx, f.user.ID); active {
+		t.Fatal("erased user still reported active")
+	}
+}
@@ -0,0 +1,263 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+var (
+	ErrInvalidRole           = errors.New("unknown role")
+	ErrCannotModifySelf      = errors.New("admins cannot change their own role, status or account")
+	ErrAccountDisabled       = errors.New("account is disabled")
+	ErrPasswordResetRequired = errors.New("password reset required, check your email for a reset link")
+)
+
+// UserListInput describes one page of the admin user listing.
+type UserListInput struct {
+	Query string
+	Role  string
+	// Status is "active", "disabled" or empty for both.
+	Status   string
+	Page     int
+	PageSize int
+}
+
+type UserPage struct {
+	Users    []model.User
+	Total    int
+	Page     int
+	PageSize int
+}
+
+// UserDetails is what an admin sees about a single account.
+type UserDetails struct {
+	User             model.User
+	TwoFactorEnabled bool
+	Sessions         []model.Session
+}
+
+// ListUsers searches and pages through all accounts.
+func (s *UserService) ListUsers(ctx context.Context, req UserListInput) (UserPage, error) {
+	filter := repository.UserFilter{Query: strings.TrimSpace(req.Query)}
+
+	if req.Role != "" {
+		ok, err := s.roleExists(ctx, req.Role)
+		if err != nil {
+			return UserPage{}, err
+		}
+		if !ok {
+			return UserPage{}, ErrInvalidRole
+		}
+		filter.Role = req.Role
+	}
+	switch req.Status {
+	case "":
+	case "active", "disabled":
+		disabled := req.Status == "disabled"
+		filter.Disabled = &disabled
+	default:
+		return UserPage{}, errors.New("status must be active or disabled")
+	}
+
+	page, size := req.Page, req.PageSize
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultUserPageSize
+	}
+	if size > maxUserPageSize {
+		size = maxUserPageSize
+	}
+	filter.Limit = size
+	filter.Offset = (page - 1) * size
+
+	users, total, err := s.userRepo.List(ctx, filter)
+	if err != nil {
+		return UserPage{}, err
+	}
+	return UserPage{Users: users, Total: total, Page: page, PageSize: size}, nil
+}
+
+// GetUserDetails returns an account with its two-factor status and active sessions.
+func (s *UserService) GetUserDetails(ctx context.Context, id uint) (UserDetails, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return UserDetails{}, err
+	}
+	details := UserDetails{User: user}
+
+	if s.twoFactorRepo != nil {
+		tf, err := s.twoFactorRepo.Get(ctx, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return UserDetails{}, err
+		}
+		details.TwoFactorEnabled = err == nil && tf.Enabled
+	}
+	if s.sessionRepo != nil {
+		sessions, err := s.sessionRepo.ListActive(ctx, id, s.clock.Now())
+		if err != nil {
+			return UserDetails{}, err
+		}
+		details.Sessions = sessions
+	}
+	return details, nil
+}
+
+// ChangeUserRole sets the role of another user. Their sessions are revoked so the new role
+// is picked up at the next login instead of when the current access token expires.
+func (s *UserService) ChangeUserRole(ctx context.Context, actorID, id uint, role string) (err error) {
+	defer func() { s.auditUserAction(ctx, actorID, id, model.AuditUserRoleChange, "role "+role, err) }()
+
+	ok, err := s.roleExists(ctx, role)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidRole
+	}
+	if actorID == id {
+		return ErrCannotModifySelf
+	}
+	if err := s.userRepo.UpdateRole(ctx, id, role); err != nil {
+		return err
+	}
+	return s.revokeAllSessions(ctx, id)
+}
+
+// SetUserDisabled disables or re-enables another user. Disabling signs them out everywhere.
+func (s *UserService) SetUserDisabled(ctx context.Context, actorID, id uint, disabled bool) (err error) {
+	action := model.AuditUserEnable
+	if disabled {
+		action = model.AuditUserDisable
+	}
+	defer func() { s.auditUserAction(ctx, actorID, id, action, "", err) }()
+
+	if actorID == id {
+		return ErrCannotModifySelf
+	}
+	if err := s.userRepo.SetDisabled(ctx, id, disabled); err != nil {
+		return err
+	}
+	s.userStatusCache.set(userStatusKey(id), !disabled)
+	if !disabled {
+		return nil
+	}
+	return s.revokeAllSessions(ctx, id)
+}
+
+// ForcePasswordReset blocks password logins for the user until they reset their password
+// through the link emailed to them, and signs them out everywhere.
+func (s *UserService) ForcePasswordReset(ctx context.Context, actorID, id uint) (err error) {
+	defer func() { s.auditUserAction(ctx, actorID, id, model.AuditUserForcePasswordReset, "", err) }()
+
+	if s.tokenRepo == nil || s.mailer == nil {
+		return ErrAccountEmailsUnavailable
+	}
+
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.RequirePasswordReset(ctx, id); err != nil {
+		return err
+	}
+	if err := s.revokeAllSessions(ctx, id); err != nil {
+		return err
+	}
+	return s.sendPasswordReset(ctx, user)
+}
+
+// DeleteUser permanently removes another user together with their sessions and tokens.
+func (s *UserService) DeleteUser(ctx context.Context, actorID, id uint) (err error) {
+	defer func() { s.auditUserAction(ctx, actorID, id, model.AuditUserDelete, "", err) }()
+
+	if actorID == id {
+		return ErrCannotModifySelf
+	}
+	if err := s.revokeAllSessions(ctx, id); err != nil {
+		return err
+	}
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.userStatusCache.set(userStatusKey(id), false)
+	log.Printf("User %d deleted by admin %d", id, actorID)
+	return nil
+}
+
+// IsUserActive reports whether tokens of the user may still be used. Like session checks,
+// answers are cached briefly.
+func (s *UserService) IsUserActive(ctx context.Context, userID uint) (bool, error) {
+	key := userStatusKey(userID)
+	if active, ok := s.userStatusCache.get(key); ok {
+		return active, nil
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			s.userStatusCache.set(key, false)
+			return false, nil
+		}
+		return false, err
+	}
+	s.userStatusCache.set(key, !user.Disabled)
+	return !user.Disabled, nil
+}
+
+// revokeAllSessions signs the user out of every session.
+func (s *UserService) revokeAllSessions(ctx context.Context, userID uint) error {
+	if s.sessionRepo == nil {
+		return nil
+	}
+	ids, err := s.sessionRepo.RevokeAllExcept(ctx, userID, "", s.clock.Now())
+	if err != nil {
+		return fmt.Errorf("revoke sessions: %w", err)
+	}
+	for _, id := range ids {
+		s.sessionCache.set(id, false)
+	}
+	return nil
+}
+
+// checkAccountStatus rejects sign-ins to accounts an admin has disabled or flagged for a
+// password reset.
+func checkAccountStatus(user model.User) error {
+	if user.Disabled {
+		return ErrAccountDisabled
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
+
+func (s *UserService) roleExists(ctx context.Context, role string) (bool, error) {
+	if s.roleRepo == nil {
+		return role == model.RoleUser || role == model.RoleAdmin, nil
+	}
+	if _, err := s.roleRepo.Get(ctx, role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func userStatusKey(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
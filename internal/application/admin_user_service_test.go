@@ -0,0 +1,128 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestListUsersNormalisesPaging(t *testing.T) {
+	var got repository.UserFilter
+	repo := &fakeUserRepository{
+		listFn: func(filter repository.UserFilter) ([]model.User, int, error) {
+			got = filter
+			return []model.User{{ID: 1}}, 250, nil
+		},
+	}
+	svc := NewUserService(repo, loginTestKeys)
+
+	page, err := svc.ListUsers(context.Background(), UserListInput{Query: " ali ", Status: "disabled", Page: 3, PageSize: 500})
+	if err != nil {
+		t.Fatalf("ListUsers() error = %v", err)
+	}
+	if got.Limit != maxUserPageSize || got.Offset != 2*maxUserPageSize {
+		t.Fatalf("limit/offset = %d/%d, want %d/%d", got.Limit, got.Offset, maxUserPageSize, 2*maxUserPageSize)
+	}
+	if got.Query != "ali" || got.Disabled == nil || !*got.Disabled {
+		t.Fatalf("filter = %+v, want query ali and disabled only", got)
+	}
+	if page.Total != 250 || page.Page != 3 || page.PageSize != maxUserPageSize {
+		t.Fatalf("page = %+v", page)
+	}
+
+	if _, err := svc.ListUsers(context.Background(), UserListInput{Role: "root"}); !errors.Is(err, ErrInvalidRole) {
+		t.Fatalf("ListUsers(role=root) error = %v, want %v", err, ErrInvalidRole)
+	}
+}
+
+func TestAdminCannotModifyOwnAccount(t *testing.T) {
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys)
+	ctx := context.Background()
+
+	if err := svc.ChangeUserRole(ctx, 1, 1, model.RoleUser); !errors.Is(err, ErrCannotModifySelf) {
+		t.Fatalf("ChangeUserRole(self) error = %v, want %v", err, ErrCannotModifySelf)
+	}
+	if err := svc.SetUserDisabled(ctx, 1, 1, true); !errors.Is(err, ErrCannotModifySelf) {
+		t.Fatalf("SetUserDisabled(self) error = %v, want %v", err, ErrCannotModifySelf)
+	}
+	if err := svc.DeleteUser(ctx, 1, 1); !errors.Is(err, ErrCannotModifySelf) {
+		t.Fatalf("DeleteUser(self) error = %v, want %v", err, ErrCannotModifySelf)
+	}
+	if err := svc.ChangeUserRole(ctx, 1, 2, "superuser"); !errors.Is(err, ErrInvalidRole) {
+		t.Fatalf("ChangeUserRole(superuser) error = %v, want %v", err, ErrInvalidRole)
+	}
+}
+
+func TestDisabledUserIsSignedOutAndCannotLogin(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	user := model.User{ID: 4, Username: "dave", Email: "dave@example.com", Password: string(hash), Role: model.RoleUser}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) { return user, nil },
+		findByIDFn:              func(id uint) (model.User, error) { return user, nil },
+		setDisabledFn: func(id uint, disabled bool) error {
+			user.Disabled = disabled
+			return nil
+		},
+	}
+	sessions := newFakeSessionRepository()
+	svc := NewUserService(repo, loginTestKeys, WithSessionRepository(sessions))
+	ctx := context.Background()
+
+	_, result, err := svc.Login(ctx, LoginInput{Identifier: "dave", Password: correctPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if active, _ := svc.IsUserActive(ctx, user.ID); !active {
+		t.Fatal("IsUserActive() = false before disabling")
+	}
+
+	if err := svc.SetUserDisabled(ctx, 1, user.ID, true); err != nil {
+		t.Fatalf("SetUserDisabled() error = %v", err)
+	}
+	if active, _ := svc.IsUserActive(ctx, user.ID); active {
+		t.Fatal("IsUserActive() = true after disabling")
+	}
+	if active, _ := sessions.ListActive(ctx, user.ID, time.Now()); len(active) != 0 {
+		t.Fatalf("%d sessions still active after disabling, want 0", len(active))
+	}
+	if _, err := svc.RefreshToken(ctx, result.RefreshToken, ""); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("RefreshToken() error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+	if _, _, err := svc.Login(ctx, LoginInput{Identifier: "dave", Password: correctPassword}); !errors.Is(err, ErrAccountDisabled) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrAccountDisabled)
+	}
+
+	if err := svc.SetUserDisabled(ctx, 1, user.ID, false); err != nil {
+		t.Fatalf("SetUserDisabled(false) error = %v", err)
+	}
+	if _, _, err := svc.Login(ctx, LoginInput{Identifier: "dave", Password: correctPassword}); err != nil {
+		t.Fatalf("Login() after enabling error = %v", err)
+	}
+}
+
+func TestForcePasswordResetBlocksLoginUntilReset(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	ctx := context.Background()
+
+	if err := f.svc.ForcePasswordReset(ctx, 1, f.user.ID); err != nil {
+		t.Fatalf("ForcePasswordReset() error = %v", err)
+	}
+	if _, _, err := f.svc.Login(ctx, LoginInput{Identifier: "carol", Password: correctPassword}); !errors.Is(err, ErrPasswordResetRequired) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrPasswordResetRequired)
+	}
+
+	if err := f.svc.ResetPassword(ctx, f.lastToken(t), "NewPassw0rd!"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if _, _, err := f.svc.Login(ctx, LoginInput{Identifier: "carol", Password: "NewPassw0rd!"}); err != nil {
+		t.Fatalf("Login() after reset error = %v", err)
+	}
+}
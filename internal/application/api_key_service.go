@@ -0,0 +1,203 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPIKeyRateLimit is the requests per minute a key gets when none is asked for.
+	DefaultAPIKeyRateLimit = 60
+	maxAPIKeyRateLimit     = 600
+	maxAPIKeysPerUser      = 20
+	// apiKeyTouchInterval throttles last-used writes for busy keys.
+	apiKeyTouchInterval = time.Minute
+)
+
+var (
+	ErrAPIKeysUnavailable = errors.New("API keys are not enabled on this server")
+	ErrInvalidAPIKey      = auth.ErrInvalidAPIKey
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrTooManyAPIKeys     = fmt.Errorf("a user can have at most %d API keys", maxAPIKeysPerUser)
+	ErrScopeNotGranted    = errors.New("scope not granted to your role")
+)
+
+type CreateAPIKeyInput struct {
+	Name   string
+	Scopes []string
+	// ExpiresAt is optional; keys without it stay valid until revoked.
+	ExpiresAt *time.Time
+	// RateLimit is requests per minute; zero means DefaultAPIKeyRateLimit.
+	RateLimit int
+}
+
+// CreateAPIKey issues a key limited to scopes, which must all be granted to the user's role.
+// The returned key string is only available now; afterwards only its prefix is known.
+func (s *UserService) CreateAPIKey(ctx context.Context, userID uint, req CreateAPIKeyInput) (string, model.APIKey, error) {
+	if s.apiKeyRepo == nil {
+		return "", model.APIKey{}, ErrAPIKeysUnavailable
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return "", model.APIKey{}, errors.New("name is required and at most 100 characters")
+	}
+	if len(req.Scopes) == 0 {
+		return "", model.APIKey{}, errors.New("at least one scope is required")
+	}
+	rate := req.RateLimit
+	if rate == 0 {
+		rate = DefaultAPIKeyRateLimit
+	}
+	if rate < 1 || rate > maxAPIKeyRateLimit {
+		return "", model.APIKey{}, fmt.Errorf("rate_limit must be between 1 and %d requests per minute", maxAPIKeyRateLimit)
+	}
+	now := s.clock.Now()
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(now) {
+		return "", model.APIKey{}, errors.New("expires_at must be in the future")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+	granted, err := s.permissionsFor(ctx, user.Role)
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	for _, scope := range req.Scopes {
+		if !model.IsPermission(scope) {
+			return "", model.APIKey{}, fmt.Errorf("%w: %s", ErrUnknownPermission, scope)
+		}
+		if !slices.Contains(granted, scope) {
+			return "", model.APIKey{}, fmt.Errorf("%w: %s", ErrScopeNotGranted, scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	slices.Sort(scopes)
+
+	existing, err := s.apiKeyRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+	if len(existing) >= maxAPIKeysPerUser {
+		return "", model.APIKey{}, ErrTooManyAPIKeys
+	}
+
+	id, err := auth.NewSessionID()
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+	secret, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		return "", model.APIKey{}, err
+	}
+	key := model.APIKey{
+		ID:        id,
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		RateLimit: rate,
+		CreatedAt: now,
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Create(ctx, key); err != nil {
+		return "", model.APIKey{}, fmt.Errorf("store API key: %w", err)
+	}
+	s.auditUserAction(ctx, userID, userID, model.AuditAPIKeyCreate, "key "+prefix+" scopes "+strings.Join(scopes, ","), nil)
+	return secret, key, nil
+}
+
+// ListAPIKeys returns the user's active and expired keys, without their secrets.
+func (s *UserService) ListAPIKeys(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	if s.apiKeyRepo == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+	return s.apiKeyRepo.ListByUser(ctx, userID)
+}
+
+// RevokeAPIKey disables one of the user's keys immediately.
+func (s *UserService) RevokeAPIKey(ctx context.Context, userID uint, id string) error {
+	if s.apiKeyRepo == nil {
+		return ErrAPIKeysUnavailable
+	}
+	if err := s.apiKeyRepo.Revoke(ctx, userID, id, s.clock.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	s.auditUserAction(ctx, userID, userID, model.AuditAPIKeyRevoke, "key "+id, nil)
+	return nil
+}
+
+// AuthenticateAPIKey resolves a presented key to the access it grants: its scopes, narrowed
+// to what the owner's role still allows.
+func (s *UserService) AuthenticateAPIKey(ctx context.Context, key string) (model.APIKeyAccess, error) {
+	if s.apiKeyRepo == nil {
+		return model.APIKeyAccess{}, ErrAPIKeysUnavailable
+	}
+	prefix, ok := auth.APIKeyPrefix(key)
+	if !ok {
+		return model.APIKeyAccess{}, ErrInvalidAPIKey
+	}
+
+	stored, err := s.apiKeyRepo.FindByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.APIKeyAccess{}, ErrInvalidAPIKey
+		}
+		return model.APIKeyAccess{}, err
+	}
+	if subtle.ConstantTimeCompare([]byte(auth.HashAPIKey(key)), []byte(stored.KeyHash)) != 1 {
+		return model.APIKeyAccess{}, ErrInvalidAPIKey
+	}
+	now := s.clock.Now()
+	if stored.RevokedAt != nil || (stored.ExpiresAt != nil && !now.Before(*stored.ExpiresAt)) {
+		return model.APIKeyAccess{}, ErrInvalidAPIKey
+	}
+
+	// Keys stop working while the owner may not sign in, e.g. until a forced password reset.
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil || checkAccountStatus(user) != nil {
+		return model.APIKeyAccess{}, ErrInvalidAPIKey
+	}
+	granted, err := s.permissionsFor(ctx, user.Role)
+	if err != nil {
+		return model.APIKeyAccess{}, err
+	}
+	perms := make([]string, 0, len(stored.Scopes))
+	for _, scope := range stored.Scopes {
+		if slices.Contains(granted, scope) {
+			perms = append(perms, scope)
+		}
+	}
+
+	if stored.LastUsedAt == nil || now.Sub(*stored.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.TouchLastUsed(ctx, stored.ID, now); err != nil {
+			log.Printf("Error updating API key %s last use: %v", stored.ID, err)
+		}
+	}
+
+	return model.APIKeyAccess{
+		KeyID:       stored.ID,
+		UserID:      user.ID,
+		Role:        user.Role,
+		Permissions: perms,
+		RateLimit:   stored.RateLimit,
+	}, nil
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func newAPIKeyTestService(user *model.User) (*UserService, *fakeAPIKeyRepository, *fakeRoleRepository, *fakeClock) {
+	repo := &fakeUserRepository{
+		findByIDFn: func(id uint) (model.User, error) {
+			if id == user.ID {
+				return *user, nil
+			}
+			return model.User{}, errors.New("user not found")
+		},
+	}
+	keys := &fakeAPIKeyRepository{}
+	roles := newFakeRoleRepository()
+	clock := &fakeClock{now: time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)}
+	svc := NewUserService(repo, loginTestKeys, WithRoles(roles), WithAPIKeys(keys), WithClock(clock))
+	return svc, keys, roles, clock
+}
+
+func TestAPIKeyAuthenticatesWithItsScopes(t *testing.T) {
+	user := &model.User{ID: 3, Username: "bot-owner", Role: model.RoleUser}
+	svc, keys, _, clock := newAPIKeyTestService(user)
+	ctx := context.Background()
+
+	secret, key, err := svc.CreateAPIKey(ctx, user.ID, CreateAPIKeyInput{Name: "price bot", Scopes: []string{model.PermCommodityRead}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	if key.RateLimit != DefaultAPIKeyRateLimit || key.KeyHash == secret {
+		t.Fatalf("stored key = %+v", key)
+	}
+
+	access, err := svc.AuthenticateAPIKey(ctx, secret)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey() error = %v", err)
+	}
+	if access.UserID != user.ID || !slices.Equal(access.Permissions, []string{model.PermCommodityRead}) {
+		t.Fatalf("access = %+v", access)
+	}
+	if keys.keys[0].LastUsedAt == nil || !keys.keys[0].LastUsedAt.Equal(clock.now) {
+		t.Fatalf("LastUsedAt = %v, want %v", keys.keys[0].LastUsedAt, clock.now)
+	}
+
+	if _, err := svc.AuthenticateAPIKey(ctx, secret+"x"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("AuthenticateAPIKey(tampered) error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+}
+
+func TestCreateAPIKeyRejectsScopesBeyondRole(t *testing.T) {
+	user := &model.User{ID: 3, Role: model.RoleUser}
+	svc, _, _, _ := newAPIKeyTestService(user)
+
+	_, _, err := svc.CreateAPIKey(context.Background(), user.ID, CreateAPIKeyInput{Name: "admin bot", Scopes: []string{model.PermUsersManage}})
+	if !errors.Is(err, ErrScopeNotGranted) {
+		t.Fatalf("CreateAPIKey() error = %v, want %v", err, ErrScopeNotGranted)
+	}
+}
+
+func TestAPIKeyRevokedOrExpiredIsRejected(t *testing.T) {
+	user := &model.User{ID: 3, Role: model.RoleUser}
+	svc, _, _, clock := newAPIKeyTestService(user)
+	ctx := context.Background()
+
+	expiry := clock.now.Add(time.Hour)
+	expiring, _, err := svc.CreateAPIKey(ctx, user.ID, CreateAPIKeyInput{Name: "short", Scopes: []string{model.PermCommodityRead}, ExpiresAt: &expiry})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+	revoked, key, err := svc.CreateAPIKey(ctx, user.ID, CreateAPIKeyInput{Name: "old", Scopes: []string{model.PermCommodityRead}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, 99, key.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Fatalf("RevokeAPIKey(other user) error = %v, want %v", err, ErrAPIKeyNotFound)
+	}
+	if err := svc.RevokeAPIKey(ctx, user.ID, key.ID); err != nil {
+		t.Fatalf("RevokeAPIKey() error = %v", err)
+	}
+	if _, err := svc.AuthenticateAPIKey(ctx, revoked); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("AuthenticateAPIKey(revoked) error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+
+	clock.Advance(time.Hour)
+	if _, err := svc.AuthenticateAPIKey(ctx, expiring); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("AuthenticateAPIKey(expired) error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+}
+
+func TestAPIKeyScopesFollowRoleChanges(t *testing.T) {
+	user := &model.User{ID: 3, Role: model.RoleUser}
+	svc, _, roles, _ := newAPIKeyTestService(user)
+	ctx := context.Background()
+
+	secret, _, err := svc.CreateAPIKey(ctx, user.ID, CreateAPIKeyInput{Name: "bot", Scopes: []string{model.PermCommodityRead, model.PermCorrelationRead}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	roles.roles[model.RoleUser] = model.Role{Name: model.RoleUser, Permissions: []string{model.PermCorrelationRead}}
+	access, err := svc.AuthenticateAPIKey(ctx, secret)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIKey() error = %v", err)
+	}
+	if !slices.Equal(access.Permissions, []string{model.PermCorrelationRead}) {
+		t.Fatalf("Permissions = %v, want only %s", access.Permissions, model.PermCorrelationRead)
+	}
+
+	user.Disabled = true
+	if _, err := svc.AuthenticateAPIKey(ctx, secret); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("AuthenticateAPIKey(disabled owner) error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+}
+
+func TestAPIKeyRejectedUntilForcedPasswordReset(t *testing.T) {
+	user := &model.User{ID: 3, Role: model.RoleUser}
+	svc, _, _, _ := newAPIKeyTestService(user)
+	ctx := context.Background()
+
+	secret, _, err := svc.CreateAPIKey(ctx, user.ID, CreateAPIKeyInput{Name: "bot", Scopes: []string{model.PermCommodityRead}})
+	if err != nil {
+		t.Fatalf("CreateAPIKey() error = %v", err)
+	}
+
+	user.PasswordResetRequired = true
+	if _, err := svc.AuthenticateAPIKey(ctx, secret); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Fatalf("AuthenticateAPIKey(reset pending) error = %v, want %v", err, ErrInvalidAPIKey)
+	}
+
+	user.PasswordResetRequired = false
+	if _, err := svc.AuthenticateAPIKey(ctx, secret); err != nil {
+		t.Fatalf("AuthenticateAPIKey(after reset) error = %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"time"
+)
+
+const (
+	// DefaultAuditRetention is how long audit events are kept when not configured.
+	DefaultAuditRetention = 365 * 24 * time.Hour
+
+	defaultAuditPageSize = 50
+	maxAuditPageSize     = 500
+)
+
+var ErrInvalidAuditFilter = errors.New("invalid audit filter")
+
+// RequestInfo describes the client behind a request, for the audit trail.
+type RequestInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type requestInfoKey struct{}
+
+// ContextWithRequestInfo attaches the client description to ctx so audit events recorded
+// while serving the request carry it.
+func ContextWithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+func requestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
+
+// AuditLog appends security-relevant actions to the audit trail and enforces its retention.
+// A nil *AuditLog records nothing.
+type AuditLog struct {
+	repo      repository.AuditRepository
+	retention time.Duration
+	clock     Clock
+}
+
+func NewAuditLog(repo repository.AuditRepository, retention time.Duration) *AuditLog {
+	if retention <= 0 {
+		retention = DefaultAuditRetention
+	}
+	return &AuditLog{repo: repo, retention: retention, clock: systemClock{}}
+}
+
+// Record appends an event, filling the client from the request context when not set.
+// Failing to record is logged and never fails the audited action.
+func (a *AuditLog) Record(ctx context.Context, event model.AuditEvent) {
+	if a == nil {
+		return
+	}
+	info := requestInfoFromContext(ctx)
+	if event.IP == "" {
+		event.IP = info.IP
+	}
+	if event.UserAgent == "" {
+		event.UserAgent = info.UserAgent
+	}
+	if event.Result == "" {
+		event.Result = model.AuditResultSuccess
+	}
+	event.CreatedAt = a.clock.Now()
+	if err := a.repo.Record(ctx, event); err != nil {
+		log.Printf("Error recording audit event %s: %v", event.Action, err)
+	}
+}
+
+// AuditQuery describes one page of the admin audit listing.
+type AuditQuery struct {
+	ActorID  *uint
+	Action   string
+	TargetID string
+	Result   string
+	Since    time.Time
+	Until    time.Time
+	Limit    int
+	Offset   int
+}
+
+// Query returns matching events, newest first.
+func (a *AuditLog) Query(ctx context.Context, q AuditQuery) ([]model.AuditEvent, error) {
+	switch q.Result {
+	case "", model.AuditResultSuccess, model.AuditResultFailure:
+	default:
+		return nil, ErrInvalidAuditFilter
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Since.Before(q.Until) {
+		return nil, ErrInvalidAuditFilter
+	}
+
+	limit := q.Limit
+	if limit < 1 {
+		limit = defaultAuditPageSize
+	}
+	if limit > maxAuditPageSize {
+		limit = maxAuditPageSize
+	}
+	return a.repo.Query(ctx, repository.AuditFilter{
+		ActorID:  q.ActorID,
+		Action:   q.Action,
+		TargetID: q.TargetID,
+		Result:   q.Result,
+		Since:    q.Since,
+		Until:    q.Until,
+		Limit:    limit,
+		Offset:   max(q.Offset, 0),
+	})
+}
+
+// EventsOfUser returns every event the user acted in or that targeted their account,
+// newest first.
+func (a *AuditLog) EventsOfUser(ctx context.Context, userID uint) ([]model.AuditEvent, error) {
+	if a == nil {
+		return nil, nil
+	}
+	seen := map[int64]bool{}
+	var events []model.AuditEvent
+	for _, filter := range []repository.AuditFilter{
+		{ActorID: &userID},
+		{TargetType: "user", TargetID: userStatusKey(userID)},
+	} {
+		filter.Limit = maxAuditPageSize
+		for {
+			page, err := a.repo.Query(ctx, filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range page {
+				if !seen[e.ID] {
+					seen[e.ID] = true
+					events = append(events, e)
+				}
+			}
+			if len(page) < filter.Limit {
+				break
+			}
+			filter.Offset += len(page)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].ID > events[j].ID
+		}
+		return events[i].CreatedAt.After(events[j].CreatedAt)
+	})
+	return events, nil
+}
+
+// PurgeExpired deletes events older than the retention period.
+func (a *AuditLog) PurgeExpired(ctx context.Context) (int64, error) {
+	return a.repo.DeleteBefore(ctx, a.clock.Now().Add(-a.retention))
+}
+
+// auditResult maps the outcome of an action to the recorded result.
+func auditResult(err error) string {
+	if err != nil {
+		return model.AuditResultFailure
+	}
+	return model.AuditResultSuccess
+}
+
+// auditUserAction records an action of actorID on the account targetID.
+func (s *UserService) auditUserAction(ctx context.Context, actorID, targetID uint, action, detail string, err error) {
+	if err != nil && detail == "" {
+		detail = err.Error()
+	}
+	s.audit.Record(ctx, model.AuditEvent{
+		ActorID:    &actorID,
+		Action:     action,
+		TargetType: "user",
+		TargetID:   userStatusKey(targetID),
+		Result:     auditResult(err),
+		Detail:     detail,
+	})
+}
@@ -0,0 +1,103 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLoginOutcomesAreAudited(t *testing.T) {
+	svc, clock, _ := newThrottleTestService(t, DefaultLoginThrottlePolicy())
+	repo := &fakeAuditRepository{}
+	audit := NewAuditLog(repo, 0)
+	audit.clock = clock
+	WithAuditLog(audit)(svc)
+
+	if err := loginAs(svc, "alice", "wrong", "203.0.113.7"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if err := loginAs(svc, "nobody", "wrong", "203.0.113.7"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("Login(unknown) error = %v, want %v", err, ErrInvalidCredentials)
+	}
+
+	if len(repo.events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(repo.events))
+	}
+	failed := repo.events[0]
+	if failed.Action != model.AuditLogin || failed.Result != model.AuditResultFailure || failed.IP != "203.0.113.7" {
+		t.Fatalf("first event = %+v, want failed login from 203.0.113.7", failed)
+	}
+	if failed.ActorID == nil || *failed.ActorID != 5 || failed.TargetID != "5" {
+		t.Fatalf("first event actor/target = %v/%q, want user 5", failed.ActorID, failed.TargetID)
+	}
+	if !failed.CreatedAt.Equal(clock.now) {
+		t.Fatalf("CreatedAt = %v, want %v", failed.CreatedAt, clock.now)
+	}
+	if anonymous := repo.events[1]; anonymous.ActorID != nil || anonymous.TargetID != "" {
+		t.Fatalf("unknown account event = %+v, want no actor or target", anonymous)
+	}
+}
+
+func TestAdminActionsAreAuditedWithRequestInfo(t *testing.T) {
+	repo := &fakeAuditRepository{}
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys, WithAuditLog(NewAuditLog(repo, 0)))
+	ctx := ContextWithRequestInfo(context.Background(), RequestInfo{IP: "198.51.100.4", UserAgent: "curl/8.5"})
+
+	if err := svc.ChangeUserRole(ctx, 1, 2, model.RoleAdmin); err != nil {
+		t.Fatalf("ChangeUserRole() error = %v", err)
+	}
+	if err := svc.DeleteUser(ctx, 1, 1); !errors.Is(err, ErrCannotModifySelf) {
+		t.Fatalf("DeleteUser(self) error = %v, want %v", err, ErrCannotModifySelf)
+	}
+
+	if len(repo.events) != 2 {
+		t.Fatalf("recorded %d events, want 2", len(repo.events))
+	}
+	changed := repo.events[0]
+	if changed.Action != model.AuditUserRoleChange || changed.Result != model.AuditResultSuccess ||
+		*changed.ActorID != 1 || changed.TargetID != "2" {
+		t.Fatalf("role change event = %+v", changed)
+	}
+	if changed.IP != "198.51.100.4" || changed.UserAgent != "curl/8.5" {
+		t.Fatalf("role change client = %q/%q, want request info", changed.IP, changed.UserAgent)
+	}
+	if refused := repo.events[1]; refused.Action != model.AuditUserDelete || refused.Result != model.AuditResultFailure {
+		t.Fatalf("refused delete event = %+v, want failed user.delete", refused)
+	}
+}
+
+func TestAuditPurgeExpiredAppliesRetention(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)}
+	repo := &fakeAuditRepository{events: []model.AuditEvent{
+		{Action: model.AuditLogin, CreatedAt: clock.now.Add(-40 * 24 * time.Hour)},
+		{Action: model.AuditLogin, CreatedAt: clock.now.Add(-10 * 24 * time.Hour)},
+	}}
+	audit := NewAuditLog(repo, 30*24*time.Hour)
+	audit.clock = clock
+
+	n, err := audit.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeExpired() error = %v", err)
+	}
+	if n != 1 || len(repo.events) != 1 {
+		t.Fatalf("purged %d, kept %d, want 1 and 1", n, len(repo.events))
+	}
+	if want := clock.now.Add(-30 * 24 * time.Hour); !repo.cutoff.Equal(want) {
+		t.Fatalf("cutoff = %v, want %v", repo.cutoff, want)
+	}
+}
+
+func TestAuditQueryValidatesFilter(t *testing.T) {
+	audit := NewAuditLog(&fakeAuditRepository{}, 0)
+	ctx := context.Background()
+
+	if _, err := audit.Query(ctx, AuditQuery{Result: "maybe"}); !errors.Is(err, ErrInvalidAuditFilter) {
+		t.Fatalf("Query(result=maybe) error = %v, want %v", err, ErrInvalidAuditFilter)
+	}
+	now := time.Now()
+	if _, err := audit.Query(ctx, AuditQuery{Since: now, Until: now.Add(-time.Hour)}); !errors.Is(err, ErrInvalidAuditFilter) {
+		t.Fatalf("Query(since > until) error = %v, want %v", err, ErrInvalidAuditFilter)
+	}
+}
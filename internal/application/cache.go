@@ -0,0 +1,115 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"sync"
+	"time"
+)
+
+// readThroughCache keeps loaded values for a while. Concurrent misses for the same key
+// share one load, so a burst of requests costs one provider or database call.
+type readThroughCache[V any] struct {
+	name  string
+	ttl   time.Duration
+	clock Clock
+
+	mu       sync.Mutex
+	entries  map[string]cacheEntry[V]
+	inflight map[string]*cacheLoad[V]
+	// generations changes whenever a key is set or invalidated, so a load that started
+	// before cannot store its older result.
+	generations map[string]uint64
+	hits        int64
+	misses      int64
+	coalesced   int64
+}
+
+type cacheEntry[V any] struct {
+	value    V
+	loadedAt time.Time
+}
+
+type cacheLoad[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+func newReadThroughCache[V any](name string, ttl time.Duration, clock Clock) *readThroughCache[V] {
+	return &readThroughCache[V]{
+		name:        name,
+		ttl:         ttl,
+		clock:       clock,
+		entries:     make(map[string]cacheEntry[V]),
+		inflight:    make(map[string]*cacheLoad[V]),
+		generations: make(map[string]uint64),
+	}
+}
+
+// get returns the cached value of key or loads it. Errors are not cached. The load is not
+// cancelled with the caller that started it, as other callers may be waiting for it.
+func (c *readThroughCache[V]) get(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	if e, ok := c.entries[key]; ok && c.clock.Now().Sub(e.loadedAt) < c.ttl {
+		c.hits++
+		c.mu.Unlock()
+		return e.value, nil
+	}
+	c.misses++
+	if l, ok := c.inflight[key]; ok {
+		c.coalesced++
+		c.mu.Unlock()
+		select {
+		case <-l.done:
+			return l.value, l.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+	l := &cacheLoad[V]{done: make(chan struct{})}
+	c.inflight[key] = l
+	generation := c.generations[key]
+	c.mu.Unlock()
+
+	l.value, l.err = load(context.WithoutCancel(ctx))
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if l.err == nil && c.generations[key] == generation {
+		c.entries[key] = cacheEntry[V]{value: l.value, loadedAt: c.clock.Now()}
+	}
+	c.mu.Unlock()
+	close(l.done)
+	return l.value, l.err
+}
+
+// set replaces the cached value of key, e.g. with one just saved.
+func (c *readThroughCache[V]) set(key string, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[key]++
+	c.entries[key] = cacheEntry[V]{value: value, loadedAt: c.clock.Now()}
+}
+
+// invalidate forgets key; the next get loads it again.
+func (c *readThroughCache[V]) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generations[key]++
+	delete(c.entries, key)
+}
+
+func (c *readThroughCache[V]) stats() model.CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return model.CacheStats{
+		Name:       c.name,
+		TTLSeconds: c.ttl.Seconds(),
+		Entries:    len(c.entries),
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Coalesced:  c.coalesced,
+	}
+}
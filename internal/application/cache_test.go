@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReadThroughCacheExpires(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)}
+	cache := newReadThroughCache[int]("test", time.Minute, clock)
+	loads := 0
+	load := func(ctx context.Context) (int, error) {
+		loads++
+		return loads, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		if v, err := cache.get(context.Background(), "k", load); err != nil || v != 1 {
+			t.Fatalf("get %d: %v, %v", i, v, err)
+		}
+	}
+	clock.Advance(time.Minute)
+	if v, _ := cache.get(context.Background(), "k", load); v != 2 {
+		t.Errorf("expected a reload after the TTL, got %d", v)
+	}
+
+	stats := cache.stats()
+	if stats.Hits != 2 || stats.Misses != 2 || stats.Entries != 1 || stats.TTLSeconds != 60 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestReadThroughCacheDoesNotKeepErrors(t *testing.T) {
+	cache := newReadThroughCache[int]("test", time.Minute, systemClock{})
+	fail := errors.New("provider down")
+	if _, err := cache.get(context.Background(), "k", func(context.Context) (int, error) { return 0, fail }); !errors.Is(err, fail) {
+		t.Fatalf("expected the load error, got %v", err)
+	}
+	if v, err := cache.get(context.Background(), "k", func(context.Context) (int, error) { return 7, nil }); err != nil || v != 7 {
+		t.Errorf("expected a fresh load after an error, got %v, %v", v, err)
+	}
+}
+
+func TestReadThroughCacheCoalescesConcurrentMisses(t *testing.T) {
+	cache := newReadThroughCache[int]("test", time.Minute, systemClock{})
+	release := make(chan struct{})
+	var loads atomic.Int32
+	load := func(ctx context.Context) (int, error) {
+		loads.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make(chan int, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, _ := cache.get(context.Background(), "k", load)
+			results <- v
+		}()
+	}
+	// Wait until every caller is either loading or waiting for the load.
+	for cache.stats().Misses < callers {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("expected one load for %d concurrent misses, got %d", callers, n)
+	}
+	for v := range results {
+		if v != 42 {
+			t.Errorf("a caller got %d", v)
+		}
+	}
+	if stats := cache.stats(); stats.Coalesced != callers-1 {
+		t.Errorf("expected %d coalesced misses, got %+v", callers-1, stats)
+	}
+}
+
+func TestReadThroughCacheInvalidationWinsOverLoadInFlight(t *testing.T) {
+	cache := newReadThroughCache[int]("test", time.Minute, systemClock{})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		v, _ := cache.get(context.Background(), "k", func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		done <- v
+	}()
+
+	<-started
+	cache.set("k", 2)
+	close(release)
+	if v := <-done; v != 1 {
+		t.Errorf("the loading caller should get its own result, got %d", v)
+	}
+	if v, _ := cache.get(context.Background(), "k", func(context.Context) (int, error) { return 3, nil }); v != 2 {
+		t.Errorf("the older load must not replace the value set meanwhile, got %d", v)
+	}
+
+	cache.invalidate("k")
+	if v, _ := cache.get(context.Background(), "k", func(context.Context) (int, error) { return 4, nil }); v != 4 {
+		t.Errorf("expected a reload after invalidate, got %d", v)
+	}
+}
@@ -0,0 +1,12 @@
+package application
+
+import "time"
+
+// Clock abstracts the current time so time-dependent rules can be tested.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
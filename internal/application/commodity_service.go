@@ -3,52 +3,273 @@ package application
 import (
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
+	"backend/internal/domain/units"
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 )
 
+// supportedCommodities lists every commodity the providers can price, in display order.
+var supportedCommodities = []string{"gold", "silver", "copper", "aluminum", "brent"}
+
+func isSupportedCommodity(name string) bool {
+	for _, c := range supportedCommodities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
 // MetalPriceProvider is the outbound port for fetching live metal prices.
 type MetalPriceProvider interface {
 	FetchPrice(ctx context.Context, metal string) (*model.Commodity, error)
 }
 
+// DefaultChartInterval applies when neither the request nor the user's preferences name one.
+const DefaultChartInterval = "max"
+
+// ChartIntervals maps the history windows clients can ask for to their length. The window
+// ends at the most recent stored price; "max" is only bounded by the row limit.
+var ChartIntervals = map[string]time.Duration{
+	"1w":  7 * 24 * time.Hour,
+	"1m":  30 * 24 * time.Hour,
+	"3m":  91 * 24 * time.Hour,
+	"6m":  182 * 24 * time.Hour,
+	"1y":  365 * 24 * time.Hour,
+	"5y":  5 * 365 * 24 * time.Hour,
+	"max": 0,
+}
+
+var ErrUnknownChartInterval = errors.New("unknown chart interval, expected one of 1w, 1m, 3m, 6m, 1y, 5y, max")
+
+var (
+	ErrQuarantineNotFound        = errors.New("quarantined price not found")
+	ErrQuarantineAlreadyReviewed = errors.New("quarantined price has already been reviewed")
+	ErrInvalidQuarantineStatus   = errors.New("status must be pending, approved or rejected")
+)
+
+// PriceOptions describes how prices are presented in responses.
+type PriceOptions struct {
+	// Currency is an ISO code from SupportedCurrencies; empty means USD.
+	Currency string
+	// Unit is a unit of measure understood by units.Parse; empty means kg.
+	Unit string
+}
+
+// validate checks the options against a commodity before any data is fetched.
+func (o PriceOptions) validate(commodity string) error {
+	if _, err := NormalizeCurrency(o.Currency); err != nil {
+		return err
+	}
+	unit, err := units.Parse(o.Unit)
+	if err != nil {
+		return err
+	}
+	_, err = units.KgPer(commodity, unit)
+	return err
+}
+
+const (
+	// DefaultLatestPriceTTL is how long a latest price is served from memory when not configured.
+	DefaultLatestPriceTTL = time.Minute
+	// DefaultLatestPriceMaxAge is the age past which a latest price is stale when not configured.
+	DefaultLatestPriceMaxAge = 15 * time.Minute
+)
+
 type CommodityService struct {
-	priceProvider MetalPriceProvider
-	commodityRepo repository.CommodityRepository
-	statusMu      sync.RWMutex
-	lastErrors    map[string]string
+	priceProvider  MetalPriceProvider
+	commodityRepo  repository.CommodityRepository
+	quarantineRepo repository.QuarantineRepository
+	validator      PriceValidator
+	fx             *FXService
+	statusMu       sync.RWMutex
+	lastErrors     map[string]string
+	latestTTL      time.Duration
+	// fromStorage serves latest prices from the saved ones while they are younger than
+	// maxAge, instead of fetching every one live.
+	fromStorage bool
+	maxAge      time.Duration
+	clock       Clock
+	// latestPrices holds the latest USD/kg price of each commodity, before conversion.
+	latestPrices *readThroughCache[model.LatestPrice]
+}
+
+type CommodityServiceOption func(*CommodityService)
+
+// WithLatestPriceTTL sets how long latest prices are served from memory.
+func WithLatestPriceTTL(ttl time.Duration) CommodityServiceOption {
+	return func(s *CommodityService) { s.latestTTL = ttl }
+}
+
+// WithStoredLatestPrices serves latest prices from the ones the refresh cycle saved. The
+// provider is only asked when the saved price is older than maxAge, or missing.
+func WithStoredLatestPrices(maxAge time.Duration) CommodityServiceOption {
+	return func(s *CommodityService) {
+		s.fromStorage = true
+		s.maxAge = maxAge
+	}
 }
 
-func NewCommodityService(priceProvider MetalPriceProvider, commodityRepo repository.CommodityRepository) *CommodityService {
-	return &CommodityService{
-		priceProvider: priceProvider,
-		commodityRepo: commodityRepo,
-		lastErrors:    make(map[string]string),
+// WithCommodityClock replaces the clock used to age prices and expire cached ones.
+func WithCommodityClock(clock Clock) CommodityServiceOption {
+	return func(s *CommodityService) { s.clock = clock }
+}
+
+func NewCommodityService(priceProvider MetalPriceProvider, commodityRepo repository.CommodityRepository, quarantineRepo repository.QuarantineRepository, validator PriceValidator, fx *FXService, opts ...CommodityServiceOption) *CommodityService {
+	s := &CommodityService{
+		priceProvider:  priceProvider,
+		commodityRepo:  commodityRepo,
+		quarantineRepo: quarantineRepo,
+		validator:      validator,
+		fx:             fx,
+		lastErrors:     make(map[string]string),
+		latestTTL:      DefaultLatestPriceTTL,
+		maxAge:         DefaultLatestPriceMaxAge,
+		clock:          systemClock{},
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.latestPrices = newReadThroughCache[model.LatestPrice]("latest_prices", s.latestTTL, s.clock)
+	return s
 }
 
-func (s *CommodityService) GetCommodityByType(ctx context.Context, commodityType string) (*model.Commodity, error) {
+// GetCommodityByType returns the latest price of a commodity with its freshness.
+func (s *CommodityService) GetCommodityByType(ctx context.Context, commodityType string, opts PriceOptions) (*model.LatestPrice, error) {
 	if commodityType == "" {
 		return nil, errors.New("'type' query parameter is required")
 	}
 
 	commodity := strings.ToLower(commodityType)
-	if commodity != "gold" && commodity != "silver" && commodity != "copper" && commodity != "aluminum" && commodity != "brent" {
-		return nil, errors.New("unknown commodity type")
+	if !isSupportedCommodity(commodity) {
+		return nil, ErrUnknownCommodity
+	}
+	if err := opts.validate(commodity); err != nil {
+		return nil, err
+	}
+
+	latest, err := s.latestPrices.get(ctx, commodity, func(ctx context.Context) (model.LatestPrice, error) {
+		return s.loadLatestPrice(ctx, commodity)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := s.present(ctx, []model.Commodity{latest.Commodity}, opts)
+	if err != nil {
+		return nil, err
+	}
+	latest.Commodity = converted[0]
+	latest.AgeSeconds, latest.Stale = s.freshness(latest.Commodity)
+	return &latest, nil
+}
+
+// loadLatestPrice reads the saved price, or fetches one live when it is missing, too old
+// or not read from storage at all. A stale saved price is still served when the provider
+// fails.
+func (s *CommodityService) loadLatestPrice(ctx context.Context, commodity string) (model.LatestPrice, error) {
+	var stored *model.Commodity
+	if s.fromStorage {
+		latest, err := s.commodityRepo.GetLatestPrice(ctx, commodity)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return model.LatestPrice{}, fmt.Errorf("load latest %s price: %w", commodity, err)
+		}
+		if err == nil {
+			if _, stale := s.freshness(latest); !stale {
+				return model.LatestPrice{Commodity: latest, PriceFreshness: model.PriceFreshness{Source: model.PriceSourceStorage}}, nil
+			}
+			stored = &latest
+		}
 	}
 
-	return s.priceProvider.FetchPrice(ctx, commodity)
+	live, err := s.priceProvider.FetchPrice(ctx, commodity)
+	if err != nil {
+		if stored != nil {
+			return model.LatestPrice{Commodity: *stored, PriceFreshness: model.PriceFreshness{Source: model.PriceSourceStorage}}, nil
+		}
+		return model.LatestPrice{}, err
+	}
+	return model.LatestPrice{Commodity: *live, PriceFreshness: model.PriceFreshness{Source: model.PriceSourceLive}}, nil
 }
 
-func (s *CommodityService) GetHistory(ctx context.Context, name string, limit int) ([]model.Commodity, error) {
+// freshness returns how long ago the price was fetched and whether that is past maxAge.
+func (s *CommodityService) freshness(c model.Commodity) (float64, bool) {
+	fetched := c.FetchedAt
+	if fetched.IsZero() {
+		fetched = c.Date
+	}
+	age := max(s.clock.Now().Sub(fetched), 0)
+	return age.Seconds(), age > s.maxAge
+}
+
+// GetHistory returns up to limit prices, newest first, within interval (a key of
+// ChartIntervals; empty means DefaultChartInterval).
+func (s *CommodityService) GetHistory(ctx context.Context, name string, limit int, interval string, opts PriceOptions) ([]model.Commodity, error) {
 	if limit <= 0 {
 		limit = 100
 	}
-	return s.commodityRepo.GetPriceHistory(ctx, name, limit)
+	if interval == "" {
+		interval = DefaultChartInterval
+	}
+	window, ok := ChartIntervals[strings.ToLower(interval)]
+	if !ok {
+		return nil, ErrUnknownChartInterval
+	}
+	if err := opts.validate(strings.ToLower(name)); err != nil {
+		return nil, err
+	}
+
+	history, err := s.commodityRepo.GetPriceHistory(ctx, name, limit)
+	if err != nil {
+		return nil, err
+	}
+	if window > 0 && len(history) > 0 {
+		cutoff := history[0].Date.Add(-window)
+		for i, c := range history {
+			if c.Date.Before(cutoff) {
+				history = history[:i]
+				break
+			}
+		}
+	}
+	return s.present(ctx, history, opts)
+}
+
+// present converts stored USD/kg prices according to the requested options: PriceKg is
+// expressed in the requested currency, and Price/Unit in the requested currency and unit.
+func (s *CommodityService) present(ctx context.Context, prices []model.Commodity, opts PriceOptions) ([]model.Commodity, error) {
+	unit, err := units.Parse(opts.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	converted, err := s.fx.Convert(ctx, prices, opts.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]model.Commodity, len(converted))
+	for i, c := range converted {
+		price, err := units.FromPerKg(c.PriceKg, c.Name, unit)
+		if err != nil {
+			return nil, err
+		}
+		c.Price = price
+		c.Unit = withUnit(c.Unit, unit)
+		out[i] = c
+	}
+	return out, nil
+}
+
+// withUnit swaps the unit part of a label such as "USD/kg".
+func withUnit(label string, unit units.Unit) string {
+	currency, _, _ := strings.Cut(label, "/")
+	return currency + "/" + string(unit)
 }
 
 func (s *CommodityService) UpdatePreciousPrices(ctx context.Context) error {
@@ -71,12 +292,24 @@ func (s *CommodityService) updateSymbols(ctx context.Context, symbols []string)
 			continue
 		}
 
+		quarantined, err := s.screenPrice(ctx, *commodity)
+		if err != nil {
+			s.setLastError(symbol, err)
+			failed = append(failed, fmt.Sprintf("validate %s: %v", symbol, err))
+			continue
+		}
+		if quarantined {
+			failed = append(failed, fmt.Sprintf("quarantine %s: %s", symbol, s.getLastError(symbol)))
+			continue
+		}
+
 		if err := s.commodityRepo.Save(ctx, *commodity); err != nil {
 			s.setLastError(symbol, err)
 			failed = append(failed, fmt.Sprintf("save %s: %v", symbol, err))
 			continue
 		}
 
+		s.latestPrices.set(symbol, model.LatestPrice{Commodity: *commodity, PriceFreshness: model.PriceFreshness{Source: model.PriceSourceStorage}})
 		s.clearLastError(symbol)
 		successes++
 	}
@@ -91,11 +324,117 @@ func (s *CommodityService) updateSymbols(ctx context.Context, symbols []string)
 	return nil
 }
 
+// screenPrice checks a fetched price against recent history and moves it to the review
+// queue when it looks wrong. It reports whether the price was quarantined.
+func (s *CommodityService) screenPrice(ctx context.Context, commodity model.Commodity) (bool, error) {
+	history, err := s.commodityRepo.GetPriceHistory(ctx, commodity.Name, priceValidationWindow)
+	if err != nil {
+		return false, fmt.Errorf("load recent history: %w", err)
+	}
+
+	reason := s.validator.Check(commodity, history)
+	if reason == "" {
+		return false, nil
+	}
+
+	err = s.quarantineRepo.Save(ctx, model.QuarantinedPrice{
+		Name:        commodity.Name,
+		Date:        commodity.Date,
+		PriceKg:     commodity.PriceKg,
+		Unit:        commodity.Unit,
+		FetchedAt:   commodity.FetchedAt,
+		NativePrice: commodity.NativePrice,
+		NativeUnit:  commodity.NativeUnit,
+		Reason:      reason,
+		Status:      model.QuarantineStatusPending,
+	})
+	if err != nil {
+		return false, fmt.Errorf("quarantine price: %w", err)
+	}
+
+	s.setLastError(commodity.Name, fmt.Errorf("price quarantined: %s", reason))
+	return true, nil
+}
+
+func (s *CommodityService) ListQuarantined(ctx context.Context, status string, limit int) ([]model.QuarantinedPrice, error) {
+	switch status {
+	case "", model.QuarantineStatusPending, model.QuarantineStatusApproved, model.QuarantineStatusRejected:
+	default:
+		return nil, ErrInvalidQuarantineStatus
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.quarantineRepo.List(ctx, status, limit)
+}
+
+// ApproveQuarantined releases a quarantined price into the commodities table.
+func (s *CommodityService) ApproveQuarantined(ctx context.Context, id int64, reviewerID uint) error {
+	q, err := s.pendingQuarantined(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.commodityRepo.Save(ctx, model.Commodity{
+		Name:        q.Name,
+		Date:        q.Date,
+		PriceKg:     q.PriceKg,
+		Unit:        q.Unit,
+		FetchedAt:   q.FetchedAt,
+		NativePrice: q.NativePrice,
+		NativeUnit:  q.NativeUnit,
+	}); err != nil {
+		return fmt.Errorf("save approved price: %w", err)
+	}
+
+	s.latestPrices.invalidate(q.Name)
+
+	if err := s.markReviewed(ctx, id, model.QuarantineStatusApproved, reviewerID); err != nil {
+		return err
+	}
+	s.clearLastError(q.Name)
+	return nil
+}
+
+// RejectQuarantined discards a quarantined price for good.
+func (s *CommodityService) RejectQuarantined(ctx context.Context, id int64, reviewerID uint) error {
+	if _, err := s.pendingQuarantined(ctx, id); err != nil {
+		return err
+	}
+	return s.markReviewed(ctx, id, model.QuarantineStatusRejected, reviewerID)
+}
+
+func (s *CommodityService) pendingQuarantined(ctx context.Context, id int64) (model.QuarantinedPrice, error) {
+	q, err := s.quarantineRepo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.QuarantinedPrice{}, ErrQuarantineNotFound
+		}
+		return model.QuarantinedPrice{}, err
+	}
+	if q.Status != model.QuarantineStatusPending {
+		return model.QuarantinedPrice{}, ErrQuarantineAlreadyReviewed
+	}
+	return q, nil
+}
+
+func (s *CommodityService) markReviewed(ctx context.Context, id int64, status string, reviewerID uint) error {
+	err := s.quarantineRepo.UpdateStatus(ctx, id, status, reviewerID, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrQuarantineAlreadyReviewed
+	}
+	return err
+}
+
+// CacheStats reports the hits and misses of the latest price cache.
+func (s *CommodityService) CacheStats() model.CacheStats {
+	return s.latestPrices.stats()
+}
+
 func (s *CommodityService) GetStatuses(ctx context.Context) ([]model.CommodityStatus, error) {
-	symbols := []string{"gold", "silver", "copper", "aluminum", "brent"}
-	statuses := make([]model.CommodityStatus, 0, len(symbols))
+	statuses := make([]model.CommodityStatus, 0, len(supportedCommodities))
 
-	for _, symbol := range symbols {
+	for _, symbol := range supportedCommodities {
 		status := model.CommodityStatus{
 			Name:   symbol,
 			Source: sourceFor(symbol),
@@ -113,6 +452,10 @@ func (s *CommodityService) GetStatuses(ctx context.Context) ([]model.CommoditySt
 			status.LastError = lastErr
 		}
 
+		if pending, err := s.quarantineRepo.CountPending(ctx, symbol); err == nil {
+			status.Quarantined = pending
+		}
+
 		statuses = append(statuses, status)
 	}
 
@@ -0,0 +1,107 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"testing"
+	"time"
+)
+
+// countingPriceProvider counts the live fetches made through it.
+type countingPriceProvider struct {
+	fakePriceProvider
+	fetches int
+}
+
+func (p *countingPriceProvider) FetchPrice(ctx context.Context, metal string) (*model.Commodity, error) {
+	p.fetches++
+	return p.fakePriceProvider.FetchPrice(ctx, metal)
+}
+
+func TestGetCommodityByTypeServesLatestPriceFromCache(t *testing.T) {
+	provider := &countingPriceProvider{fakePriceProvider: fakePriceProvider{prices: map[string]*model.Commodity{
+		"silver": {Name: "silver", Date: time.Now(), PriceKg: 30, Unit: "USD/kg"},
+	}}}
+	repo := &fakeCommodityRepository{}
+	svc := NewCommodityService(provider, repo, &fakeQuarantineRepository{}, NewPriceValidator(25, 8), NewFXService(nil, &fakeFXRateRepository{}))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		got, err := svc.GetCommodityByType(ctx, "Silver", PriceOptions{Unit: "g"})
+		if err != nil {
+			t.Fatalf("GetCommodityByType() error = %v", err)
+		}
+		if got.PriceKg != 30 || got.Price != 0.03 || got.Unit != "USD/g" || got.Source != model.PriceSourceLive {
+			t.Fatalf("presented = %+v, want a live 30 USD/kg shown as 0.03 USD/g", got)
+		}
+	}
+	if provider.fetches != 1 {
+		t.Fatalf("expected one live fetch, got %d", provider.fetches)
+	}
+
+	// The refresh cycle saves a new price, which replaces the cached one.
+	provider.prices["silver"] = &model.Commodity{Name: "silver", Date: time.Now(), PriceKg: 31, Unit: "USD/kg"}
+	if err := svc.UpdatePreciousPrices(ctx); err != nil {
+		t.Fatalf("UpdatePreciousPrices() error = %v", err)
+	}
+	got, err := svc.GetCommodityByType(ctx, "silver", PriceOptions{})
+	if err != nil || got.PriceKg != 31 {
+		t.Fatalf("after refresh: %+v, %v; want the saved price", got, err)
+	}
+	if provider.fetches != 3 {
+		t.Errorf("expected only the refresh cycle's fetches, got %d in total", provider.fetches)
+	}
+
+	stats := svc.CacheStats()
+	if stats.Hits != 3 || stats.Misses != 1 {
+		t.Errorf("unexpected cache stats: %+v", stats)
+	}
+}
+
+func TestGetCommodityByTypeServesStoredPriceUntilStale(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)}
+	stored := model.Commodity{Name: "gold", Date: clock.now.Add(-time.Hour), PriceKg: 75000, Unit: "USD/kg", FetchedAt: clock.now.Add(-10 * time.Minute)}
+	repo := &fakeCommodityRepository{history: map[string][]model.Commodity{"gold": {stored}}}
+	provider := &countingPriceProvider{fakePriceProvider: fakePriceProvider{prices: map[string]*model.Commodity{}}}
+	newService := func() *CommodityService {
+		return NewCommodityService(provider, repo, &fakeQuarantineRepository{}, NewPriceValidator(25, 8), NewFXService(nil, &fakeFXRateRepository{}),
+			WithStoredLatestPrices(15*time.Minute), WithCommodityClock(clock))
+	}
+	ctx := context.Background()
+
+	got, err := newService().GetCommodityByType(ctx, "gold", PriceOptions{})
+	if err != nil {
+		t.Fatalf("GetCommodityByType() error = %v", err)
+	}
+	if got.PriceKg != 75000 || got.Source != model.PriceSourceStorage || got.AgeSeconds != 600 || got.Stale {
+		t.Fatalf("got %+v, want the fresh stored price, 600s old", got)
+	}
+	if provider.fetches != 0 {
+		t.Fatalf("a fresh stored price should not be fetched live, got %d fetches", provider.fetches)
+	}
+
+	// Past the maximum age, with the provider down, the stored price is served as stale.
+	clock.Advance(10 * time.Minute)
+	got, err = newService().GetCommodityByType(ctx, "gold", PriceOptions{})
+	if err != nil {
+		t.Fatalf("GetCommodityByType() error = %v", err)
+	}
+	if provider.fetches != 1 || got.Source != model.PriceSourceStorage || !got.Stale || got.AgeSeconds != 1200 {
+		t.Fatalf("got %+v after %d fetches, want a stale stored price after one live attempt", got, provider.fetches)
+	}
+
+	// With the provider back, the stale price is replaced by a live one.
+	provider.prices["gold"] = &model.Commodity{Name: "gold", Date: clock.now, PriceKg: 76000, Unit: "USD/kg", FetchedAt: clock.now}
+	got, err = newService().GetCommodityByType(ctx, "gold", PriceOptions{})
+	if err != nil {
+		t.Fatalf("GetCommodityByType() error = %v", err)
+	}
+	if got.PriceKg != 76000 || got.Source != model.PriceSourceLive || got.Stale || got.AgeSeconds != 0 {
+		t.Fatalf("got %+v, want the live price", got)
+	}
+
+	// Nothing stored and no provider: the provider's error is returned.
+	if _, err := newService().GetCommodityByType(ctx, "copper", PriceOptions{}); err == nil {
+		t.Error("expected an error without any price")
+	}
+}
@@ -0,0 +1,118 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"database/sql"
+	"time"
+)
+
+type fakeCommodityRepository struct {
+	history map[string][]model.Commodity // DESC by date, like the postgres adapter
+	saved   []model.Commodity
+}
+
+func (f *fakeCommodityRepository) Save(ctx context.Context, stock model.Commodity) error {
+	f.saved = append(f.saved, stock)
+	return nil
+}
+
+func (f *fakeCommodityRepository) SaveBatch(ctx context.Context, stocks []model.Commodity) error {
+	f.saved = append(f.saved, stocks...)
+	return nil
+}
+
+func (f *fakeCommodityRepository) GetLatestPrice(ctx context.Context, commodity string) (model.Commodity, error) {
+	if len(f.history[commodity]) == 0 {
+		return model.Commodity{}, sql.ErrNoRows
+	}
+	return f.history[commodity][0], nil
+}
+
+func (f *fakeCommodityRepository) GetPriceHistory(ctx context.Context, commodity string, limit int) ([]model.Commodity, error) {
+	h := f.history[commodity]
+	if len(h) > limit {
+		h = h[:limit]
+	}
+	return h, nil
+}
+
+func (f *fakeCommodityRepository) GetDailyCloses(ctx context.Context, commodity string, days int) ([]model.Commodity, error) {
+	var closes []model.Commodity
+	for _, c := range f.history[commodity] {
+		if n := len(closes); n > 0 && closes[n-1].Date.UTC().Truncate(24*time.Hour).Equal(c.Date.UTC().Truncate(24*time.Hour)) {
+			continue
+		}
+		if len(closes) == days {
+			break
+		}
+		closes = append(closes, c)
+	}
+	return closes, nil
+}
+
+func (f *fakeCommodityRepository) HasRecentData(ctx context.Context) (bool, error) {
+	return len(f.history) > 0, nil
+}
+
+type fakePriceProvider struct {
+	prices map[string]*model.Commodity
+}
+
+func (f *fakePriceProvider) FetchPrice(ctx context.Context, metal string) (*model.Commodity, error) {
+	if c, ok := f.prices[metal]; ok {
+		return c, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+type fakeQuarantineRepository struct {
+	entries []model.QuarantinedPrice
+}
+
+func (f *fakeQuarantineRepository) Save(ctx context.Context, price model.QuarantinedPrice) error {
+	price.ID = int64(len(f.entries) + 1)
+	f.entries = append(f.entries, price)
+	return nil
+}
+
+func (f *fakeQuarantineRepository) FindByID(ctx context.Context, id int64) (model.QuarantinedPrice, error) {
+	for _, e := range f.entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return model.QuarantinedPrice{}, sql.ErrNoRows
+}
+
+func (f *fakeQuarantineRepository) List(ctx context.Context, status string, limit int) ([]model.QuarantinedPrice, error) {
+	var out []model.QuarantinedPrice
+	for _, e := range f.entries {
+		if status == "" || e.Status == status {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeQuarantineRepository) CountPending(ctx context.Context, commodity string) (int, error) {
+	n := 0
+	for _, e := range f.entries {
+		if e.Name == commodity && e.Status == model.QuarantineStatusPending {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeQuarantineRepository) UpdateStatus(ctx context.Context, id int64, status string, reviewerID uint, reviewedAt time.Time) error {
+	for i, e := range f.entries {
+		if e.ID == id && e.Status == model.QuarantineStatusPending {
+			f.entries[i].Status = status
+			f.entries[i].ReviewedBy = &reviewerID
+			f.entries[i].ReviewedAt = &reviewedAt
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
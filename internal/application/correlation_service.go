@@ -12,16 +12,49 @@ import (
 	"time"
 )
 
+const (
+	// Rolling correlations for regime detection are computed on a year of daily closes.
+	regimeHistoryDays       = 365
+	regimeCorrelationWindow = 30
+	regimeBaselineWindow    = 60
+	regimeThreshold         = 2.5
+)
+
+// DefaultLatestCorrelationTTL is how long a pair's latest correlation is served from memory
+// when not configured.
+const DefaultLatestCorrelationTTL = 5 * time.Minute
+
+var (
+	ErrInvalidCorrelationPair      = errors.New("'a' and 'b' are required")
+	ErrInsufficientCorrelationData = errors.New("insufficient overlapping data points")
+)
+
 type CorrelationService struct {
 	correlationRepo repository.CorrelationRepository
 	commodityRepo   repository.CommodityRepository
+	latestTTL       time.Duration
+	// latest holds the latest correlation of each pair, keyed by latestKey.
+	latest *readThroughCache[model.Correlation]
+}
+
+type CorrelationServiceOption func(*CorrelationService)
+
+// WithLatestCorrelationTTL sets how long latest correlations are served from memory.
+func WithLatestCorrelationTTL(ttl time.Duration) CorrelationServiceOption {
+	return func(s *CorrelationService) { s.latestTTL = ttl }
 }
 
-func NewCorrelationService(correlationRepo repository.CorrelationRepository, commodityRepo repository.CommodityRepository) *CorrelationService {
-	return &CorrelationService{
+func NewCorrelationService(correlationRepo repository.CorrelationRepository, commodityRepo repository.CommodityRepository, opts ...CorrelationServiceOption) *CorrelationService {
+	s := &CorrelationService{
 		correlationRepo: correlationRepo,
 		commodityRepo:   commodityRepo,
+		latestTTL:       DefaultLatestCorrelationTTL,
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	s.latest = newReadThroughCache[model.Correlation]("latest_correlations", s.latestTTL, systemClock{})
+	return s
 }
 
 func (s *CorrelationService) GetCorrelationByType(ctx context.Context, correlationType string) (*model.Correlation, error) {
@@ -37,12 +70,27 @@ func (s *CorrelationService) GetCorrelationByType(ctx context.Context, correlati
 	commodityA := strings.ToLower(parts[0])
 	commodityB := strings.ToLower(parts[1])
 
-	correlation, err := s.correlationRepo.GetLatest(ctx, commodityA, commodityB)
+	correlation, err := s.latest.get(ctx, latestKey(commodityA, commodityB), func(ctx context.Context) (model.Correlation, error) {
+		c, err := s.correlationRepo.GetLatest(ctx, commodityA, commodityB)
+		if err != nil {
+			return model.Correlation{}, err
+		}
+		return *c, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return correlation, nil
+	return &correlation, nil
+}
+
+// CacheStats reports the hits and misses of the latest correlation cache.
+func (s *CorrelationService) CacheStats() model.CacheStats {
+	return s.latest.stats()
+}
+
+func latestKey(commodityA, commodityB string) string {
+	return commodityA + "|" + commodityB
 }
 
 // UpdateCorrelations computes and saves the latest correlation for a given pair.
@@ -90,7 +138,7 @@ func (s *CorrelationService) UpdateCorrelations(ctx context.Context, commodityA,
 	}
 	
 	if len(x) < 5 {
-		return fmt.Errorf("insufficient overlapping data points (found %d)", len(x))
+		return fmt.Errorf("%w (found %d)", ErrInsufficientCorrelationData, len(x))
 	}
 	
 	pearsonR, err := algorithm.Pearson(x, y)
@@ -118,7 +166,26 @@ func (s *CorrelationService) UpdateCorrelations(ctx context.Context, commodityA,
 		DataPoints:      len(x),
 	}
 	
-	return s.correlationRepo.Save(ctx, correlation)
+	if err := s.correlationRepo.Save(ctx, correlation); err != nil {
+		return err
+	}
+	// The stored row has an id and creation time the computed one lacks; reload it next time.
+	s.latest.invalidate(latestKey(commodityA, commodityB))
+	return nil
+}
+
+// RecomputeCorrelation computes the pair's correlation now rather than at the next refresh
+// and returns the stored result.
+func (s *CorrelationService) RecomputeCorrelation(ctx context.Context, commodityA, commodityB string) (*model.Correlation, error) {
+	commodityA = strings.ToLower(strings.TrimSpace(commodityA))
+	commodityB = strings.ToLower(strings.TrimSpace(commodityB))
+	if commodityA == "" || commodityB == "" {
+		return nil, ErrInvalidCorrelationPair
+	}
+	if err := s.UpdateCorrelations(ctx, commodityA, commodityB); err != nil {
+		return nil, err
+	}
+	return s.correlationRepo.GetLatest(ctx, commodityA, commodityB)
 }
 
 func (s *CorrelationService) GetHistory(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.Correlation, error) {
@@ -127,3 +194,82 @@ func (s *CorrelationService) GetHistory(ctx context.Context, commodityA, commodi
 	}
 	return s.correlationRepo.GetHistory(ctx, commodityA, commodityB, limit)
 }
+
+// DetectRegimeChanges scans the pair's rolling correlation for breaks from its historical
+// norm and records any new events. It returns the number of events found in the scan.
+// Daily closes only change once a day, so there is no point in running it more often.
+func (s *CorrelationService) DetectRegimeChanges(ctx context.Context, commodityA, commodityB string) (int, error) {
+	historyA, err := s.commodityRepo.GetDailyCloses(ctx, commodityA, regimeHistoryDays)
+	if err != nil {
+		return 0, fmt.Errorf("fetch %s history: %w", commodityA, err)
+	}
+	historyB, err := s.commodityRepo.GetDailyCloses(ctx, commodityB, regimeHistoryDays)
+	if err != nil {
+		return 0, fmt.Errorf("fetch %s history: %w", commodityB, err)
+	}
+
+	dates, x, y := alignDailyCloses(historyA, historyB)
+	if len(x) < regimeCorrelationWindow+regimeBaselineWindow+1 {
+		return 0, fmt.Errorf("insufficient overlapping daily data for regime detection (found %d)", len(x))
+	}
+
+	rolling, err := algorithm.RollingCorrelation(x, y, regimeCorrelationWindow)
+	if err != nil {
+		return 0, fmt.Errorf("rolling correlation: %w", err)
+	}
+
+	shifts := algorithm.DetectRegimeShifts(rolling, regimeBaselineWindow, regimeThreshold)
+	events := make([]*model.CorrelationEvent, 0, len(shifts))
+	for _, shift := range shifts {
+		events = append(events, &model.CorrelationEvent{
+			CommodityA: commodityA,
+			CommodityB: commodityB,
+			// rolling[i] covers days i..i+window-1, so the break is observed on the window's last day.
+			StartDate: dates[shift.Index+regimeCorrelationWindow-1],
+			Kind:      shift.Kind,
+			Baseline:  shift.Baseline,
+			Value:     shift.Value,
+			Magnitude: shift.Magnitude,
+			Window:    regimeCorrelationWindow,
+		})
+	}
+
+	if err := s.correlationRepo.SaveEvents(ctx, events); err != nil {
+		return 0, fmt.Errorf("save regime events: %w", err)
+	}
+	return len(events), nil
+}
+
+func (s *CorrelationService) GetEvents(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.CorrelationEvent, error) {
+	if (commodityA == "") != (commodityB == "") {
+		return nil, errors.New("'a' and 'b' must be provided together")
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	return s.correlationRepo.GetEvents(ctx, strings.ToLower(commodityA), strings.ToLower(commodityB), limit)
+}
+
+// alignDailyCloses pairs the daily closes of two DESC-ordered histories on matching days.
+func alignDailyCloses(historyA, historyB []model.Commodity) ([]time.Time, []float64, []float64) {
+	datesA, closesA := dailyCloses(historyA)
+	datesB, closesB := dailyCloses(historyB)
+
+	var dates []time.Time
+	var x, y []float64
+	for i, j := 0, 0; i < len(datesA) && j < len(datesB); {
+		switch {
+		case datesA[i].Before(datesB[j]):
+			i++
+		case datesB[j].Before(datesA[i]):
+			j++
+		default:
+			dates = append(dates, datesA[i])
+			x = append(x, closesA[i])
+			y = append(y, closesB[j])
+			i++
+			j++
+		}
+	}
+	return dates, x, y
+}
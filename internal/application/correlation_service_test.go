@@ -0,0 +1,115 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// fakeCorrelationRepository records saved correlations and events; other methods are not
+// used by the tests.
+type fakeCorrelationRepository struct {
+	repository.CorrelationRepository
+	saved  []*model.Correlation
+	events []*model.CorrelationEvent
+}
+
+func (f *fakeCorrelationRepository) Save(ctx context.Context, c *model.Correlation) error {
+	f.saved = append(f.saved, c)
+	return nil
+}
+
+func (f *fakeCorrelationRepository) GetLatest(ctx context.Context, commodityA, commodityB string) (*model.Correlation, error) {
+	for i := len(f.saved) - 1; i >= 0; i-- {
+		if c := f.saved[i]; c.CommodityA == commodityA && c.CommodityB == commodityB {
+			return c, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (f *fakeCorrelationRepository) SaveEvents(ctx context.Context, events []*model.CorrelationEvent) error {
+	f.events = append(f.events, events...)
+	return nil
+}
+
+func TestDetectRegimeChangesReadsDailyClosesOfIntradayHistory(t *testing.T) {
+	// 200 days of 15-minute ticks; the pair moves together, then in opposite directions.
+	const days, ticksPerDay = 200, 96
+	rng := rand.New(rand.NewSource(1))
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	gold, silver := 1000.0, 20.0
+	var historyA, historyB []model.Commodity
+	for d := 0; d < days; d++ {
+		move := rng.NormFloat64()
+		gold += move * 10
+		if d < 150 {
+			silver += move*0.2 + rng.NormFloat64()*0.02
+		} else {
+			silver -= move*0.2 + rng.NormFloat64()*0.02
+		}
+		for tick := 0; tick < ticksPerDay; tick++ {
+			at := start.AddDate(0, 0, d).Add(time.Duration(tick) * 15 * time.Minute)
+			historyA = append(historyA, model.Commodity{Name: "gold", Date: at, PriceKg: gold})
+			historyB = append(historyB, model.Commodity{Name: "silver", Date: at, PriceKg: silver})
+		}
+	}
+	reverse := func(h []model.Commodity) []model.Commodity {
+		for i, j := 0, len(h)-1; i < j; i, j = i+1, j-1 {
+			h[i], h[j] = h[j], h[i]
+		}
+		return h
+	}
+	commodities := &fakeCommodityRepository{history: map[string][]model.Commodity{
+		"gold":   reverse(historyA),
+		"silver": reverse(historyB),
+	}}
+	correlations := &fakeCorrelationRepository{}
+	svc := NewCorrelationService(correlations, commodities)
+
+	n, err := svc.DetectRegimeChanges(context.Background(), "gold", "silver")
+	if err != nil {
+		t.Fatalf("DetectRegimeChanges() error = %v", err)
+	}
+	if n == 0 || len(correlations.events) != n {
+		t.Fatalf("found %d events, saved %d; want the break to be detected", n, len(correlations.events))
+	}
+	for _, e := range correlations.events {
+		if e.StartDate.Before(start.AddDate(0, 0, 150)) {
+			t.Fatalf("event on %v precedes the break", e.StartDate)
+		}
+	}
+}
+
+func TestRecomputeCorrelation(t *testing.T) {
+	start := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	var gold, silver []model.Commodity
+	for d := 9; d >= 0; d-- {
+		at := start.AddDate(0, 0, d)
+		gold = append(gold, model.Commodity{Name: "gold", Date: at, PriceKg: 1000 + float64(d)})
+		silver = append(silver, model.Commodity{Name: "silver", Date: at, PriceKg: 20 + float64(d)})
+	}
+	commodities := &fakeCommodityRepository{history: map[string][]model.Commodity{"gold": gold, "silver": silver}}
+	svc := NewCorrelationService(&fakeCorrelationRepository{}, commodities)
+	ctx := context.Background()
+
+	c, err := svc.RecomputeCorrelation(ctx, "Gold", "silver")
+	if err != nil {
+		t.Fatalf("RecomputeCorrelation() error = %v", err)
+	}
+	if c.CommodityA != "gold" || c.CommodityB != "silver" || c.DataPoints != 10 {
+		t.Fatalf("got %+v, want gold-silver over 10 points", c)
+	}
+
+	if _, err := svc.RecomputeCorrelation(ctx, "gold", ""); !errors.Is(err, ErrInvalidCorrelationPair) {
+		t.Fatalf("missing commodity: err = %v, want ErrInvalidCorrelationPair", err)
+	}
+	if _, err := svc.RecomputeCorrelation(ctx, "gold", "copper"); !errors.Is(err, ErrInsufficientCorrelationData) {
+		t.Fatalf("no overlap: err = %v, want ErrInsufficientCorrelationData", err)
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	emailVerificationTTL = 48 * time.Hour
+	passwordResetTTL     = time.Hour
+)
+
+var (
+	ErrAccountEmailsUnavailable = errors.New("account emails are not enabled on this server")
+	ErrInvalidEmailToken        = errors.New("invalid or expired link")
+	ErrEmailAlreadyVerified     = errors.New("email already verified")
+	ErrEmailNotVerified         = errors.New("email address not verified")
+)
+
+// SendVerificationEmail (re)sends the verification link to the user's address.
+func (s *UserService) SendVerificationEmail(ctx context.Context, userID uint) error {
+	if s.tokenRepo == nil || s.mailer == nil {
+		return ErrAccountEmailsUnavailable
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueUserToken(ctx, user.ID, model.TokenPurposeEmailVerification, emailVerificationTTL, "")
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, Email{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address by opening this link:\n\n%s\n\nThe link expires in %d hours.\n",
+			user.Username, s.appLink("/verify-email", token), int(emailVerificationTTL.Hours())),
+	})
+}
+
+// VerifyEmail consumes a verification token and marks the address as verified.
+func (s *UserService) VerifyEmail(ctx context.Context, token string) error {
+	if s.tokenRepo == nil {
+		return ErrAccountEmailsUnavailable
+	}
+
+	t, err := s.consumeUserToken(ctx, model.TokenPurposeEmailVerification, token)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.MarkEmailVerified(ctx, t.UserID); err != nil {
+		return err
+	}
+	s.auditUserAction(ctx, t.UserID, t.UserID, model.AuditEmailVerify, "", nil)
+	return nil
+}
+
+// sendVerificationAfterRegister is best effort: the account exists either way and the
+// user can ask for a new link.
+func (s *UserService) sendVerificationAfterRegister(ctx context.Context, email string) {
+	if s.tokenRepo == nil || s.mailer == nil {
+		return
+	}
+	user, err := s.userRepo.FindByUsernameOrEmail(ctx, email)
+	if err != nil {
+		log.Printf("Error loading new user %s for verification email: %v", email, err)
+		return
+	}
+	if err := s.SendVerificationEmail(ctx, user.ID); err != nil {
+		log.Printf("Error sending verification email to user %d: %v", user.ID, err)
+	}
+}
+
+// issueUserToken stores the hash of a new single-use token and returns the token itself.
+// payload is stored with the token and returned when it is consumed.
+func (s *UserService) issueUserToken(ctx context.Context, userID uint, purpose string, ttl time.Duration, payload string) (string, error) {
+	token, hash, err := auth.NewOneTimeToken()
+	if err != nil {
+		return "", err
+	}
+	now := s.clock.Now()
+	if err := s.tokenRepo.Create(ctx, model.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+		Payload:   payload,
+	}); err != nil {
+		return "", fmt.Errorf("store %s token: %w", purpose, err)
+	}
+	return token, nil
+}
+
+func (s *UserService) consumeUserToken(ctx context.Context, purpose, token string) (model.UserToken, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return model.UserToken{}, ErrInvalidEmailToken
+	}
+	t, err := s.tokenRepo.Consume(ctx, purpose, auth.HashRefreshToken(token), s.clock.Now())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserToken{}, ErrInvalidEmailToken
+		}
+		return model.UserToken{}, err
+	}
+	return t, nil
+}
+
+// appLink builds a frontend URL carrying a token.
+func (s *UserService) appLink(path, token string) string {
+	return strings.TrimRight(s.appBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
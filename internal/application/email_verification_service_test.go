@@ -0,0 +1,204 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var emailLinkPattern = regexp.MustCompile(`https?://\S+`)
+
+type emailFlowFixture struct {
+	svc      *UserService
+	user     *model.User
+	clock    *fakeClock
+	mailer   *fakeMailer
+	sessions *fakeSessionRepository
+	// others are further accounts, for uniqueness checks.
+	others []model.User
+}
+
+func newEmailFlowFixture(t *testing.T) *emailFlowFixture {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	f := &emailFlowFixture{
+		user:     &model.User{ID: 8, Username: "carol", Email: "carol@example.com", Password: string(hash), Role: "user"},
+		clock:    &fakeClock{now: time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)},
+		mailer:   &fakeMailer{},
+		sessions: newFakeSessionRepository(),
+	}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) {
+			if identifier == f.user.Username || identifier == f.user.Email {
+				return *f.user, nil
+			}
+			for _, u := range f.others {
+				if identifier == u.Username || identifier == u.Email {
+					return u, nil
+				}
+			}
+			return model.User{}, errors.New("user not found")
+		},
+		findByIDFn: func(id uint) (model.User, error) {
+			if id == f.user.ID {
+				return *f.user, nil
+			}
+			return model.User{}, errors.New("user not found")
+		},
+		updatePasswordFn: func(id uint, hashedPassword string) error {
+			f.user.Password = hashedPassword
+			f.user.PasswordResetRequired = false
+			return nil
+		},
+		requirePasswordResetFn: func(id uint) error {
+			f.user.PasswordResetRequired = true
+			return nil
+		},
+		markEmailVerifiedFn: func(id uint) error {
+			f.user.EmailVerified = true
+			return nil
+		},
+		updateEmailFn: func(id uint, email string, verified bool) error {
+			f.user.Email, f.user.EmailVerified = email, verified
+			return nil
+		},
+		updateUsernameFn: func(id uint, username string) error {
+			f.user.Username = username
+			return nil
+		},
+	}
+	f.svc = NewUserService(repo, loginTestKeys,
+		WithSessionRepository(f.sessions),
+		WithAccountEmails(&fakeUserTokenRepository{}, f.mailer, "https://app.example.com/"),
+		WithClock(f.clock),
+	)
+	return f
+}
+
+// lastToken extracts the token from the link in the most recent email.
+func (f *emailFlowFixture) lastToken(t *testing.T) string {
+	t.Helper()
+	if len(f.mailer.sent) == 0 {
+		t.Fatal("no email sent")
+	}
+	link := emailLinkPattern.FindString(f.mailer.sent[len(f.mailer.sent)-1].Body)
+	u, err := url.Parse(link)
+	if err != nil || u.Query().Get("token") == "" {
+		t.Fatalf("email has no token link: %q", link)
+	}
+	return u.Query().Get("token")
+}
+
+func TestVerifyEmailIsSingleUse(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	ctx := context.Background()
+
+	if err := f.svc.SendVerificationEmail(ctx, f.user.ID); err != nil {
+		t.Fatalf("SendVerificationEmail() error = %v", err)
+	}
+	if f.mailer.sent[0].To != f.user.Email {
+		t.Fatalf("email sent to %q, want %q", f.mailer.sent[0].To, f.user.Email)
+	}
+	token := f.lastToken(t)
+
+	if err := f.svc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	if !f.user.EmailVerified {
+		t.Fatal("email not marked verified")
+	}
+	if err := f.svc.VerifyEmail(ctx, token); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("second VerifyEmail() error = %v, want %v", err, ErrInvalidEmailToken)
+	}
+	if err := f.svc.SendVerificationEmail(ctx, f.user.ID); !errors.Is(err, ErrEmailAlreadyVerified) {
+		t.Fatalf("resend error = %v, want %v", err, ErrEmailAlreadyVerified)
+	}
+}
+
+func TestVerifyEmailRejectsExpiredToken(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	ctx := context.Background()
+
+	if err := f.svc.SendVerificationEmail(ctx, f.user.ID); err != nil {
+		t.Fatalf("SendVerificationEmail() error = %v", err)
+	}
+	f.clock.Advance(emailVerificationTTL + time.Minute)
+
+	if err := f.svc.VerifyEmail(ctx, f.lastToken(t)); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("VerifyEmail() error = %v, want %v", err, ErrInvalidEmailToken)
+	}
+	if f.user.EmailVerified {
+		t.Fatal("expired token verified the email")
+	}
+}
+
+func TestRequestPasswordResetDoesNotRevealAccounts(t *testing.T) {
+	f := newEmailFlowFixture(t)
+
+	if err := f.svc.RequestPasswordReset(context.Background(), "nobody@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v, want nil for unknown email", err)
+	}
+	if len(f.mailer.sent) != 0 {
+		t.Fatalf("sent %d emails for unknown address, want 0", len(f.mailer.sent))
+	}
+}
+
+func TestResetPasswordUpdatesPasswordAndRevokesSessions(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	ctx := context.Background()
+
+	if _, _, err := f.svc.Login(ctx, LoginInput{Identifier: "carol", Password: correctPassword}); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	if err := f.svc.RequestPasswordReset(ctx, f.user.Email); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	first := f.lastToken(t)
+	if err := f.svc.RequestPasswordReset(ctx, f.user.Email); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	second := f.lastToken(t)
+
+	if err := f.svc.ResetPassword(ctx, first, "NewPassw0rd!"); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("ResetPassword(old link) error = %v, want %v", err, ErrInvalidEmailToken)
+	}
+	if err := f.svc.ResetPassword(ctx, second, "NewPassw0rd!"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(f.user.Password), []byte("NewPassw0rd!")) != nil {
+		t.Fatal("password was not updated")
+	}
+	sessions, _ := f.sessions.ListActive(ctx, f.user.ID, f.clock.Now())
+	if len(sessions) != 0 {
+		t.Fatalf("%d sessions still active after reset, want 0", len(sessions))
+	}
+	if err := f.svc.ResetPassword(ctx, second, "An0therPass!"); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("reused link error = %v, want %v", err, ErrInvalidEmailToken)
+	}
+}
+
+func TestLoginRequiresVerifiedEmailWhenEnabled(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	WithEmailVerificationRequired(true)(f.svc)
+
+	_, _, err := f.svc.Login(context.Background(), LoginInput{Identifier: "carol", Password: correctPassword})
+	if !errors.Is(err, ErrEmailNotVerified) {
+		t.Fatalf("Login() error = %v, want %v", err, ErrEmailNotVerified)
+	}
+
+	f.user.EmailVerified = true
+	if _, _, err := f.svc.Login(context.Background(), LoginInput{Identifier: "carol", Password: correctPassword}); err != nil {
+		t.Fatalf("Login() after verification error = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package application
+
+import (
+	"backend/internal/domain/algorithm"
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/domain/units"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	forecastMaxDays        = 365
+	forecastMinDays        = 20
+	DefaultForecastHorizon = 30
+	MaxForecastHorizon     = 180
+	// forecastConfidenceZ is the normal quantile for a 95% prediction interval.
+	forecastConfidenceZ  = 1.96
+	forecastConfidence   = 0.95
+	forecastSeasonPeriod = 7
+	forecastAROrder      = 5
+)
+
+var (
+	ErrUnknownCommodity     = errors.New("unknown commodity type")
+	ErrUnknownForecastModel = errors.New("unknown forecast model")
+	ErrInsufficientHistory  = errors.New("not enough price history to forecast")
+)
+
+type forecastModel struct {
+	name       string
+	forecaster algorithm.Forecaster
+}
+
+// forecastModels is ordered so that reports list models consistently.
+var forecastModels = []forecastModel{
+	{name: "naive", forecaster: algorithm.NaiveForecast},
+	{name: "holt_winters", forecaster: algorithm.HoltWinters(forecastSeasonPeriod)},
+	{name: "ar", forecaster: algorithm.AutoRegressive(forecastAROrder)},
+}
+
+type ForecastService struct {
+	commodityRepo repository.CommodityRepository
+	fx            *FXService
+}
+
+func NewForecastService(commodityRepo repository.CommodityRepository, fx *FXService) *ForecastService {
+	return &ForecastService{commodityRepo: commodityRepo, fx: fx}
+}
+
+// Forecast predicts the next `horizon` daily prices of a commodity with every model, or only
+// with modelName when it is set. Each forecast carries a backtest accuracy on the most recent
+// days of stored history. Models are fitted on USD/kg prices; other currencies and units are
+// applied afterwards, currencies at the latest exchange rate.
+func (s *ForecastService) Forecast(ctx context.Context, name, modelName string, horizon int, opts PriceOptions) (*model.ForecastReport, error) {
+	commodity := strings.ToLower(name)
+	if !isSupportedCommodity(commodity) {
+		return nil, ErrUnknownCommodity
+	}
+	if err := opts.validate(commodity); err != nil {
+		return nil, err
+	}
+	currency, _ := NormalizeCurrency(opts.Currency)
+	unit, _ := units.Parse(opts.Unit)
+
+	models := forecastModels
+	if modelName != "" {
+		models = nil
+		for _, m := range forecastModels {
+			if m.name == strings.ToLower(modelName) {
+				models = []forecastModel{m}
+			}
+		}
+		if models == nil {
+			return nil, ErrUnknownForecastModel
+		}
+	}
+
+	if horizon <= 0 {
+		horizon = DefaultForecastHorizon
+	}
+	if horizon > MaxForecastHorizon {
+		horizon = MaxForecastHorizon
+	}
+
+	history, err := s.commodityRepo.GetDailyCloses(ctx, commodity, forecastMaxDays)
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s history: %w", commodity, err)
+	}
+
+	dates, closes := dailyCloses(history)
+	if len(closes) < forecastMinDays {
+		return nil, ErrInsufficientHistory
+	}
+
+	lastDate := dates[len(dates)-1]
+	report := &model.ForecastReport{
+		Commodity:   commodity,
+		Unit:        history[0].Unit,
+		LastDate:    lastDate,
+		LastPrice:   closes[len(closes)-1],
+		Horizon:     horizon,
+		Confidence:  forecastConfidence,
+		GeneratedAt: time.Now(),
+	}
+
+	holdout := min(horizon, len(closes)/5)
+	for _, m := range models {
+		forecast := model.Forecast{Model: m.name, Points: []model.ForecastPoint{}}
+
+		points, err := m.forecaster(closes, horizon, forecastConfidenceZ)
+		if err != nil {
+			forecast.Error = err.Error()
+			report.Forecasts = append(report.Forecasts, forecast)
+			continue
+		}
+		for i, p := range points {
+			forecast.Points = append(forecast.Points, model.ForecastPoint{
+				Date:  lastDate.AddDate(0, 0, i+1),
+				Value: p.Value,
+				Lower: p.Lower,
+				Upper: p.Upper,
+			})
+		}
+
+		if acc, err := algorithm.Backtest(closes, holdout, m.forecaster); err == nil {
+			forecast.Accuracy = &model.ForecastAccuracy{MAE: acc.MAE, MAPE: acc.MAPE, Holdout: holdout}
+		}
+
+		report.Forecasts = append(report.Forecasts, forecast)
+	}
+
+	factor, err := units.FromPerKg(1, commodity, unit)
+	if err != nil {
+		return nil, err
+	}
+	if currency != BaseCurrency {
+		rate, err := s.fx.LatestRate(ctx, currency)
+		if err != nil {
+			return nil, err
+		}
+		factor *= rate
+	}
+	scaleForecastReport(report, factor)
+	report.Unit = currency + "/" + string(unit)
+
+	return report, nil
+}
+
+// scaleForecastReport multiplies every price in the report by factor. MAPE is a ratio and
+// is left unchanged.
+func scaleForecastReport(report *model.ForecastReport, factor float64) {
+	report.LastPrice *= factor
+	for i := range report.Forecasts {
+		f := &report.Forecasts[i]
+		for j := range f.Points {
+			f.Points[j].Value *= factor
+			f.Points[j].Lower *= factor
+			f.Points[j].Upper *= factor
+		}
+		if f.Accuracy != nil {
+			f.Accuracy.MAE *= factor
+		}
+	}
+}
+
+// dailyCloses collapses a DESC-ordered price history into one closing price per calendar
+// day, returned in chronological order.
+func dailyCloses(history []model.Commodity) ([]time.Time, []float64) {
+	var dates []time.Time
+	var closes []float64
+
+	for i := len(history) - 1; i >= 0; i-- {
+		c := history[i]
+		day := time.Date(c.Date.Year(), c.Date.Month(), c.Date.Day(), 0, 0, 0, 0, time.UTC)
+
+		if n := len(dates); n > 0 && dates[n-1].Equal(day) {
+			closes[n-1] = c.PriceKg
+			continue
+		}
+		dates = append(dates, day)
+		closes = append(closes, c.PriceKg)
+	}
+
+	return dates, closes
+}
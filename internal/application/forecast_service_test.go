@@ -0,0 +1,125 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	stdErrors "errors"
+	"testing"
+	"time"
+)
+
+// dailyHistory builds a DESC-ordered history of `days` daily prices ending today.
+func dailyHistory(name string, days int, price func(i int) float64) []model.Commodity {
+	start := time.Now().UTC().AddDate(0, 0, -days)
+	history := make([]model.Commodity, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		history = append(history, model.Commodity{
+			Name:    name,
+			Date:    start.AddDate(0, 0, i),
+			PriceKg: price(i),
+			Unit:    "USD/kg",
+		})
+	}
+	return history
+}
+
+func TestForecastRejectsUnknownCommodity(t *testing.T) {
+	svc := NewForecastService(&fakeCommodityRepository{}, nil)
+
+	_, err := svc.Forecast(context.Background(), "platinum", "", 10, PriceOptions{})
+	if !stdErrors.Is(err, ErrUnknownCommodity) {
+		t.Fatalf("error = %v, want ErrUnknownCommodity", err)
+	}
+}
+
+func TestForecastRejectsUnknownModel(t *testing.T) {
+	svc := NewForecastService(&fakeCommodityRepository{}, nil)
+
+	_, err := svc.Forecast(context.Background(), "gold", "arima", 10, PriceOptions{})
+	if !stdErrors.Is(err, ErrUnknownForecastModel) {
+		t.Fatalf("error = %v, want ErrUnknownForecastModel", err)
+	}
+}
+
+func TestForecastRequiresEnoughHistory(t *testing.T) {
+	repo := &fakeCommodityRepository{history: map[string][]model.Commodity{
+		"gold": dailyHistory("gold", 5, func(i int) float64 { return 100 }),
+	}}
+	svc := NewForecastService(repo, nil)
+
+	_, err := svc.Forecast(context.Background(), "gold", "", 10, PriceOptions{})
+	if !stdErrors.Is(err, ErrInsufficientHistory) {
+		t.Fatalf("error = %v, want ErrInsufficientHistory", err)
+	}
+}
+
+func TestForecastReturnsEveryModelWithAccuracy(t *testing.T) {
+	repo := &fakeCommodityRepository{history: map[string][]model.Commodity{
+		"gold": dailyHistory("gold", 120, func(i int) float64 { return 1000 + float64(i) + float64(i%7) }),
+	}}
+	svc := NewForecastService(repo, nil)
+
+	report, err := svc.Forecast(context.Background(), "Gold", "", 14, PriceOptions{})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+	if len(report.Forecasts) != len(forecastModels) {
+		t.Fatalf("forecasts = %d, want %d", len(report.Forecasts), len(forecastModels))
+	}
+	for _, f := range report.Forecasts {
+		if f.Error != "" {
+			t.Fatalf("model %s failed: %s", f.Model, f.Error)
+		}
+		if len(f.Points) != 14 {
+			t.Fatalf("model %s points = %d, want 14", f.Model, len(f.Points))
+		}
+		if !f.Points[0].Date.Equal(report.LastDate.AddDate(0, 0, 1)) {
+			t.Fatalf("model %s first date = %v, want day after %v", f.Model, f.Points[0].Date, report.LastDate)
+		}
+		if f.Accuracy == nil || f.Accuracy.Holdout != 14 {
+			t.Fatalf("model %s accuracy = %+v, want holdout 14", f.Model, f.Accuracy)
+		}
+	}
+}
+
+func TestForecastUsesDaysNotRowsOfIntradayHistory(t *testing.T) {
+	// 30 days of 5-minute ticks, far more rows than a row-based limit would have read.
+	const ticksPerDay = 288
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -30)
+	var history []model.Commodity
+	for i := 30*ticksPerDay - 1; i >= 0; i-- {
+		history = append(history, model.Commodity{
+			Name:    "gold",
+			Date:    start.Add(time.Duration(i) * 5 * time.Minute),
+			PriceKg: 1000 + float64(i/ticksPerDay),
+			Unit:    "USD/kg",
+		})
+	}
+	svc := NewForecastService(&fakeCommodityRepository{history: map[string][]model.Commodity{"gold": history}}, nil)
+
+	report, err := svc.Forecast(context.Background(), "gold", "naive", 5, PriceOptions{})
+	if err != nil {
+		t.Fatalf("Forecast() error = %v", err)
+	}
+	if report.LastPrice != 1029 || !report.LastDate.Equal(start.AddDate(0, 0, 29)) {
+		t.Fatalf("last close = %v on %v, want 1029 on %v", report.LastPrice, report.LastDate, start.AddDate(0, 0, 29))
+	}
+}
+
+func TestDailyClosesKeepsLastTickPerDay(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	history := []model.Commodity{
+		{Date: day.Add(26 * time.Hour), PriceKg: 4},
+		{Date: day.Add(15 * time.Hour), PriceKg: 3},
+		{Date: day.Add(9 * time.Hour), PriceKg: 2},
+		{Date: day, PriceKg: 1},
+	}
+
+	dates, closes := dailyCloses(history)
+	if len(closes) != 2 || closes[0] != 3 || closes[1] != 4 {
+		t.Fatalf("closes = %v, want [3 4]", closes)
+	}
+	if !dates[0].Equal(day) {
+		t.Fatalf("dates[0] = %v, want %v", dates[0], day)
+	}
+}
@@ -0,0 +1,159 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BaseCurrency is the currency every price is stored in.
+const BaseCurrency = "USD"
+
+// SupportedCurrencies lists the currencies prices can be presented in.
+var SupportedCurrencies = []string{"USD", "EUR", "GBP", "CHF", "JPY"}
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency, expected one of USD, EUR, GBP, CHF, JPY")
+	ErrFXRateUnavailable   = errors.New("no exchange rate available for currency")
+)
+
+// ExchangeRateProvider is the outbound port for fetching live exchange rates.
+type ExchangeRateProvider interface {
+	FetchExchangeRate(ctx context.Context, from, to string) (*model.FXRate, error)
+}
+
+type FXService struct {
+	rateProvider ExchangeRateProvider
+	fxRepo       repository.FXRateRepository
+	clock        Clock
+}
+
+func NewFXService(rateProvider ExchangeRateProvider, fxRepo repository.FXRateRepository) *FXService {
+	return &FXService{rateProvider: rateProvider, fxRepo: fxRepo, clock: systemClock{}}
+}
+
+// NormalizeCurrency validates a currency code and returns it upper-cased. An empty code
+// means the base currency.
+func NormalizeCurrency(currency string) (string, error) {
+	c := strings.ToUpper(strings.TrimSpace(currency))
+	if c == "" {
+		return BaseCurrency, nil
+	}
+	for _, supported := range SupportedCurrencies {
+		if c == supported {
+			return c, nil
+		}
+	}
+	return "", ErrUnsupportedCurrency
+}
+
+// UpdateRates stores the latest rate for every supported currency. Currencies already
+// fetched during the current UTC day are skipped to preserve the provider quota; the
+// quote date is not used for this, as it stays on the last trading day over weekends.
+func (s *FXService) UpdateRates(ctx context.Context) error {
+	now := s.clock.Now()
+	today := now.UTC().Truncate(24 * time.Hour)
+
+	var failed []string
+	for _, quote := range SupportedCurrencies {
+		if quote == BaseCurrency {
+			continue
+		}
+
+		latest, err := s.fxRepo.GetLatest(ctx, BaseCurrency, quote)
+		if err == nil && !latest.FetchedAt.Before(today) {
+			continue
+		}
+
+		rate, err := s.rateProvider.FetchExchangeRate(ctx, BaseCurrency, quote)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("fetch %s: %v", quote, err))
+			continue
+		}
+		if rate.Rate <= 0 {
+			failed = append(failed, fmt.Sprintf("fetch %s: non-positive rate %v", quote, rate.Rate))
+			continue
+		}
+		rate.FetchedAt = now
+		if err := s.fxRepo.Save(ctx, *rate); err != nil {
+			failed = append(failed, fmt.Sprintf("save %s: %v", quote, err))
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("exchange rate updates failed: %s", strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Convert expresses USD prices in the requested currency, using for each price the most
+// recent daily rate on or before its date (or the earliest known rate for older prices).
+// The returned slice is a converted copy; the input is left untouched.
+func (s *FXService) Convert(ctx context.Context, prices []model.Commodity, currency string) ([]model.Commodity, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == BaseCurrency || len(prices) == 0 {
+		return prices, nil
+	}
+
+	rates, err := s.fxRepo.ListRates(ctx, BaseCurrency, currency)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("load %s rates: %w", currency, err)
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%w %s", ErrFXRateUnavailable, currency)
+	}
+
+	converted := make([]model.Commodity, len(prices))
+	for i, p := range prices {
+		p.PriceKg *= rateOn(rates, p.Date)
+		p.Unit = withCurrency(p.Unit, currency)
+		converted[i] = p
+	}
+	return converted, nil
+}
+
+// LatestRate returns the most recent rate from the base currency to currency.
+func (s *FXService) LatestRate(ctx context.Context, currency string) (float64, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return 0, err
+	}
+	if currency == BaseCurrency {
+		return 1, nil
+	}
+
+	rate, err := s.fxRepo.GetLatest(ctx, BaseCurrency, currency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%w %s", ErrFXRateUnavailable, currency)
+		}
+		return 0, err
+	}
+	return rate.Rate, nil
+}
+
+// rateOn picks the last rate dated on or before date from a chronological list.
+func rateOn(rates []model.FXRate, date time.Time) float64 {
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].Date.After(date) })
+	if i == 0 {
+		return rates[0].Rate
+	}
+	return rates[i-1].Rate
+}
+
+// withCurrency swaps the currency part of a unit label such as "USD/kg".
+func withCurrency(unit, currency string) string {
+	if _, per, ok := strings.Cut(unit, "/"); ok {
+		return currency + "/" + per
+	}
+	return currency
+}
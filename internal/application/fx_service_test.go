@@ -0,0 +1,200 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type fakeFXRateRepository struct {
+	rates []model.FXRate // chronological
+}
+
+func (f *fakeFXRateRepository) Save(ctx context.Context, rate model.FXRate) error {
+	f.rates = append(f.rates, rate)
+	return nil
+}
+
+func (f *fakeFXRateRepository) GetLatest(ctx context.Context, base, quote string) (model.FXRate, error) {
+	for i := len(f.rates) - 1; i >= 0; i-- {
+		if f.rates[i].Base == base && f.rates[i].Quote == quote {
+			return f.rates[i], nil
+		}
+	}
+	return model.FXRate{}, sql.ErrNoRows
+}
+
+func (f *fakeFXRateRepository) ListRates(ctx context.Context, base, quote string) ([]model.FXRate, error) {
+	var out []model.FXRate
+	for _, r := range f.rates {
+		if r.Base == base && r.Quote == quote {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+type fakeRateProvider struct {
+	calls int
+	rates map[string]float64
+	date  time.Time // quote date; today when zero
+}
+
+func (f *fakeRateProvider) FetchExchangeRate(ctx context.Context, from, to string) (*model.FXRate, error) {
+	f.calls++
+	date := f.date
+	if date.IsZero() {
+		date = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	return &model.FXRate{Base: from, Quote: to, Date: date, Rate: f.rates[to]}, nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestFXConvertUsesRateOfEachDay(t *testing.T) {
+	repo := &fakeFXRateRepository{rates: []model.FXRate{
+		{Base: "USD", Quote: "EUR", Date: day(2), Rate: 0.9},
+		{Base: "USD", Quote: "EUR", Date: day(4), Rate: 0.8},
+	}}
+	svc := NewFXService(nil, repo)
+
+	prices := []model.Commodity{
+		{Date: day(5).Add(10 * time.Hour), PriceKg: 100, Unit: "USD/kg"},
+		{Date: day(3), PriceKg: 100, Unit: "USD/kg"},
+		{Date: day(1), PriceKg: 100, Unit: "USD/kg"},
+	}
+
+	converted, err := svc.Convert(context.Background(), prices, "eur")
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	want := []float64{80, 90, 90} // day 1 predates every rate and falls back to the earliest one
+	for i, c := range converted {
+		if math.Abs(c.PriceKg-want[i]) > 1e-9 {
+			t.Fatalf("converted[%d] = %v, want %v", i, c.PriceKg, want[i])
+		}
+		if c.Unit != "EUR/kg" {
+			t.Fatalf("unit = %q, want EUR/kg", c.Unit)
+		}
+	}
+	if prices[0].PriceKg != 100 {
+		t.Fatal("Convert must not modify its input")
+	}
+}
+
+func TestFXConvertRejectsUnsupportedCurrency(t *testing.T) {
+	svc := NewFXService(nil, &fakeFXRateRepository{})
+
+	if _, err := svc.Convert(context.Background(), []model.Commodity{{PriceKg: 1}}, "BTC"); !stdErrors.Is(err, ErrUnsupportedCurrency) {
+		t.Fatalf("error = %v, want ErrUnsupportedCurrency", err)
+	}
+}
+
+func TestFXConvertWithoutRatesIsUnavailable(t *testing.T) {
+	svc := NewFXService(nil, &fakeFXRateRepository{})
+
+	if _, err := svc.Convert(context.Background(), []model.Commodity{{PriceKg: 1}}, "JPY"); !stdErrors.Is(err, ErrFXRateUnavailable) {
+		t.Fatalf("error = %v, want ErrFXRateUnavailable", err)
+	}
+}
+
+func TestFXUpdateRatesSkipsCurrenciesFetchedToday(t *testing.T) {
+	repo := &fakeFXRateRepository{}
+	provider := &fakeRateProvider{rates: map[string]float64{"EUR": 0.9, "GBP": 0.8, "CHF": 0.85, "JPY": 150}}
+	svc := NewFXService(provider, repo)
+
+	if err := svc.UpdateRates(context.Background()); err != nil {
+		t.Fatalf("UpdateRates() error = %v", err)
+	}
+	if provider.calls != 4 || len(repo.rates) != 4 {
+		t.Fatalf("calls = %d, saved = %d, want 4 and 4", provider.calls, len(repo.rates))
+	}
+
+	if err := svc.UpdateRates(context.Background()); err != nil {
+		t.Fatalf("second UpdateRates() error = %v", err)
+	}
+	if provider.calls != 4 {
+		t.Fatalf("calls after second update = %d, want 4", provider.calls)
+	}
+}
+
+func TestFXUpdateRatesFetchesOnceADayOverWeekends(t *testing.T) {
+	friday := time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+	repo := &fakeFXRateRepository{}
+	provider := &fakeRateProvider{rates: map[string]float64{"EUR": 0.9, "GBP": 0.8, "CHF": 0.85, "JPY": 150}, date: friday}
+	svc := NewFXService(provider, repo)
+	clock := &fakeClock{now: friday.Add(24*time.Hour + 9*time.Hour)} // Saturday morning
+	svc.clock = clock
+
+	if err := svc.UpdateRates(context.Background()); err != nil {
+		t.Fatalf("UpdateRates() error = %v", err)
+	}
+	clock.Advance(5 * time.Minute)
+	if err := svc.UpdateRates(context.Background()); err != nil {
+		t.Fatalf("second UpdateRates() error = %v", err)
+	}
+	if provider.calls != 4 {
+		t.Fatalf("calls = %d, want 4: a Friday quote fetched on Saturday is not fetched again the same day", provider.calls)
+	}
+
+	clock.Advance(24 * time.Hour) // Sunday
+	if err := svc.UpdateRates(context.Background()); err != nil {
+		t.Fatalf("Sunday UpdateRates() error = %v", err)
+	}
+	if provider.calls != 8 {
+		t.Fatalf("calls = %d, want 8 after a new day", provider.calls)
+	}
+}
+
+func TestGetHistoryPresentsCurrencyAndUnit(t *testing.T) {
+	repo := &fakeCommodityRepository{history: map[string][]model.Commodity{
+		"brent": {{Name: "brent", Date: day(3), PriceKg: 0.6, Unit: "USD/kg"}},
+	}}
+	fx := NewFXService(nil, &fakeFXRateRepository{rates: []model.FXRate{{Base: "USD", Quote: "EUR", Date: day(1), Rate: 0.5}}})
+	svc := NewCommodityService(&fakePriceProvider{}, repo, &fakeQuarantineRepository{}, NewPriceValidator(25, 8), fx)
+
+	history, err := svc.GetHistory(context.Background(), "brent", 10, "", PriceOptions{Currency: "EUR", Unit: "bbl"})
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	got := history[0]
+	if math.Abs(got.PriceKg-0.3) > 1e-9 || math.Abs(got.Price-40.8) > 1e-9 || got.Unit != "EUR/bbl" {
+		t.Fatalf("presented = %+v, want price_kg 0.3, price 40.8 EUR/bbl", got)
+	}
+
+	if _, err := svc.GetHistory(context.Background(), "gold", 10, "", PriceOptions{Unit: "bbl"}); err == nil {
+		t.Fatal("expected an error for barrels of gold")
+	}
+}
+
+func TestGetHistoryLimitsToChartInterval(t *testing.T) {
+	repo := &fakeCommodityRepository{history: map[string][]model.Commodity{
+		"gold": {
+			{Name: "gold", Date: day(20), PriceKg: 3, Unit: "USD/kg"},
+			{Name: "gold", Date: day(14), PriceKg: 2, Unit: "USD/kg"},
+			{Name: "gold", Date: day(1), PriceKg: 1, Unit: "USD/kg"},
+		},
+	}}
+	svc := NewCommodityService(&fakePriceProvider{}, repo, &fakeQuarantineRepository{}, NewPriceValidator(25, 8), NewFXService(nil, &fakeFXRateRepository{}))
+
+	history, err := svc.GetHistory(context.Background(), "gold", 10, "1w", PriceOptions{})
+	if err != nil {
+		t.Fatalf("GetHistory() error = %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d prices, want the 2 within a week of the latest", len(history))
+	}
+	if all, _ := svc.GetHistory(context.Background(), "gold", 10, "", PriceOptions{}); len(all) != 3 {
+		t.Fatalf("got %d prices without an interval, want 3", len(all))
+	}
+	if _, err := svc.GetHistory(context.Background(), "gold", 10, "2d", PriceOptions{}); !stdErrors.Is(err, ErrUnknownChartInterval) {
+		t.Fatalf("GetHistory(2d) error = %v, want %v", err, ErrUnknownChartInterval)
+	}
+}
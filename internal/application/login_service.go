@@ -5,44 +5,86 @@ import (
 	"context"
 	stdErrors "errors"
 
-	"backend/internal/auth"
-
 	"log"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-func (s *UserService) Login(ctx context.Context, req LoginInput) (model.User, string, error) {
+func (s *UserService) Login(ctx context.Context, req LoginInput) (model.User, AuthResult, error) {
 	if req.Identifier == "" {
-		return model.User{}, "", stdErrors.New("user or email is required")
+		return model.User{}, AuthResult{}, stdErrors.New("user or email is required")
 	}
 	if req.Password == "" {
-		return model.User{}, "", stdErrors.New("password is required")
+		return model.User{}, AuthResult{}, stdErrors.New("password is required")
+	}
+
+	if err := s.checkIPThrottle(ctx, req.IP); err != nil {
+		return model.User{}, AuthResult{}, err
 	}
 
+	attempt := model.LoginAttempt{Identifier: req.Identifier, IP: req.IP, UserAgent: req.UserAgent}
+
 	user, err := s.userRepo.FindByUsernameOrEmail(ctx, req.Identifier)
 	if err != nil {
-		return model.User{}, "", stdErrors.New("invalid credentials")
+		attempt.AccountKey = loginAccountKey(nil, req.Identifier)
+		if err := s.checkAccountThrottle(ctx, attempt.AccountKey); err != nil {
+			attempt.Outcome = throttleOutcome(err)
+			s.recordAttempt(ctx, attempt)
+			return model.User{}, AuthResult{}, err
+		}
+		attempt.Outcome = model.LoginOutcomeInvalidCredentials
+		s.recordAttempt(ctx, attempt)
+		return model.User{}, AuthResult{}, ErrInvalidCredentials
+	}
+
+	attempt.AccountKey = loginAccountKey(&user, req.Identifier)
+	attempt.UserID = &user.ID
+	if err := s.checkAccountThrottle(ctx, attempt.AccountKey); err != nil {
+		attempt.Outcome = throttleOutcome(err)
+		s.recordAttempt(ctx, attempt)
+		return model.User{}, AuthResult{}, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return model.User{}, "", stdErrors.New("invalid credentials")
+		attempt.Outcome = model.LoginOutcomeInvalidCredentials
+		s.recordAttempt(ctx, attempt)
+		return model.User{}, AuthResult{}, ErrInvalidCredentials
 	}
 
-	token, err := auth.GenerateJWTToken(s.jwtSecret, user.ID, user.Username, user.Role)
-	if err != nil {
-		log.Printf("Error generating JWT token: %v", err)
-		return model.User{}, "", err
+	if err := checkAccountStatus(user); err != nil {
+		attempt.Outcome = model.LoginOutcomeAccountBlocked
+		s.recordAttempt(ctx, attempt)
+		return model.User{}, AuthResult{}, err
 	}
-	log.Printf("Token generated for user %s", user.Username)
 
-	return user, token, nil
-}
+	if s.requireVerifiedEmail && !user.EmailVerified {
+		attempt.Outcome = model.LoginOutcomeEmailNotVerified
+		s.recordAttempt(ctx, attempt)
+		return model.User{}, AuthResult{}, ErrEmailNotVerified
+	}
+
+	challenge, err := s.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return model.User{}, AuthResult{}, err
+	}
+	if challenge != "" {
+		// The password alone does not reset the failure count, or it could be used to
+		// keep guessing the second factor.
+		attempt.Outcome = model.LoginOutcomeTwoFactorRequired
+		s.recordAttempt(ctx, attempt)
+		log.Printf("Two-factor challenge issued for user %s", user.Username)
+		return user, AuthResult{TwoFactorChallenge: challenge}, nil
+	}
 
-func (s *UserService) RefreshToken(tokenString string) (string, error) {
-	claims, err := auth.VerifyJWTToken(s.jwtSecret, tokenString)
+	result, err := s.startSession(ctx, user, req.IP, req.UserAgent)
 	if err != nil {
-		return "", err
+		log.Printf("Error generating tokens: %v", err)
+		return model.User{}, AuthResult{}, err
 	}
-	return auth.RefreshJWToken(s.jwtSecret, claims)
+	log.Printf("Token generated for user %s", user.Username)
+
+	attempt.Outcome = model.LoginOutcomeSuccess
+	s.recordAttempt(ctx, attempt)
+
+	return user, result, nil
 }
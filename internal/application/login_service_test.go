@@ -1,6 +1,7 @@
 package application
 
 import (
+	"backend/internal/auth"
 	"backend/internal/domain/model"
 	"context"
 	stdErrors "errors"
@@ -11,10 +12,11 @@ import (
 )
 
 const correctPassword = "correct-password"
-const loginTestSecret = "app-test-secret"
+
+var loginTestKeys = auth.NewHMACKeySet([]byte("app-test-secret"))
 
 func TestLoginRejectsEmptyIdentifier(t *testing.T) {
-	svc := NewUserService(&fakeUserRepository{}, loginTestSecret)
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys)
 
 	_, _, err := svc.Login(context.Background(), LoginInput{Password: "abc"})
 	if err == nil || err.Error() != "user or email is required" {
@@ -23,7 +25,7 @@ func TestLoginRejectsEmptyIdentifier(t *testing.T) {
 }
 
 func TestLoginRejectsEmptyPassword(t *testing.T) {
-	svc := NewUserService(&fakeUserRepository{}, loginTestSecret)
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys)
 
 	_, _, err := svc.Login(context.Background(), LoginInput{Identifier: "alice"})
 	if err == nil || err.Error() != "password is required" {
@@ -37,7 +39,7 @@ func TestLoginReturnsInvalidCredentialsWhenUserMissing(t *testing.T) {
 			return model.User{}, stdErrors.New("not found")
 		},
 	}
-	svc := NewUserService(repo, loginTestSecret)
+	svc := NewUserService(repo, loginTestKeys)
 
 	_, _, err := svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: "password"})
 	if err == nil || err.Error() != "invalid credentials" {
@@ -56,7 +58,7 @@ func TestLoginReturnsInvalidCredentialsWhenPasswordMismatch(t *testing.T) {
 			return model.User{ID: 1, Username: "alice", Password: string(hash), Role: "user"}, nil
 		},
 	}
-	svc := NewUserService(repo, loginTestSecret)
+	svc := NewUserService(repo, loginTestKeys)
 
 	_, _, err = svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: "wrong-password"})
 	if err == nil || err.Error() != "invalid credentials" {
@@ -75,24 +77,27 @@ func TestLoginSuccessReturnsTokenAndUser(t *testing.T) {
 			return model.User{ID: 42, Username: "alice", Password: string(hash), Role: "admin"}, nil
 		},
 	}
-	svc := NewUserService(repo, loginTestSecret)
+	svc := NewUserService(repo, loginTestKeys)
 
-	user, token, err := svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: correctPassword})
+	user, tokens, err := svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: correctPassword})
 	if err != nil {
 		t.Fatalf("Login() error = %v", err)
 	}
 	if user.ID != 42 || user.Username != "alice" {
 		t.Fatalf("unexpected user returned: %+v", user)
 	}
-	if strings.TrimSpace(token) == "" {
+	if strings.TrimSpace(tokens.AccessToken) == "" {
 		t.Fatal("expected non-empty token")
 	}
+	if tokens.RefreshToken != "" {
+		t.Fatal("expected no refresh token without a session repository")
+	}
 }
 
 func TestRefreshTokenRejectsInvalidToken(t *testing.T) {
-	svc := NewUserService(&fakeUserRepository{}, loginTestSecret)
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys)
 
-	_, err := svc.RefreshToken("invalid-token")
+	_, err := svc.RefreshToken(context.Background(), "invalid-token", "")
 	if err == nil {
 		t.Fatal("expected error for invalid token")
 	}
@@ -0,0 +1,173 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrAccountLocked      = errors.New("account temporarily locked after too many failed logins")
+	ErrTooManyAttempts    = errors.New("too many login attempts, slow down")
+)
+
+// LoginThrottledError is returned when a login is refused before the password is checked.
+// It wraps ErrAccountLocked or ErrTooManyAttempts.
+type LoginThrottledError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("%v, retry in %s", e.Err, e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error { return e.Err }
+
+// LoginThrottlePolicy decides how failed logins slow down further attempts.
+type LoginThrottlePolicy struct {
+	// FailureWindow is how far back failures are counted.
+	FailureWindow time.Duration
+	// DelayAfter is the number of account failures after which each new attempt must wait
+	// BaseDelay, doubled per extra failure and capped at MaxDelay.
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// MaxAccountFailures locks the account for LockoutDuration after the last failure.
+	MaxAccountFailures int
+	LockoutDuration    time.Duration
+	// MaxIPFailures blocks an address, across all accounts, for LockoutDuration.
+	MaxIPFailures int
+}
+
+// DefaultLoginThrottlePolicy returns the policy used when none is configured.
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		FailureWindow:      15 * time.Minute,
+		DelayAfter:         3,
+		BaseDelay:          time.Second,
+		MaxDelay:           30 * time.Second,
+		MaxAccountFailures: 10,
+		LockoutDuration:    15 * time.Minute,
+		MaxIPFailures:      50,
+	}
+}
+
+// accountDelay is the wait imposed after `failures` consecutive account failures.
+func (p LoginThrottlePolicy) accountDelay(failures int) time.Duration {
+	if p.DelayAfter <= 0 || failures < p.DelayAfter {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.DelayAfter; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// checkIPThrottle refuses logins from an address that failed too often recently.
+func (s *UserService) checkIPThrottle(ctx context.Context, ip string) error {
+	if s.attemptRepo == nil || ip == "" || s.throttle.MaxIPFailures <= 0 {
+		return nil
+	}
+
+	now := s.clock.Now()
+	failures, err := s.attemptRepo.IPFailures(ctx, ip, now.Add(-s.throttle.FailureWindow))
+	if err != nil {
+		return err
+	}
+	if failures.Count >= s.throttle.MaxIPFailures {
+		if until := failures.Last.Add(s.throttle.LockoutDuration); now.Before(until) {
+			return &LoginThrottledError{Err: ErrTooManyAttempts, RetryAfter: until.Sub(now)}
+		}
+	}
+	return nil
+}
+
+// checkAccountThrottle applies the progressive delay and the lockout of an account.
+func (s *UserService) checkAccountThrottle(ctx context.Context, accountKey string) error {
+	if s.attemptRepo == nil {
+		return nil
+	}
+
+	now := s.clock.Now()
+	failures, err := s.attemptRepo.AccountFailures(ctx, accountKey, now.Add(-s.throttle.FailureWindow))
+	if err != nil {
+		return err
+	}
+	if failures.Count == 0 {
+		return nil
+	}
+
+	if s.throttle.MaxAccountFailures > 0 && failures.Count >= s.throttle.MaxAccountFailures {
+		if until := failures.Last.Add(s.throttle.LockoutDuration); now.Before(until) {
+			return &LoginThrottledError{Err: ErrAccountLocked, RetryAfter: until.Sub(now)}
+		}
+		return nil
+	}
+
+	if until := failures.Last.Add(s.throttle.accountDelay(failures.Count)); now.Before(until) {
+		return &LoginThrottledError{Err: ErrTooManyAttempts, RetryAfter: until.Sub(now)}
+	}
+	return nil
+}
+
+// recordAttempt appends to the login audit trail. Failing to record never blocks a login.
+func (s *UserService) recordAttempt(ctx context.Context, attempt model.LoginAttempt) {
+	s.auditLogin(ctx, attempt)
+	if s.attemptRepo == nil {
+		return
+	}
+	attempt.CreatedAt = s.clock.Now()
+	if err := s.attemptRepo.Record(ctx, attempt); err != nil {
+		log.Printf("Error recording login attempt: %v", err)
+	}
+}
+
+// auditLogin copies the final outcome of a login to the security audit log. Passing the
+// password step of a two-factor login is not an outcome yet.
+func (s *UserService) auditLogin(ctx context.Context, attempt model.LoginAttempt) {
+	if attempt.Outcome == model.LoginOutcomeTwoFactorRequired {
+		return
+	}
+	event := model.AuditEvent{
+		ActorID:   attempt.UserID,
+		Action:    model.AuditLogin,
+		IP:        attempt.IP,
+		UserAgent: attempt.UserAgent,
+		Result:    model.AuditResultFailure,
+		Detail:    attempt.Outcome,
+	}
+	if attempt.UserID != nil {
+		event.TargetType = "user"
+		event.TargetID = userStatusKey(*attempt.UserID)
+	} else {
+		event.Detail += " for " + attempt.Identifier
+	}
+	if attempt.Outcome == model.LoginOutcomeSuccess {
+		event.Result = model.AuditResultSuccess
+	}
+	s.audit.Record(ctx, event)
+}
+
+// loginAccountKey identifies the account a login targets, independently of whether the
+// username or the email was typed.
+func loginAccountKey(user *model.User, identifier string) string {
+	if user != nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+	return "identifier:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// throttleOutcome maps a throttling error to the outcome recorded in the audit trail.
+func throttleOutcome(err error) string {
+	if errors.Is(err, ErrAccountLocked) {
+		return model.LoginOutcomeLocked
+	}
+	return model.LoginOutcomeThrottled
+}
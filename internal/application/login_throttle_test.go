@@ -0,0 +1,145 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newThrottleTestService(t *testing.T, policy LoginThrottlePolicy) (*UserService, *fakeClock, *fakeLoginAttemptRepository) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	user := model.User{ID: 5, Username: "alice", Email: "alice@example.com", Password: string(hash), Role: "user"}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) {
+			if identifier == user.Username || identifier == user.Email {
+				return user, nil
+			}
+			return model.User{}, errors.New("user not found")
+		},
+	}
+	clock := &fakeClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	attempts := &fakeLoginAttemptRepository{}
+	svc := NewUserService(repo, loginTestKeys, WithLoginAttempts(attempts, policy), WithClock(clock))
+	return svc, clock, attempts
+}
+
+func loginAs(svc *UserService, identifier, password, ip string) error {
+	_, _, err := svc.Login(context.Background(), LoginInput{Identifier: identifier, Password: password, IP: ip})
+	return err
+}
+
+func TestLoginProgressiveDelay(t *testing.T) {
+	policy := DefaultLoginThrottlePolicy()
+	svc, clock, _ := newThrottleTestService(t, policy)
+
+	for i := 0; i < policy.DelayAfter; i++ {
+		if err := loginAs(svc, "alice", "wrong", "198.51.100.1"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d error = %v, want %v", i, err, ErrInvalidCredentials)
+		}
+	}
+
+	// Even the right password has to wait for the delay.
+	err := loginAs(svc, "alice", correctPassword, "198.51.100.1")
+	var throttled *LoginThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("error = %v, want %v", err, ErrTooManyAttempts)
+	}
+	if throttled.RetryAfter != policy.BaseDelay {
+		t.Fatalf("RetryAfter = %v, want %v", throttled.RetryAfter, policy.BaseDelay)
+	}
+
+	clock.Advance(policy.BaseDelay)
+	if err := loginAs(svc, "alice", "wrong", "198.51.100.1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("error after delay = %v, want %v", err, ErrInvalidCredentials)
+	}
+	err = loginAs(svc, "alice", correctPassword, "198.51.100.1")
+	if !errors.As(err, &throttled) || throttled.RetryAfter != 2*policy.BaseDelay {
+		t.Fatalf("error = %v, want doubled delay", err)
+	}
+
+	clock.Advance(2 * policy.BaseDelay)
+	if err := loginAs(svc, "alice", correctPassword, "198.51.100.1"); err != nil {
+		t.Fatalf("Login() after delay error = %v", err)
+	}
+	// A success resets the account's failure count.
+	if err := loginAs(svc, "alice", "wrong", "198.51.100.1"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	if err := loginAs(svc, "alice", correctPassword, "198.51.100.1"); err != nil {
+		t.Fatalf("Login() after reset error = %v", err)
+	}
+}
+
+func TestLoginLockoutSpansUsernameAndEmail(t *testing.T) {
+	policy := DefaultLoginThrottlePolicy()
+	policy.DelayAfter = 0
+	policy.MaxAccountFailures = 4
+	svc, clock, attempts := newThrottleTestService(t, policy)
+
+	for i := 0; i < policy.MaxAccountFailures; i++ {
+		identifier := "alice"
+		if i%2 == 1 {
+			identifier = "alice@example.com"
+		}
+		if err := loginAs(svc, identifier, "wrong", "198.51.100.2"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d error = %v, want %v", i, err, ErrInvalidCredentials)
+		}
+	}
+
+	if err := loginAs(svc, "alice@example.com", correctPassword, "203.0.113.9"); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("error = %v, want %v", err, ErrAccountLocked)
+	}
+	if last := attempts.attempts[len(attempts.attempts)-1]; last.Outcome != model.LoginOutcomeLocked {
+		t.Fatalf("recorded outcome = %q, want %q", last.Outcome, model.LoginOutcomeLocked)
+	}
+
+	clock.Advance(policy.LockoutDuration)
+	if err := loginAs(svc, "alice", correctPassword, "203.0.113.9"); err != nil {
+		t.Fatalf("Login() after lockout error = %v", err)
+	}
+}
+
+func TestLoginBlocksNoisyIP(t *testing.T) {
+	policy := DefaultLoginThrottlePolicy()
+	policy.DelayAfter = 0
+	policy.MaxIPFailures = 3
+	svc, clock, _ := newThrottleTestService(t, policy)
+
+	for _, identifier := range []string{"bob", "carol", "dave"} {
+		if err := loginAs(svc, identifier, "guess", "192.0.2.66"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("error = %v, want %v", err, ErrInvalidCredentials)
+		}
+	}
+
+	if err := loginAs(svc, "alice", correctPassword, "192.0.2.66"); !errors.Is(err, ErrTooManyAttempts) {
+		t.Fatalf("error = %v, want %v", err, ErrTooManyAttempts)
+	}
+	if err := loginAs(svc, "alice", correctPassword, "192.0.2.67"); err != nil {
+		t.Fatalf("other address should not be blocked: %v", err)
+	}
+
+	clock.Advance(policy.LockoutDuration)
+	if err := loginAs(svc, "alice", correctPassword, "192.0.2.66"); err != nil {
+		t.Fatalf("Login() after block expired error = %v", err)
+	}
+}
+
+func TestAccountDelayIsCapped(t *testing.T) {
+	policy := DefaultLoginThrottlePolicy()
+
+	if d := policy.accountDelay(policy.DelayAfter - 1); d != 0 {
+		t.Fatalf("delay below threshold = %v, want 0", d)
+	}
+	if d := policy.accountDelay(policy.DelayAfter + 100); d != policy.MaxDelay {
+		t.Fatalf("delay = %v, want cap %v", d, policy.MaxDelay)
+	}
+}
@@ -0,0 +1,15 @@
+package application
+
+import "context"
+
+// Email is a plain-text message sent to a single recipient.
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer is the outbound port for sending emails.
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
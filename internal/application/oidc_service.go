@@ -0,0 +1,429 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// oidcStateTTL bounds how long the user may take at the provider's login page.
+	oidcStateTTL = 10 * time.Minute
+	// reauthenticationTTL is how long a fresh external login stands in for the password.
+	reauthenticationTTL = 5 * time.Minute
+)
+
+var (
+	ErrUnknownIdentityProvider = errors.New("unknown identity provider")
+	ErrInvalidOIDCState        = errors.New("login request expired or was tampered with, please try again")
+	ErrIdentityEmailRequired   = errors.New("the identity provider did not share a verified email address")
+	ErrIdentityEmailConflict   = errors.New("an account with this email already exists, sign in with your password and link the provider from your settings")
+	ErrIdentityAlreadyLinked   = errors.New("this external account is already linked to another user")
+	ErrProviderAlreadyLinked   = errors.New("an account from this provider is already linked")
+	ErrIdentityNotLinked       = errors.New("no account from this provider is linked to yours")
+)
+
+// ExternalIdentity is the account a user proved to own at an identity provider.
+type ExternalIdentity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+// IdentityProvider is the outbound port to an OpenID Connect provider using the
+// authorization code flow with PKCE.
+type IdentityProvider interface {
+	Name() string
+	// AuthCodeURL returns where to send the browser to sign in at the provider.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the authorization code and returns the verified identity. The ID
+	// token must carry nonce.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (ExternalIdentity, error)
+}
+
+// WithIdentityProviders enables sign-in with external OpenID Connect providers.
+func WithIdentityProviders(repo repository.IdentityRepository, providers ...IdentityProvider) UserServiceOption {
+	return func(s *UserService) {
+		s.identityRepo = repo
+		s.identityProviders = make(map[string]IdentityProvider, len(providers))
+		for _, p := range providers {
+			s.identityProviders[p.Name()] = p
+		}
+	}
+}
+
+// OIDCAuthorization is where to send the browser to start an external login, and the
+// state it must bring back.
+type OIDCAuthorization struct {
+	URL        string
+	StateToken string
+	ExpiresAt  time.Time
+}
+
+type OIDCCallbackInput struct {
+	Provider string
+	Code     string
+	State    string
+	// StateToken is the value StartOIDCLogin returned, kept by the browser meanwhile.
+	StateToken string
+	IP         string
+	UserAgent  string
+}
+
+// OIDCLoginResult is the outcome of an external login. Linked is set instead of tokens when
+// the identity was linked to the signed-in user who started the flow, and ReauthToken when
+// that user re-authenticated with it.
+type OIDCLoginResult struct {
+	User        model.User
+	Auth        AuthResult
+	Linked      bool
+	ReauthToken string
+}
+
+// IdentityProviderNames lists the configured providers.
+func (s *UserService) IdentityProviderNames() []string {
+	names := make([]string, 0, len(s.identityProviders))
+	for name := range s.identityProviders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// StartOIDCLogin prepares the redirect to provider. linkUserID is the signed-in user asking
+// to link the provider to their account, or 0 for a login.
+func (s *UserService) StartOIDCLogin(ctx context.Context, provider string, linkUserID uint) (OIDCAuthorization, error) {
+	return s.startOIDC(ctx, provider, linkUserID, false)
+}
+
+// StartOIDCReauthentication prepares the redirect to provider for a signed-in user who
+// confirms a sensitive change by signing in there again. Accounts created through a
+// provider have no password they know, so this is their only way to re-authenticate.
+func (s *UserService) StartOIDCReauthentication(ctx context.Context, provider string, userID uint) (OIDCAuthorization, error) {
+	if s.identityRepo == nil {
+		return OIDCAuthorization{}, ErrUnknownIdentityProvider
+	}
+	linked, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+	if !slices.ContainsFunc(linked, func(l model.UserIdentity) bool { return l.Provider == provider }) {
+		return OIDCAuthorization{}, ErrIdentityNotLinked
+	}
+	return s.startOIDC(ctx, provider, userID, true)
+}
+
+func (s *UserService) startOIDC(ctx context.Context, provider string, linkUserID uint, reauth bool) (OIDCAuthorization, error) {
+	p, ok := s.identityProviders[provider]
+	if !ok || s.identityRepo == nil {
+		return OIDCAuthorization{}, ErrUnknownIdentityProvider
+	}
+
+	state, err := auth.NewOIDCState(provider, linkUserID)
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+	state.Reauth = reauth
+	url, err := p.AuthCodeURL(ctx, state.State, state.Nonce, auth.PKCEChallenge(state.Verifier))
+	if err != nil {
+		return OIDCAuthorization{}, fmt.Errorf("%s: %w", provider, err)
+	}
+	token, err := auth.GenerateOIDCStateToken(s.jwtKeys, state, oidcStateTTL)
+	if err != nil {
+		return OIDCAuthorization{}, err
+	}
+	return OIDCAuthorization{URL: url, StateToken: token, ExpiresAt: s.clock.Now().Add(oidcStateTTL)}, nil
+}
+
+// CompleteOIDCLogin handles the provider's redirect back: it checks the state, redeems the
+// code, then signs in the user the identity belongs to. Unknown identities are linked to the
+// account with the same verified email, or get a new account.
+func (s *UserService) CompleteOIDCLogin(ctx context.Context, req OIDCCallbackInput) (OIDCLoginResult, error) {
+	p, ok := s.identityProviders[req.Provider]
+	if !ok || s.identityRepo == nil {
+		return OIDCLoginResult{}, ErrUnknownIdentityProvider
+	}
+
+	state, err := auth.VerifyOIDCStateToken(s.jwtKeys, req.StateToken)
+	if err != nil || state.Provider != req.Provider ||
+		subtle.ConstantTimeCompare([]byte(state.State), []byte(req.State)) != 1 {
+		return OIDCLoginResult{}, ErrInvalidOIDCState
+	}
+
+	identity, err := p.Exchange(ctx, req.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		s.auditOIDCLogin(ctx, req.Provider, nil, err)
+		return OIDCLoginResult{}, fmt.Errorf("%s: %w", req.Provider, err)
+	}
+	identity.Provider = req.Provider
+
+	if state.Reauth {
+		user, token, err := s.reauthenticateWithIdentity(ctx, state.LinkUserID, identity)
+		s.auditUserAction(ctx, state.LinkUserID, state.LinkUserID, model.AuditReauthenticate, "provider "+req.Provider, err)
+		if err != nil {
+			return OIDCLoginResult{}, err
+		}
+		return OIDCLoginResult{User: user, ReauthToken: token}, nil
+	}
+
+	if state.LinkUserID != 0 {
+		user, err := s.linkIdentity(ctx, state.LinkUserID, identity)
+		s.auditUserAction(ctx, state.LinkUserID, state.LinkUserID, model.AuditIdentityLink, "provider "+req.Provider, err)
+		if err != nil {
+			return OIDCLoginResult{}, err
+		}
+		return OIDCLoginResult{User: user, Linked: true}, nil
+	}
+
+	user, err := s.identityUser(ctx, identity)
+	if err != nil {
+		s.auditOIDCLogin(ctx, req.Provider, nil, err)
+		return OIDCLoginResult{}, err
+	}
+	if err := checkAccountStatus(user); err != nil {
+		// The provider vouches for the identity, so a pending password reset does not apply.
+		if !errors.Is(err, ErrPasswordResetRequired) {
+			s.auditOIDCLogin(ctx, req.Provider, &user.ID, err)
+			return OIDCLoginResult{}, err
+		}
+	}
+	if err := s.identityRepo.TouchLogin(ctx, identity.Provider, identity.Subject, s.clock.Now()); err != nil {
+		log.Printf("Error updating %s identity of user %d: %v", identity.Provider, user.ID, err)
+	}
+
+	challenge, err := s.twoFactorChallenge(ctx, user)
+	if err != nil {
+		return OIDCLoginResult{}, err
+	}
+	if challenge != "" {
+		return OIDCLoginResult{User: user, Auth: AuthResult{TwoFactorChallenge: challenge}}, nil
+	}
+
+	result, err := s.startSession(ctx, user, req.IP, req.UserAgent)
+	if err != nil {
+		return OIDCLoginResult{}, err
+	}
+	s.auditOIDCLogin(ctx, req.Provider, &user.ID, nil)
+	log.Printf("User %s signed in with %s", user.Username, req.Provider)
+	return OIDCLoginResult{User: user, Auth: result}, nil
+}
+
+// ListIdentities returns the external accounts linked to the user.
+func (s *UserService) ListIdentities(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	if s.identityRepo == nil {
+		return nil, ErrUnknownIdentityProvider
+	}
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+// identityUser returns the user an external identity signs in as, linking or creating the
+// account on first use.
+func (s *UserService) identityUser(ctx context.Context, identity ExternalIdentity) (model.User, error) {
+	linked, err := s.identityRepo.Find(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.userRepo.FindByID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return model.User{}, ErrIdentityEmailRequired
+	}
+
+	user, err := s.userRepo.FindByUsernameOrEmail(ctx, identity.Email)
+	switch {
+	case err == nil && strings.EqualFold(user.Email, identity.Email):
+		// Only link by email when we verified the address too; otherwise whoever registered
+		// it here first, possibly not its owner, would get the provider's sign-ins.
+		if !user.EmailVerified {
+			return model.User{}, ErrIdentityEmailConflict
+		}
+	case err != nil && err.Error() != "user not found":
+		return model.User{}, err
+	default:
+		if user, err = s.createIdentityUser(ctx, identity); err != nil {
+			return model.User{}, err
+		}
+	}
+
+	if err := s.createIdentity(ctx, user.ID, identity); err != nil {
+		return model.User{}, err
+	}
+	return user, nil
+}
+
+// reauthenticateWithIdentity checks that the identity the user just signed in with is linked
+// to them and returns a token that stands in for their password for a few minutes.
+func (s *UserService) reauthenticateWithIdentity(ctx context.Context, userID uint, identity ExternalIdentity) (model.User, string, error) {
+	linked, err := s.identityRepo.Find(ctx, identity.Provider, identity.Subject)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && linked.UserID != userID) {
+		return model.User{}, "", ErrIdentityNotLinked
+	}
+	if err != nil {
+		return model.User{}, "", err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return model.User{}, "", err
+	}
+	token, err := auth.GenerateReauthenticationToken(s.jwtKeys, user.ID, reauthenticationTTL)
+	if err != nil {
+		return model.User{}, "", err
+	}
+	return user, token, nil
+}
+
+// linkIdentity attaches an external identity to a signed-in user.
+func (s *UserService) linkIdentity(ctx context.Context, userID uint, identity ExternalIdentity) (model.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	existing, err := s.identityRepo.Find(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return model.User{}, ErrIdentityAlreadyLinked
+		}
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, err
+	}
+
+	linked, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return model.User{}, err
+	}
+	for _, l := range linked {
+		if l.Provider == identity.Provider {
+			return model.User{}, ErrProviderAlreadyLinked
+		}
+	}
+	return user, s.createIdentity(ctx, userID, identity)
+}
+
+func (s *UserService) createIdentity(ctx context.Context, userID uint, identity ExternalIdentity) error {
+	return s.identityRepo.Create(ctx, model.UserIdentity{
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		UserID:    userID,
+		Email:     identity.Email,
+		CreatedAt: s.clock.Now(),
+	})
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// createIdentityUser registers an account for a first-time external login. It gets a
+// random password, so it can only sign in through the provider until the user resets it.
+func (s *UserService) createIdentityUser(ctx context.Context, identity ExternalIdentity) (model.User, error) {
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return model.User{}, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(base64.RawURLEncoding.EncodeToString(b)), bcrypt.DefaultCost)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	if err := s.userRepo.Save(ctx, model.User{
+		Username: username,
+		Email:    identity.Email,
+		Password: string(hashed),
+		Role:     model.RoleUser,
+	}); err != nil {
+		return model.User{}, err
+	}
+	user, err := s.userRepo.FindByUsernameOrEmail(ctx, identity.Email)
+	if err != nil {
+		return model.User{}, err
+	}
+	// The provider verified the address.
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return model.User{}, err
+	}
+	user.EmailVerified = true
+	log.Printf("Created user %s for %s identity", user.Username, identity.Provider)
+	return user, nil
+}
+
+// availableUsername derives a free username from the identity, appending a number when
+// the natural choice is taken.
+func (s *UserService) availableUsername(ctx context.Context, identity ExternalIdentity) (string, error) {
+	base := identity.PreferredUsername
+	if V.Username(sanitizeUsername(base)) != nil {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = sanitizeUsername(base)
+	if len(base) < 3 {
+		base = "user"
+	}
+	if len(base) > 20 {
+		base = base[:20]
+	}
+
+	for i := 0; i < 100; i++ {
+		candidate := base
+		if i > 0 {
+			candidate += strconv.Itoa(i + 1)
+		}
+		if V.Username(candidate) != nil {
+			continue
+		}
+		_, err := s.userRepo.FindByUsernameOrEmail(ctx, candidate)
+		if err != nil && err.Error() == "user not found" {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("could not find a free username")
+}
+
+func sanitizeUsername(name string) string {
+	name = usernameInvalidChars.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-")
+}
+
+func (s *UserService) auditOIDCLogin(ctx context.Context, provider string, userID *uint, err error) {
+	event := model.AuditEvent{
+		ActorID: userID,
+		Action:  model.AuditLogin,
+		Result:  auditResult(err),
+		Detail:  "oidc " + provider,
+	}
+	if userID != nil {
+		event.TargetType = "user"
+		event.TargetID = userStatusKey(*userID)
+	}
+	if err != nil {
+		event.Detail += ": " + err.Error()
+	}
+	s.audit.Record(ctx, event)
+}
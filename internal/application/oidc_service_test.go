@@ -0,0 +1,249 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeIdentityProvider accepts the code "good-code" and returns identity, checking that the
+// nonce and PKCE verifier match what the authorization request carried.
+type fakeIdentityProvider struct {
+	identity  ExternalIdentity
+	nonce     string
+	challenge string
+}
+
+func (p *fakeIdentityProvider) Name() string { return "corp" }
+
+func (p *fakeIdentityProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	p.nonce, p.challenge = nonce, codeChallenge
+	return "https://idp.example.com/authorize?" + url.Values{"state": {state}}.Encode(), nil
+}
+
+func (p *fakeIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (ExternalIdentity, error) {
+	if code != "good-code" || nonce != p.nonce || auth.PKCEChallenge(codeVerifier) != p.challenge {
+		return ExternalIdentity{}, errors.New("invalid_grant")
+	}
+	return p.identity, nil
+}
+
+type oidcFixture struct {
+	svc        *UserService
+	users      map[uint]*model.User
+	identities *fakeIdentityRepository
+	provider   *fakeIdentityProvider
+}
+
+func newOIDCFixture(t *testing.T) *oidcFixture {
+	t.Helper()
+	f := &oidcFixture{
+		users:      map[uint]*model.User{},
+		identities: &fakeIdentityRepository{},
+		provider: &fakeIdentityProvider{identity: ExternalIdentity{
+			Subject: "ext-1", Email: "erin@example.com", EmailVerified: true, PreferredUsername: "erin.w",
+		}},
+	}
+	repo := &fakeUserRepository{
+		saveFn: func(user model.User) error {
+			user.ID = uint(len(f.users) + 1)
+			f.users[user.ID] = &user
+			return nil
+		},
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) {
+			for _, u := range f.users {
+				if u.Username == identifier || strings.EqualFold(u.Email, identifier) {
+					return *u, nil
+				}
+			}
+			return model.User{}, errors.New("user not found")
+		},
+		findByIDFn: func(id uint) (model.User, error) {
+			if u, ok := f.users[id]; ok {
+				return *u, nil
+			}
+			return model.User{}, errors.New("user not found")
+		},
+		markEmailVerifiedFn: func(id uint) error {
+			f.users[id].EmailVerified = true
+			return nil
+		},
+	}
+	f.svc = NewUserService(repo, loginTestKeys,
+		WithSessionRepository(newFakeSessionRepository()),
+		WithIdentityProviders(f.identities, f.provider),
+	)
+	return f
+}
+
+// login runs the redirect round trip through the provider.
+func (f *oidcFixture) login(t *testing.T, linkUserID uint) (OIDCLoginResult, error) {
+	t.Helper()
+	authz, err := f.svc.StartOIDCLogin(context.Background(), "corp", linkUserID)
+	if err != nil {
+		t.Fatalf("StartOIDCLogin() error = %v", err)
+	}
+	u, _ := url.Parse(authz.URL)
+	return f.svc.CompleteOIDCLogin(context.Background(), OIDCCallbackInput{
+		Provider:   "corp",
+		Code:       "good-code",
+		State:      u.Query().Get("state"),
+		StateToken: authz.StateToken,
+	})
+}
+
+func TestOIDCFirstLoginCreatesVerifiedAccount(t *testing.T) {
+	f := newOIDCFixture(t)
+
+	result, err := f.login(t, 0)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin() error = %v", err)
+	}
+	if result.Auth.AccessToken == "" || result.Auth.RefreshToken == "" {
+		t.Fatalf("result = %+v, want tokens", result)
+	}
+	if result.User.Username != "erin-w" || !result.User.EmailVerified || result.User.Role != model.RoleUser {
+		t.Fatalf("created user = %+v", result.User)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].UserID != result.User.ID {
+		t.Fatalf("identities = %+v, want one linked to the new user", f.identities.identities)
+	}
+
+	// The next login goes through the link, even after the email changed at the provider.
+	f.provider.identity.Email = "erin@new.example.com"
+	again, err := f.login(t, 0)
+	if err != nil {
+		t.Fatalf("second CompleteOIDCLogin() error = %v", err)
+	}
+	if again.User.ID != result.User.ID || len(f.users) != 1 {
+		t.Fatalf("second login signed in user %d with %d users, want the same single user", again.User.ID, len(f.users))
+	}
+}
+
+func TestOIDCLinksOnlyVerifiedLocalAccounts(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.users[1] = &model.User{ID: 1, Username: "erin", Email: "Erin@example.com", Role: model.RoleUser}
+
+	if _, err := f.login(t, 0); !errors.Is(err, ErrIdentityEmailConflict) {
+		t.Fatalf("CompleteOIDCLogin() error = %v, want %v", err, ErrIdentityEmailConflict)
+	}
+
+	f.users[1].EmailVerified = true
+	result, err := f.login(t, 0)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin() error = %v", err)
+	}
+	if result.User.ID != 1 || len(f.users) != 1 {
+		t.Fatalf("signed in user %d, want the existing account", result.User.ID)
+	}
+}
+
+func TestOIDCRequiresVerifiedEmailForNewIdentities(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.provider.identity.EmailVerified = false
+
+	if _, err := f.login(t, 0); !errors.Is(err, ErrIdentityEmailRequired) {
+		t.Fatalf("CompleteOIDCLogin() error = %v, want %v", err, ErrIdentityEmailRequired)
+	}
+}
+
+func TestOIDCRejectsTamperedState(t *testing.T) {
+	f := newOIDCFixture(t)
+	authz, err := f.svc.StartOIDCLogin(context.Background(), "corp", 0)
+	if err != nil {
+		t.Fatalf("StartOIDCLogin() error = %v", err)
+	}
+
+	for name, req := range map[string]OIDCCallbackInput{
+		"wrong state":    {Provider: "corp", Code: "good-code", State: "forged", StateToken: authz.StateToken},
+		"missing cookie": {Provider: "corp", Code: "good-code", State: "forged"},
+	} {
+		if _, err := f.svc.CompleteOIDCLogin(context.Background(), req); !errors.Is(err, ErrInvalidOIDCState) {
+			t.Fatalf("%s: CompleteOIDCLogin() error = %v, want %v", name, err, ErrInvalidOIDCState)
+		}
+	}
+	if _, err := f.svc.StartOIDCLogin(context.Background(), "unknown", 0); !errors.Is(err, ErrUnknownIdentityProvider) {
+		t.Fatalf("StartOIDCLogin(unknown) error = %v, want %v", err, ErrUnknownIdentityProvider)
+	}
+}
+
+func TestOIDCLinksIdentityToSignedInUser(t *testing.T) {
+	f := newOIDCFixture(t)
+	f.users[1] = &model.User{ID: 1, Username: "erin", Email: "erin@work.example.com", Role: model.RoleUser}
+
+	result, err := f.login(t, 1)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin(link) error = %v", err)
+	}
+	if !result.Linked || result.Auth.AccessToken != "" {
+		t.Fatalf("result = %+v, want a link without new tokens", result)
+	}
+	identities, _ := f.svc.ListIdentities(context.Background(), 1)
+	if len(identities) != 1 || identities[0].Provider != "corp" {
+		t.Fatalf("identities = %+v, want corp", identities)
+	}
+
+	f.users[2] = &model.User{ID: 2, Username: "mallory", Email: "mallory@example.com"}
+	if _, err := f.login(t, 2); !errors.Is(err, ErrIdentityAlreadyLinked) {
+		t.Fatalf("CompleteOIDCLogin(link by other user) error = %v, want %v", err, ErrIdentityAlreadyLinked)
+	}
+}
+
+func TestOIDCReauthenticationReplacesPasswordOfProviderAccounts(t *testing.T) {
+	f := newOIDCFixture(t)
+	ctx := context.Background()
+	created, err := f.login(t, 0)
+	if err != nil {
+		t.Fatalf("CompleteOIDCLogin() error = %v", err)
+	}
+	userID := created.User.ID
+
+	reauth := func(userID uint) (OIDCLoginResult, error) {
+		authz, err := f.svc.StartOIDCReauthentication(ctx, "corp", userID)
+		if err != nil {
+			return OIDCLoginResult{}, err
+		}
+		u, _ := url.Parse(authz.URL)
+		return f.svc.CompleteOIDCLogin(ctx, OIDCCallbackInput{
+			Provider:   "corp",
+			Code:       "good-code",
+			State:      u.Query().Get("state"),
+			StateToken: authz.StateToken,
+		})
+	}
+
+	result, err := reauth(userID)
+	if err != nil {
+		t.Fatalf("reauthentication error = %v", err)
+	}
+	if result.ReauthToken == "" || result.Auth.AccessToken != "" || result.Linked {
+		t.Fatalf("result = %+v, want only a reauth token", result)
+	}
+
+	err = f.svc.ChangeUsername(ctx, ChangeUsernameInput{UserID: userID, ReauthToken: result.ReauthToken, NewUsername: "erin"})
+	if err != nil {
+		t.Fatalf("ChangeUsername(reauth token) error = %v", err)
+	}
+
+	// The token only vouches for the user it was issued to, and is no access token.
+	f.users[2] = &model.User{ID: 2, Username: "mallory", Email: "mallory@example.com"}
+	if err := f.svc.ChangeUsername(ctx, ChangeUsernameInput{UserID: 2, ReauthToken: result.ReauthToken, NewUsername: "mal"}); !errors.Is(err, ErrReauthenticationFailed) {
+		t.Fatalf("ChangeUsername(other user's token) error = %v, want %v", err, ErrReauthenticationFailed)
+	}
+	if err := f.svc.ChangeUsername(ctx, ChangeUsernameInput{UserID: userID, ReauthToken: created.Auth.AccessToken, NewUsername: "erin2"}); !errors.Is(err, ErrReauthenticationFailed) {
+		t.Fatalf("ChangeUsername(access token) error = %v, want %v", err, ErrReauthenticationFailed)
+	}
+
+	// Users can only re-authenticate with a provider linked to them.
+	if _, err := reauth(2); !errors.Is(err, ErrIdentityNotLinked) {
+		t.Fatalf("StartOIDCReauthentication(unlinked) error = %v, want %v", err, ErrIdentityNotLinked)
+	}
+	f.identities.identities = append(f.identities.identities, model.UserIdentity{Provider: "corp", Subject: "ext-2", UserID: 2})
+	if _, err := reauth(2); !errors.Is(err, ErrIdentityNotLinked) {
+		t.Fatalf("CompleteOIDCLogin(reauth as another identity) error = %v, want %v", err, ErrIdentityNotLinked)
+	}
+}
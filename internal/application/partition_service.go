@@ -0,0 +1,80 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultPartitionsAhead is how many months of partitions exist beyond the current one
+// when not configured.
+const DefaultPartitionsAhead = 3
+
+// RetentionPolicy decides how long price and correlation history is kept in full.
+type RetentionPolicy struct {
+	// RawPrices is how long every fetched price is kept before the month is reduced to one
+	// price per commodity and day. Zero keeps raw prices forever.
+	RawPrices time.Duration
+	// Correlations is how long computed correlations are kept. Zero keeps them forever.
+	Correlations time.Duration
+	// PartitionsAhead is how many upcoming months have a partition ready.
+	PartitionsAhead int
+}
+
+// PartitionMaintenance reports what a maintenance run changed.
+type PartitionMaintenance struct {
+	Created     []string
+	Downsampled []string
+	Dropped     []string
+}
+
+// PartitionService creates upcoming monthly partitions and applies the retention policy.
+type PartitionService struct {
+	repo   repository.PartitionRepository
+	policy RetentionPolicy
+	clock  Clock
+}
+
+func NewPartitionService(repo repository.PartitionRepository, policy RetentionPolicy) *PartitionService {
+	if policy.PartitionsAhead <= 0 {
+		policy.PartitionsAhead = DefaultPartitionsAhead
+	}
+	return &PartitionService{repo: repo, policy: policy, clock: systemClock{}}
+}
+
+// Maintain creates the partitions of the current and upcoming months, then downsamples and
+// drops the months past their retention. Retention only ever removes whole months.
+func (s *PartitionService) Maintain(ctx context.Context) (PartitionMaintenance, error) {
+	var report PartitionMaintenance
+	now := s.clock.Now()
+
+	created, err := s.repo.EnsurePartitions(ctx, now, now.AddDate(0, s.policy.PartitionsAhead, 0))
+	report.Created = created
+	if err != nil {
+		return report, fmt.Errorf("create partitions: %w", err)
+	}
+
+	if s.policy.RawPrices > 0 {
+		downsampled, err := s.repo.DownsamplePrices(ctx, now.Add(-s.policy.RawPrices))
+		report.Downsampled = downsampled
+		if err != nil {
+			return report, fmt.Errorf("downsample prices: %w", err)
+		}
+	}
+
+	if s.policy.Correlations > 0 {
+		dropped, err := s.repo.DropCorrelations(ctx, now.Add(-s.policy.Correlations))
+		report.Dropped = dropped
+		if err != nil {
+			return report, fmt.Errorf("drop correlations: %w", err)
+		}
+	}
+	return report, nil
+}
+
+// ListPartitions returns the partitions of the price and correlation tables with their sizes.
+func (s *PartitionService) ListPartitions(ctx context.Context) ([]model.Partition, error) {
+	return s.repo.List(ctx)
+}
@@ -0,0 +1,74 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakePartitionRepository struct {
+	ensureFrom, ensureThrough time.Time
+	downsampleBefore          *time.Time
+	dropBefore                *time.Time
+	ensureErr                 error
+}
+
+func (f *fakePartitionRepository) EnsurePartitions(ctx context.Context, from, through time.Time) ([]string, error) {
+	f.ensureFrom, f.ensureThrough = from, through
+	if f.ensureErr != nil {
+		return nil, f.ensureErr
+	}
+	return []string{"commodities_p2026_12"}, nil
+}
+
+func (f *fakePartitionRepository) DownsamplePrices(ctx context.Context, before time.Time) ([]string, error) {
+	f.downsampleBefore = &before
+	return []string{"commodities_p2026_06"}, nil
+}
+
+func (f *fakePartitionRepository) DropCorrelations(ctx context.Context, before time.Time) ([]string, error) {
+	f.dropBefore = &before
+	return nil, nil
+}
+
+func (f *fakePartitionRepository) List(ctx context.Context) ([]model.Partition, error) {
+	return nil, nil
+}
+
+func TestPartitionMaintenanceAppliesRetention(t *testing.T) {
+	now := time.Date(2026, time.September, 15, 12, 0, 0, 0, time.UTC)
+	repo := &fakePartitionRepository{}
+	svc := NewPartitionService(repo, RetentionPolicy{RawPrices: 90 * 24 * time.Hour})
+	svc.clock = &fakeClock{now: now}
+
+	report, err := svc.Maintain(context.Background())
+	if err != nil {
+		t.Fatalf("Maintain: %v", err)
+	}
+	if !repo.ensureFrom.Equal(now) || !repo.ensureThrough.Equal(now.AddDate(0, DefaultPartitionsAhead, 0)) {
+		t.Errorf("unexpected partition range %v - %v", repo.ensureFrom, repo.ensureThrough)
+	}
+	if repo.downsampleBefore == nil || !repo.downsampleBefore.Equal(now.Add(-90*24*time.Hour)) {
+		t.Errorf("unexpected downsample cutoff %v", repo.downsampleBefore)
+	}
+	if repo.dropBefore != nil {
+		t.Error("correlations should be kept without a correlation retention")
+	}
+	if len(report.Created) != 1 || len(report.Downsampled) != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+func TestPartitionMaintenanceStopsWhenPartitionsCannotBeCreated(t *testing.T) {
+	repo := &fakePartitionRepository{ensureErr: errors.New("disk full")}
+	svc := NewPartitionService(repo, RetentionPolicy{RawPrices: time.Hour, Correlations: time.Hour})
+
+	if _, err := svc.Maintain(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if repo.downsampleBefore != nil || repo.dropBefore != nil {
+		t.Error("retention should not run after a failed partition creation")
+	}
+}
@@ -1,8 +1,12 @@
 package application
 
 import (
+	"backend/internal/domain/model"
 	"context"
 	"errors"
+	"fmt"
+	"log"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -26,7 +30,9 @@ func (s *UserService) ChangePassword(ctx context.Context, req ChangePasswordInpu
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
-		return errors.New("incorrect current password")
+		err = errors.New("incorrect current password")
+		s.auditUserAction(ctx, user.ID, user.ID, model.AuditPasswordChange, "", err)
+		return err
 	}
 
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
@@ -34,5 +40,78 @@ func (s *UserService) ChangePassword(ctx context.Context, req ChangePasswordInpu
 		return err
 	}
 
-	return s.userRepo.UpdatePassword(ctx, req.UserID, string(hashedPassword))
+	if err := s.userRepo.UpdatePassword(ctx, req.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+	s.auditUserAction(ctx, user.ID, user.ID, model.AuditPasswordChange, "", nil)
+	return nil
+}
+
+// RequestPasswordReset emails a reset link when the address belongs to an account. It
+// reports success either way so the endpoint cannot be used to discover accounts.
+func (s *UserService) RequestPasswordReset(ctx context.Context, email string) error {
+	if s.tokenRepo == nil || s.mailer == nil {
+		return ErrAccountEmailsUnavailable
+	}
+
+	email = strings.TrimSpace(email)
+	if !strings.Contains(email, "@") {
+		return errors.New("a valid email is required")
+	}
+	user, err := s.userRepo.FindByUsernameOrEmail(ctx, email)
+	if err != nil || !strings.EqualFold(user.Email, email) {
+		return nil
+	}
+
+	err = s.sendPasswordReset(ctx, user)
+	s.auditUserAction(ctx, user.ID, user.ID, model.AuditPasswordResetRequest, "", err)
+	return err
+}
+
+// sendPasswordReset emails a new reset link and invalidates the previous ones, so only the
+// most recent link works.
+func (s *UserService) sendPasswordReset(ctx context.Context, user model.User) error {
+	if err := s.tokenRepo.InvalidateAll(ctx, user.ID, model.TokenPurposePasswordReset, s.clock.Now()); err != nil {
+		return err
+	}
+	token, err := s.issueUserToken(ctx, user.ID, model.TokenPurposePasswordReset, passwordResetTTL, "")
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, Email{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nSomeone asked to reset the password of your account. If it was you, open this link:\n\n%s\n\nThe link expires in %d minutes. If you did not ask for it, you can ignore this email.\n",
+			user.Username, s.appLink("/reset-password", token), int(passwordResetTTL.Minutes())),
+	})
+}
+
+// ResetPassword sets a new password from a reset link and signs the user out everywhere.
+func (s *UserService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if s.tokenRepo == nil {
+		return ErrAccountEmailsUnavailable
+	}
+	if err := V.Password(newPassword); err != nil {
+		return err
+	}
+
+	t, err := s.consumeUserToken(ctx, model.TokenPurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdatePassword(ctx, t.UserID, string(hashedPassword)); err != nil {
+		return err
+	}
+	// Receiving the link proves control of the address.
+	if err := s.userRepo.MarkEmailVerified(ctx, t.UserID); err != nil {
+		log.Printf("Error marking email of user %d verified: %v", t.UserID, err)
+	}
+	s.auditUserAction(ctx, t.UserID, t.UserID, model.AuditPasswordReset, "", nil)
+
+	return s.revokeAllSessions(ctx, t.UserID)
 }
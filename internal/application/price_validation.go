@@ -0,0 +1,69 @@
+package application
+
+import (
+	"backend/internal/domain/algorithm"
+	"backend/internal/domain/model"
+	"fmt"
+	"math"
+)
+
+const (
+	// priceValidationWindow is how many stored prices a candidate is compared against.
+	priceValidationWindow = 30
+	// minZScoreSample is the smallest history on which the robust z-score is trusted.
+	minZScoreSample = 10
+)
+
+// PriceValidator screens freshly fetched prices before they reach the commodities table.
+type PriceValidator struct {
+	// MaxJumpPct is the largest accepted move, in percent, from the latest stored price.
+	MaxJumpPct float64
+	// MaxZScore is the largest accepted robust z-score against recent history.
+	MaxZScore float64
+}
+
+func NewPriceValidator(maxJumpPct, maxZScore float64) PriceValidator {
+	return PriceValidator{MaxJumpPct: maxJumpPct, MaxZScore: maxZScore}
+}
+
+// Check returns a human readable reason when the candidate looks like a provider glitch,
+// or an empty string when it can be stored. history is ordered newest first.
+func (v PriceValidator) Check(candidate model.Commodity, history []model.Commodity) string {
+	price := candidate.PriceKg
+	if math.IsNaN(price) || math.IsInf(price, 0) {
+		return "price is not a finite number"
+	}
+	if price == 0 {
+		return "price is zero"
+	}
+	if price < 0 {
+		return fmt.Sprintf("price is negative (%.4f)", price)
+	}
+
+	if len(history) > priceValidationWindow {
+		history = history[:priceValidationWindow]
+	}
+	if len(history) == 0 {
+		return ""
+	}
+
+	if latest := history[0].PriceKg; v.MaxJumpPct > 0 && latest > 0 {
+		jump := math.Abs(price-latest) / latest * 100
+		if jump > v.MaxJumpPct {
+			return fmt.Sprintf("price moved %.1f%% from last stored %.4f (max %.1f%%)", jump, latest, v.MaxJumpPct)
+		}
+	}
+
+	if v.MaxZScore > 0 && len(history) >= minZScoreSample {
+		sample := make([]float64, len(history))
+		for i, c := range history {
+			sample[i] = c.PriceKg
+		}
+		z, err := algorithm.RobustZScore(price, sample)
+		if err == nil && math.Abs(z) > v.MaxZScore {
+			return fmt.Sprintf("robust z-score %.1f exceeds %.1f over last %d prices", z, v.MaxZScore, len(sample))
+		}
+	}
+
+	return ""
+}
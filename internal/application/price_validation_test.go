@@ -0,0 +1,117 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	stdErrors "errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func flatHistory(name string, n int, price float64) []model.Commodity {
+	history := make([]model.Commodity, n)
+	for i := range history {
+		// Small alternating wiggle so the sample has a non-zero spread.
+		wiggle := 0.001 * price * float64(i%3-1)
+		history[i] = model.Commodity{Name: name, Date: time.Now().AddDate(0, 0, -i), PriceKg: price + wiggle, Unit: "USD/kg"}
+	}
+	return history
+}
+
+func TestPriceValidatorRejectsNonPositiveAndNaN(t *testing.T) {
+	v := NewPriceValidator(25, 8)
+
+	for _, price := range []float64{0, -3, math.NaN(), math.Inf(1)} {
+		if reason := v.Check(model.Commodity{PriceKg: price}, nil); reason == "" {
+			t.Fatalf("price %v should be rejected", price)
+		}
+	}
+}
+
+func TestPriceValidatorAcceptsFirstPriceWithoutHistory(t *testing.T) {
+	v := NewPriceValidator(25, 8)
+
+	if reason := v.Check(model.Commodity{PriceKg: 42}, nil); reason != "" {
+		t.Fatalf("unexpected rejection: %s", reason)
+	}
+}
+
+func TestPriceValidatorFlagsLargeJump(t *testing.T) {
+	v := NewPriceValidator(25, 0)
+	history := flatHistory("copper", 3, 10)
+
+	reason := v.Check(model.Commodity{PriceKg: 14}, history)
+	if !strings.Contains(reason, "moved") {
+		t.Fatalf("reason = %q, want jump rejection", reason)
+	}
+	if reason := v.Check(model.Commodity{PriceKg: 11}, history); reason != "" {
+		t.Fatalf("10%% move should pass, got %q", reason)
+	}
+}
+
+func TestPriceValidatorFlagsRobustOutlier(t *testing.T) {
+	v := NewPriceValidator(0, 8)
+	history := flatHistory("gold", 20, 1000)
+
+	reason := v.Check(model.Commodity{PriceKg: 1100}, history)
+	if !strings.Contains(reason, "z-score") {
+		t.Fatalf("reason = %q, want z-score rejection", reason)
+	}
+	if reason := v.Check(model.Commodity{PriceKg: 1000.5}, history); reason != "" {
+		t.Fatalf("in-range price rejected: %q", reason)
+	}
+}
+
+func TestUpdateSymbolsQuarantinesSuspiciousPrice(t *testing.T) {
+	repo := &fakeCommodityRepository{history: map[string][]model.Commodity{
+		"gold": flatHistory("gold", 20, 1000),
+	}}
+	quarantine := &fakeQuarantineRepository{}
+	provider := &fakePriceProvider{prices: map[string]*model.Commodity{
+		"gold":   {Name: "gold", Date: time.Now(), PriceKg: 0, Unit: "USD/kg"},
+		"silver": {Name: "silver", Date: time.Now(), PriceKg: 30, Unit: "USD/kg"},
+	}}
+	svc := NewCommodityService(provider, repo, quarantine, NewPriceValidator(25, 8), NewFXService(nil, &fakeFXRateRepository{}))
+
+	if err := svc.UpdatePreciousPrices(context.Background()); err != nil {
+		t.Fatalf("UpdatePreciousPrices() error = %v", err)
+	}
+
+	if len(repo.saved) != 1 || repo.saved[0].Name != "silver" {
+		t.Fatalf("saved = %+v, want only silver", repo.saved)
+	}
+	if len(quarantine.entries) != 1 || quarantine.entries[0].Name != "gold" {
+		t.Fatalf("quarantined = %+v, want gold", quarantine.entries)
+	}
+
+	statuses, _ := svc.GetStatuses(context.Background())
+	for _, st := range statuses {
+		if st.Name == "gold" && (st.Quarantined != 1 || !strings.Contains(st.LastError, "quarantined")) {
+			t.Fatalf("gold status = %+v, want flagged quarantine", st)
+		}
+	}
+}
+
+func TestApproveQuarantinedSavesPriceOnce(t *testing.T) {
+	repo := &fakeCommodityRepository{}
+	quarantine := &fakeQuarantineRepository{}
+	quarantine.Save(context.Background(), model.QuarantinedPrice{Name: "brent", PriceKg: 0.6, Unit: "USD/kg", Status: model.QuarantineStatusPending})
+	svc := NewCommodityService(&fakePriceProvider{}, repo, quarantine, NewPriceValidator(25, 8), NewFXService(nil, &fakeFXRateRepository{}))
+
+	if err := svc.ApproveQuarantined(context.Background(), 1, 7); err != nil {
+		t.Fatalf("ApproveQuarantined() error = %v", err)
+	}
+	if len(repo.saved) != 1 || repo.saved[0].PriceKg != 0.6 {
+		t.Fatalf("saved = %+v, want approved brent price", repo.saved)
+	}
+
+	err := svc.RejectQuarantined(context.Background(), 1, 7)
+	if !stdErrors.Is(err, ErrQuarantineAlreadyReviewed) {
+		t.Fatalf("error = %v, want ErrQuarantineAlreadyReviewed", err)
+	}
+	if err := svc.ApproveQuarantined(context.Background(), 99, 7); !stdErrors.Is(err, ErrQuarantineNotFound) {
+		t.Fatalf("error = %v, want ErrQuarantineNotFound", err)
+	}
+}
@@ -0,0 +1,353 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/domain/units"
+	"backend/internal/errors"
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	emailChangeTTL = 24 * time.Hour
+
+	DefaultTimezone = "UTC"
+	// maxDisplayNameLength is counted in characters.
+	maxDisplayNameLength = 64
+)
+
+var (
+	ErrPreferencesUnavailable = stdErrors.New("preferences are not enabled on this server")
+	ErrIncorrectPassword      = stdErrors.New("incorrect password")
+	ErrReauthenticationFailed = stdErrors.New("sign-in confirmation expired, please sign in again")
+	ErrUsernameTaken          = stdErrors.New("username already taken")
+	ErrEmailTaken             = stdErrors.New("an account with this email already exists")
+)
+
+// WithPreferences enables stored profile and display preferences.
+func WithPreferences(repo repository.PreferencesRepository) UserServiceOption {
+	return func(s *UserService) { s.preferencesRepo = repo }
+}
+
+// PreferencesUpdate changes the fields that are set; an empty string restores the default.
+type PreferencesUpdate struct {
+	DisplayName   *string
+	Timezone      *string
+	Currency      *string
+	Unit          *string
+	ChartInterval *string
+	Theme         *string
+}
+
+// ChangeEmailInput and ChangeUsernameInput confirm the user with either their password or a
+// ReauthToken from a fresh external login.
+type ChangeEmailInput struct {
+	UserID      uint
+	Password    string
+	ReauthToken string
+	NewEmail    string
+}
+
+type ChangeUsernameInput struct {
+	UserID      uint
+	Password    string
+	ReauthToken string
+	NewUsername string
+}
+
+// DefaultPreferences are the settings of a user who never changed them.
+func DefaultPreferences(userID uint) model.UserPreferences {
+	return model.UserPreferences{
+		UserID:        userID,
+		Timezone:      DefaultTimezone,
+		Currency:      BaseCurrency,
+		Unit:          string(units.Kilogram),
+		ChartInterval: DefaultChartInterval,
+		Theme:         model.ThemeSystem,
+	}
+}
+
+// GetPreferences returns the user's preferences with defaults filled in for unset fields.
+func (s *UserService) GetPreferences(ctx context.Context, userID uint) (model.UserPreferences, error) {
+	if s.preferencesRepo == nil {
+		return DefaultPreferences(userID), nil
+	}
+	prefs, err := s.preferencesRepo.Get(ctx, userID)
+	if err != nil {
+		if stdErrors.Is(err, sql.ErrNoRows) {
+			return DefaultPreferences(userID), nil
+		}
+		return model.UserPreferences{}, err
+	}
+	return withDefaultPreferences(prefs), nil
+}
+
+// UpdatePreferences validates and applies a partial update and returns the result.
+func (s *UserService) UpdatePreferences(ctx context.Context, userID uint, req PreferencesUpdate) (model.UserPreferences, error) {
+	if s.preferencesRepo == nil {
+		return model.UserPreferences{}, ErrPreferencesUnavailable
+	}
+
+	prefs, err := s.preferencesRepo.Get(ctx, userID)
+	if err != nil {
+		if !stdErrors.Is(err, sql.ErrNoRows) {
+			return model.UserPreferences{}, err
+		}
+		prefs = model.UserPreferences{UserID: userID}
+	}
+
+	var errs errors.ValidationErrors
+	apply := func(field string, value *string, dst *string, normalize func(string) (string, error)) {
+		if value == nil {
+			return
+		}
+		v := strings.TrimSpace(*value)
+		if v != "" {
+			var err error
+			if v, err = normalize(v); err != nil {
+				errs = append(errs, errors.ValidationError{Field: field, Code: "invalid_value"})
+				return
+			}
+		}
+		*dst = v
+	}
+	apply("display_name", req.DisplayName, &prefs.DisplayName, normalizeDisplayName)
+	apply("timezone", req.Timezone, &prefs.Timezone, normalizeTimezone)
+	apply("currency", req.Currency, &prefs.Currency, NormalizeCurrency)
+	apply("unit", req.Unit, &prefs.Unit, func(v string) (string, error) {
+		unit, err := units.Parse(v)
+		return string(unit), err
+	})
+	apply("chart_interval", req.ChartInterval, &prefs.ChartInterval, normalizeChartInterval)
+	apply("theme", req.Theme, &prefs.Theme, normalizeTheme)
+	if len(errs) > 0 {
+		return model.UserPreferences{}, errs
+	}
+
+	prefs.UpdatedAt = s.clock.Now()
+	if err := s.preferencesRepo.Save(ctx, prefs); err != nil {
+		return model.UserPreferences{}, err
+	}
+	return withDefaultPreferences(prefs), nil
+}
+
+// PriceOptionsWithPreferences fills the options a request left empty from the user's
+// preferences. A preferred unit that does not apply to the commodity, such as barrels for
+// gold, is skipped so the commodity keeps its default unit.
+func PriceOptionsWithPreferences(opts PriceOptions, prefs model.UserPreferences, commodity string) PriceOptions {
+	if opts.Currency == "" {
+		opts.Currency = prefs.Currency
+	}
+	if opts.Unit == "" && prefs.Unit != "" {
+		if unit, err := units.Parse(prefs.Unit); err == nil {
+			if _, err := units.KgPer(strings.ToLower(commodity), unit); err == nil {
+				opts.Unit = prefs.Unit
+			}
+		}
+	}
+	return opts
+}
+
+// ChangeEmail changes the account address after checking the password. With account emails
+// enabled, the new address only takes effect once the link sent to it is opened, and the old
+// address is told about the request. Without them the address changes at once, unverified.
+// It reports whether the change waits for confirmation.
+func (s *UserService) ChangeEmail(ctx context.Context, req ChangeEmailInput) (pending bool, err error) {
+	newEmail := strings.TrimSpace(req.NewEmail)
+	if err := V.Email(newEmail); err != nil {
+		return false, err
+	}
+
+	user, err := s.reauthenticate(ctx, req.UserID, req.Password, req.ReauthToken, model.AuditEmailChange)
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return false, errors.ValidationErrors{{Field: "email", Code: "unchanged"}}
+	}
+	if err := s.checkEmailAvailable(ctx, user.ID, newEmail); err != nil {
+		return false, err
+	}
+
+	if s.tokenRepo == nil || s.mailer == nil {
+		err = s.userRepo.UpdateEmail(ctx, user.ID, newEmail, false)
+		s.auditUserAction(ctx, user.ID, user.ID, model.AuditEmailChange, newEmail, err)
+		return false, err
+	}
+
+	// Only the most recent request can be confirmed.
+	if err := s.tokenRepo.InvalidateAll(ctx, user.ID, model.TokenPurposeEmailChange, s.clock.Now()); err != nil {
+		return false, err
+	}
+	token, err := s.issueUserToken(ctx, user.ID, model.TokenPurposeEmailChange, emailChangeTTL, newEmail)
+	if err != nil {
+		return false, err
+	}
+	if err := s.mailer.Send(ctx, Email{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: fmt.Sprintf("Hello %s,\n\nTo use this address for your account, open this link:\n\n%s\n\nThe link expires in %d hours.\n",
+			user.Username, s.appLink("/confirm-email", token), int(emailChangeTTL.Hours())),
+	}); err != nil {
+		return false, err
+	}
+	if err := s.mailer.Send(ctx, Email{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body: fmt.Sprintf("Hello %s,\n\nSomeone asked to change the email address of your account to %s. If it was not you, change your password and contact support.\n",
+			user.Username, newEmail),
+	}); err != nil {
+		log.Printf("Error notifying user %d of an email change: %v", user.ID, err)
+	}
+	return true, nil
+}
+
+// ConfirmEmailChange consumes the link sent by ChangeEmail and switches the address.
+func (s *UserService) ConfirmEmailChange(ctx context.Context, token string) error {
+	if s.tokenRepo == nil {
+		return ErrAccountEmailsUnavailable
+	}
+
+	t, err := s.consumeUserToken(ctx, model.TokenPurposeEmailChange, token)
+	if err != nil {
+		return err
+	}
+	// The address may have been taken since the link was sent.
+	if err := s.checkEmailAvailable(ctx, t.UserID, t.Payload); err != nil {
+		return err
+	}
+	// Opening the link proves control of the new address.
+	err = s.userRepo.UpdateEmail(ctx, t.UserID, t.Payload, true)
+	s.auditUserAction(ctx, t.UserID, t.UserID, model.AuditEmailChange, t.Payload, err)
+	if err != nil {
+		return err
+	}
+	if err := s.tokenRepo.InvalidateAll(ctx, t.UserID, model.TokenPurposeEmailVerification, s.clock.Now()); err != nil {
+		log.Printf("Error invalidating verification links of user %d: %v", t.UserID, err)
+	}
+	return nil
+}
+
+// ChangeUsername renames the account after checking the password.
+func (s *UserService) ChangeUsername(ctx context.Context, req ChangeUsernameInput) error {
+	newUsername := strings.TrimSpace(req.NewUsername)
+	if err := V.Username(newUsername); err != nil {
+		return err
+	}
+
+	user, err := s.reauthenticate(ctx, req.UserID, req.Password, req.ReauthToken, model.AuditUsernameChange)
+	if err != nil {
+		return err
+	}
+	if user.Username == newUsername {
+		return errors.ValidationErrors{{Field: "username", Code: "unchanged"}}
+	}
+	if existing, err := s.userRepo.FindByUsernameOrEmail(ctx, newUsername); err == nil && existing.ID != user.ID {
+		return ErrUsernameTaken
+	}
+
+	err = s.userRepo.UpdateUsername(ctx, user.ID, newUsername)
+	s.auditUserAction(ctx, user.ID, user.ID, model.AuditUsernameChange, user.Username+" -> "+newUsername, err)
+	return err
+}
+
+// reauthenticate confirms the signed-in user knows their password, or just signed in again
+// at a linked identity provider (see StartOIDCReauthentication), before a sensitive change.
+// Failures are audited under action.
+func (s *UserService) reauthenticate(ctx context.Context, userID uint, password, reauthToken, action string) (model.User, error) {
+	if password == "" && reauthToken == "" {
+		return model.User{}, errors.ValidationErrors{{Field: "password", Code: "required"}}
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return model.User{}, stdErrors.New("user not found")
+	}
+	if reauthToken != "" {
+		claims, err := auth.VerifyReauthenticationToken(s.jwtKeys, reauthToken)
+		if err != nil || claims.ID != user.ID {
+			s.auditUserAction(ctx, user.ID, user.ID, action, "", ErrReauthenticationFailed)
+			return model.User{}, ErrReauthenticationFailed
+		}
+		return user, nil
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		s.auditUserAction(ctx, user.ID, user.ID, action, "", ErrIncorrectPassword)
+		return model.User{}, ErrIncorrectPassword
+	}
+	return user, nil
+}
+
+func (s *UserService) checkEmailAvailable(ctx context.Context, userID uint, email string) error {
+	existing, err := s.userRepo.FindByUsernameOrEmail(ctx, email)
+	if err == nil && existing.ID != userID {
+		return ErrEmailTaken
+	}
+	return nil
+}
+
+func withDefaultPreferences(prefs model.UserPreferences) model.UserPreferences {
+	defaults := DefaultPreferences(prefs.UserID)
+	for _, f := range []struct{ dst, def *string }{
+		{&prefs.Timezone, &defaults.Timezone},
+		{&prefs.Currency, &defaults.Currency},
+		{&prefs.Unit, &defaults.Unit},
+		{&prefs.ChartInterval, &defaults.ChartInterval},
+		{&prefs.Theme, &defaults.Theme},
+	} {
+		if *f.dst == "" {
+			*f.dst = *f.def
+		}
+	}
+	return prefs
+}
+
+func normalizeDisplayName(name string) (string, error) {
+	if utf8.RuneCountInString(name) > maxDisplayNameLength {
+		return "", stdErrors.New("display name too long")
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return "", stdErrors.New("display name contains control characters")
+		}
+	}
+	return name, nil
+}
+
+func normalizeTimezone(tz string) (string, error) {
+	// time.LoadLocation also accepts "Local", which means nothing to another machine.
+	if tz == "Local" {
+		return "", stdErrors.New("unknown time zone")
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return "", err
+	}
+	return loc.String(), nil
+}
+
+func normalizeChartInterval(interval string) (string, error) {
+	interval = strings.ToLower(interval)
+	if _, ok := ChartIntervals[interval]; !ok {
+		return "", ErrUnknownChartInterval
+	}
+	return interval, nil
+}
+
+func normalizeTheme(theme string) (string, error) {
+	switch theme = strings.ToLower(theme); theme {
+	case model.ThemeSystem, model.ThemeLight, model.ThemeDark:
+		return theme, nil
+	}
+	return "", stdErrors.New("unknown theme")
+}
@@ -0,0 +1,141 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestPreferencesDefaultAndPartialUpdate(t *testing.T) {
+	repo := newFakePreferencesRepository()
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys, WithPreferences(repo))
+	ctx := context.Background()
+
+	prefs, err := svc.GetPreferences(ctx, 3)
+	if err != nil {
+		t.Fatalf("GetPreferences() error = %v", err)
+	}
+	if prefs != DefaultPreferences(3) {
+		t.Fatalf("GetPreferences() = %+v, want defaults", prefs)
+	}
+
+	name, tz, currency, unit := "  Dana ", "Europe/Paris", "eur", "OZ"
+	prefs, err = svc.UpdatePreferences(ctx, 3, PreferencesUpdate{DisplayName: &name, Timezone: &tz, Currency: &currency, Unit: &unit})
+	if err != nil {
+		t.Fatalf("UpdatePreferences() error = %v", err)
+	}
+	if prefs.DisplayName != "Dana" || prefs.Timezone != "Europe/Paris" || prefs.Currency != "EUR" || prefs.Unit != "oz" || prefs.Theme != model.ThemeSystem {
+		t.Fatalf("UpdatePreferences() = %+v", prefs)
+	}
+
+	// Omitted fields are kept, empty ones go back to the default.
+	theme, reset := "dark", ""
+	prefs, err = svc.UpdatePreferences(ctx, 3, PreferencesUpdate{Theme: &theme, Currency: &reset})
+	if err != nil {
+		t.Fatalf("second UpdatePreferences() error = %v", err)
+	}
+	if prefs.Theme != model.ThemeDark || prefs.Currency != BaseCurrency || prefs.Unit != "oz" || prefs.DisplayName != "Dana" {
+		t.Fatalf("second UpdatePreferences() = %+v", prefs)
+	}
+}
+
+func TestUpdatePreferencesRejectsInvalidValues(t *testing.T) {
+	repo := newFakePreferencesRepository()
+	svc := NewUserService(&fakeUserRepository{}, loginTestKeys, WithPreferences(repo))
+
+	tz, interval, theme := "Mars/Olympus", "2d", "neon"
+	if _, err := svc.UpdatePreferences(context.Background(), 3, PreferencesUpdate{Timezone: &tz, ChartInterval: &interval, Theme: &theme}); err == nil {
+		t.Fatal("expected validation errors")
+	}
+	if len(repo.prefs) != 0 {
+		t.Fatalf("stored %+v after a rejected update", repo.prefs)
+	}
+}
+
+func TestPriceOptionsWithPreferencesSkipsIncompatibleUnit(t *testing.T) {
+	prefs := model.UserPreferences{Currency: "EUR", Unit: "bbl"}
+
+	if got := PriceOptionsWithPreferences(PriceOptions{}, prefs, "brent"); got != (PriceOptions{Currency: "EUR", Unit: "bbl"}) {
+		t.Fatalf("brent options = %+v", got)
+	}
+	if got := PriceOptionsWithPreferences(PriceOptions{}, prefs, "gold"); got != (PriceOptions{Currency: "EUR"}) {
+		t.Fatalf("gold options = %+v, want the preferred currency only", got)
+	}
+	if got := PriceOptionsWithPreferences(PriceOptions{Currency: "GBP", Unit: "t"}, prefs, "brent"); got != (PriceOptions{Currency: "GBP", Unit: "t"}) {
+		t.Fatalf("explicit options = %+v, want them kept", got)
+	}
+}
+
+func TestChangeEmailRequiresConfirmationFromNewAddress(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	f.user.EmailVerified = true
+	ctx := context.Background()
+
+	if _, err := f.svc.ChangeEmail(ctx, ChangeEmailInput{UserID: f.user.ID, Password: "wrong", NewEmail: "carol@new.example.com"}); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("ChangeEmail(wrong password) error = %v, want %v", err, ErrIncorrectPassword)
+	}
+
+	pending, err := f.svc.ChangeEmail(ctx, ChangeEmailInput{UserID: f.user.ID, Password: correctPassword, NewEmail: "carol@new.example.com"})
+	if err != nil || !pending {
+		t.Fatalf("ChangeEmail() = %v, %v, want pending", pending, err)
+	}
+	if f.user.Email != "carol@example.com" {
+		t.Fatalf("email changed to %q before confirmation", f.user.Email)
+	}
+	if len(f.mailer.sent) != 2 || f.mailer.sent[0].To != "carol@new.example.com" || f.mailer.sent[1].To != "carol@example.com" {
+		t.Fatalf("sent = %+v, want a link to the new address and a notice to the old one", f.mailer.sent)
+	}
+	link, err := url.Parse(emailLinkPattern.FindString(f.mailer.sent[0].Body))
+	if err != nil {
+		t.Fatalf("confirmation link: %v", err)
+	}
+
+	if err := f.svc.ConfirmEmailChange(ctx, link.Query().Get("token")); err != nil {
+		t.Fatalf("ConfirmEmailChange() error = %v", err)
+	}
+	if f.user.Email != "carol@new.example.com" || !f.user.EmailVerified {
+		t.Fatalf("user = %+v, want the new verified address", f.user)
+	}
+	if err := f.svc.ConfirmEmailChange(ctx, link.Query().Get("token")); !errors.Is(err, ErrInvalidEmailToken) {
+		t.Fatalf("second ConfirmEmailChange() error = %v, want %v", err, ErrInvalidEmailToken)
+	}
+}
+
+func TestConfirmEmailChangeRejectsAddressTakenMeanwhile(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.svc.ChangeEmail(ctx, ChangeEmailInput{UserID: f.user.ID, Password: correctPassword, NewEmail: "dave@example.com"}); err != nil {
+		t.Fatalf("ChangeEmail() error = %v", err)
+	}
+	link, _ := url.Parse(emailLinkPattern.FindString(f.mailer.sent[0].Body))
+	f.others = append(f.others, model.User{ID: 9, Username: "dave", Email: "dave@example.com"})
+
+	if err := f.svc.ConfirmEmailChange(ctx, link.Query().Get("token")); !errors.Is(err, ErrEmailTaken) {
+		t.Fatalf("ConfirmEmailChange() error = %v, want %v", err, ErrEmailTaken)
+	}
+	if f.user.Email != "carol@example.com" {
+		t.Fatalf("email = %q, want it unchanged", f.user.Email)
+	}
+}
+
+func TestChangeUsername(t *testing.T) {
+	f := newEmailFlowFixture(t)
+	f.others = append(f.others, model.User{ID: 9, Username: "dave", Email: "dave@example.com"})
+	ctx := context.Background()
+
+	if err := f.svc.ChangeUsername(ctx, ChangeUsernameInput{UserID: f.user.ID, Password: correctPassword, NewUsername: "dave"}); !errors.Is(err, ErrUsernameTaken) {
+		t.Fatalf("ChangeUsername(taken) error = %v, want %v", err, ErrUsernameTaken)
+	}
+	if err := f.svc.ChangeUsername(ctx, ChangeUsernameInput{UserID: f.user.ID, Password: "wrong", NewUsername: "caroline"}); !errors.Is(err, ErrIncorrectPassword) {
+		t.Fatalf("ChangeUsername(wrong password) error = %v, want %v", err, ErrIncorrectPassword)
+	}
+	if err := f.svc.ChangeUsername(ctx, ChangeUsernameInput{UserID: f.user.ID, Password: correctPassword, NewUsername: "caroline"}); err != nil {
+		t.Fatalf("ChangeUsername() error = %v", err)
+	}
+	if f.user.Username != "caroline" {
+		t.Fatalf("username = %q, want caroline", f.user.Username)
+	}
+}
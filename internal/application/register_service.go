@@ -5,7 +5,6 @@ import (
 	"backend/internal/errors"
 	"backend/utils"
 	"context"
-	stdErrors "errors"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -33,11 +32,11 @@ func (s *UserService) Register(ctx context.Context, req RegisterInput) error {
 
 	existing, err := s.userRepo.FindByUsernameOrEmail(ctx, req.Username)
 	if err == nil && existing.ID != 0 {
-		return stdErrors.New("username already taken")
+		return ErrUsernameTaken
 	}
 	existing, err = s.userRepo.FindByUsernameOrEmail(ctx, req.Email)
 	if err == nil && existing.ID != 0 {
-		return stdErrors.New("an account with this email already exists")
+		return ErrEmailTaken
 	}
 
 	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
@@ -48,11 +47,13 @@ func (s *UserService) Register(ctx context.Context, req RegisterInput) error {
 		Username: req.Username,
 		Email:    req.Email,
 		Password: string(hashed),
-		Role:     "user",
+		Role:     model.RoleUser,
 	}
 
 	if err := s.userRepo.Save(ctx, user); err != nil {
 		return err
 	}
+
+	s.sendVerificationAfterRegister(ctx, user.Email)
 	return nil
 }
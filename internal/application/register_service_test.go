@@ -1,6 +1,7 @@
 package application
 
 import (
+	"backend/internal/auth"
 	"backend/internal/domain/model"
 	validationErrors "backend/internal/errors"
 	"context"
@@ -20,7 +21,7 @@ const (
 
 func TestRegisterReturnsValidationErrors(t *testing.T) {
 	repo := &fakeUserRepository{}
-	svc := NewUserService(repo, "register-test-secret")
+	svc := NewUserService(repo, auth.NewHMACKeySet([]byte("register-test-secret")))
 
 	err := svc.Register(context.Background(), RegisterInput{
 		Username:  "ab",
@@ -47,7 +48,7 @@ func TestRegisterReturnsUsernameAlreadyTaken(t *testing.T) {
 			return model.User{}, stdErrors.New(notFoundErr)
 		},
 	}
-	svc := NewUserService(repo, "register-test-secret")
+	svc := NewUserService(repo, auth.NewHMACKeySet([]byte("register-test-secret")))
 
 	err := svc.Register(context.Background(), RegisterInput{
 		Username:  usernameAlice,
@@ -69,7 +70,7 @@ func TestRegisterReturnsEmailAlreadyExists(t *testing.T) {
 			return model.User{}, stdErrors.New(notFoundErr)
 		},
 	}
-	svc := NewUserService(repo, "register-test-secret")
+	svc := NewUserService(repo, auth.NewHMACKeySet([]byte("register-test-secret")))
 
 	err := svc.Register(context.Background(), RegisterInput{
 		Username:  usernameAlice,
@@ -88,7 +89,7 @@ func TestRegisterHashesPasswordBeforeSave(t *testing.T) {
 			return model.User{}, stdErrors.New(notFoundErr)
 		},
 	}
-	svc := NewUserService(repo, "register-test-secret")
+	svc := NewUserService(repo, auth.NewHMACKeySet([]byte("register-test-secret")))
 
 	plain := strongPassword
 	err := svc.Register(context.Background(), RegisterInput{
@@ -0,0 +1,93 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,49}$`)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrInvalidRoleName   = errors.New("role names are 2-50 lowercase letters, digits, '-' or '_'")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrBuiltInRole       = errors.New("built-in roles cannot be deleted")
+	ErrAdminRoleLocked   = errors.New("the admin role always has every permission")
+	ErrRoleInUse         = errors.New("role is still assigned to users")
+)
+
+// RoleService manages which permissions each role grants.
+type RoleService struct {
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, userRepo repository.UserRepository) *RoleService {
+	return &RoleService{roleRepo: roleRepo, userRepo: userRepo}
+}
+
+// ListPermissions returns every permission that can be granted.
+func (s *RoleService) ListPermissions() []string {
+	return append([]string(nil), model.AllPermissions...)
+}
+
+func (s *RoleService) ListRoles(ctx context.Context) ([]model.Role, error) {
+	return s.roleRepo.List(ctx)
+}
+
+// SaveRole creates a role or replaces its permissions. Users holding the role get the new
+// permissions with their next access token.
+func (s *RoleService) SaveRole(ctx context.Context, name string, permissions []string) (model.Role, error) {
+	if !roleNamePattern.MatchString(name) {
+		return model.Role{}, ErrInvalidRoleName
+	}
+	if name == model.RoleAdmin {
+		// Editing it could lock every admin out of the role API.
+		return model.Role{}, ErrAdminRoleLocked
+	}
+
+	perms := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		if !model.IsPermission(p) {
+			return model.Role{}, fmt.Errorf("%w: %s", ErrUnknownPermission, p)
+		}
+		if !slices.Contains(perms, p) {
+			perms = append(perms, p)
+		}
+	}
+	slices.Sort(perms)
+
+	if err := s.roleRepo.Save(ctx, model.Role{Name: name, Permissions: perms}); err != nil {
+		return model.Role{}, err
+	}
+	return s.roleRepo.Get(ctx, name)
+}
+
+// DeleteRole removes a custom role that no user holds any more.
+func (s *RoleService) DeleteRole(ctx context.Context, name string) error {
+	role, err := s.roleRepo.Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	if role.BuiltIn {
+		return ErrBuiltInRole
+	}
+
+	_, holders, err := s.userRepo.List(ctx, repository.UserFilter{Role: name, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if holders > 0 {
+		return ErrRoleInUse
+	}
+	return s.roleRepo.Delete(ctx, name)
+}
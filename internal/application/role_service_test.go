@@ -0,0 +1,95 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestSaveRoleValidatesAndNormalisesPermissions(t *testing.T) {
+	roles := newFakeRoleRepository()
+	svc := NewRoleService(roles, &fakeUserRepository{})
+	ctx := context.Background()
+
+	role, err := svc.SaveRole(ctx, "analyst", []string{model.PermCorrelationRecompute, model.PermCommodityRead, model.PermCommodityRead})
+	if err != nil {
+		t.Fatalf("SaveRole() error = %v", err)
+	}
+	want := []string{model.PermCommodityRead, model.PermCorrelationRecompute}
+	if !slices.Equal(role.Permissions, want) {
+		t.Fatalf("Permissions = %v, want %v", role.Permissions, want)
+	}
+
+	if _, err := svc.SaveRole(ctx, "analyst", []string{"commodity:write"}); !errors.Is(err, ErrUnknownPermission) {
+		t.Fatalf("SaveRole(unknown permission) error = %v, want %v", err, ErrUnknownPermission)
+	}
+	if _, err := svc.SaveRole(ctx, "Bad Name", nil); !errors.Is(err, ErrInvalidRoleName) {
+		t.Fatalf("SaveRole(bad name) error = %v, want %v", err, ErrInvalidRoleName)
+	}
+	if _, err := svc.SaveRole(ctx, model.RoleAdmin, nil); !errors.Is(err, ErrAdminRoleLocked) {
+		t.Fatalf("SaveRole(admin) error = %v, want %v", err, ErrAdminRoleLocked)
+	}
+}
+
+func TestDeleteRoleRefusesBuiltInAndAssignedRoles(t *testing.T) {
+	roles := newFakeRoleRepository()
+	roles.roles["analyst"] = model.Role{Name: "analyst"}
+	holders := 1
+	users := &fakeUserRepository{
+		listFn: func(filter repository.UserFilter) ([]model.User, int, error) {
+			if filter.Role != "analyst" {
+				t.Fatalf("List() role filter = %q, want analyst", filter.Role)
+			}
+			return nil, holders, nil
+		},
+	}
+	svc := NewRoleService(roles, users)
+	ctx := context.Background()
+
+	if err := svc.DeleteRole(ctx, model.RoleUser); !errors.Is(err, ErrBuiltInRole) {
+		t.Fatalf("DeleteRole(user) error = %v, want %v", err, ErrBuiltInRole)
+	}
+	if err := svc.DeleteRole(ctx, "analyst"); !errors.Is(err, ErrRoleInUse) {
+		t.Fatalf("DeleteRole(assigned) error = %v, want %v", err, ErrRoleInUse)
+	}
+	holders = 0
+	if err := svc.DeleteRole(ctx, "analyst"); err != nil {
+		t.Fatalf("DeleteRole() error = %v", err)
+	}
+	if err := svc.DeleteRole(ctx, "analyst"); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("DeleteRole(missing) error = %v, want %v", err, ErrRoleNotFound)
+	}
+}
+
+func TestLoginEmbedsRolePermissions(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) {
+			return model.User{ID: 6, Username: "erin", Password: string(hash), Role: "analyst"}, nil
+		},
+	}
+	roles := newFakeRoleRepository()
+	roles.roles["analyst"] = model.Role{Name: "analyst", Permissions: []string{model.PermCorrelationRead}}
+	svc := NewUserService(repo, loginTestKeys, WithRoles(roles))
+
+	_, result, err := svc.Login(context.Background(), LoginInput{Identifier: "erin", Password: correctPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	claims, err := auth.VerifyJWTToken(loginTestKeys, result.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyJWTToken() error = %v", err)
+	}
+	if !slices.Equal(claims.Permissions, []string{model.PermCorrelationRead}) {
+		t.Fatalf("claims.Permissions = %v, want [%s]", claims.Permissions, model.PermCorrelationRead)
+	}
+}
@@ -3,6 +3,7 @@ package application
 import (
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
+	"backend/internal/domain/units"
 	"context"
 	"log"
 	"math/rand"
@@ -28,6 +29,7 @@ func RunCommoditySeeder(ctx context.Context, repo repository.CommodityRepository
 		"brent":    0.65,
 	}
 
+	batch := make([]model.Commodity, 0, daysToSeed*len(prices))
 	for i := 0; i < daysToSeed; i++ {
 		currentDate := startDate.AddDate(0, 0, i)
 
@@ -42,18 +44,24 @@ func RunCommoditySeeder(ctx context.Context, repo repository.CommodityRepository
 		prices["brent"] = prices["brent"] * trendB * (1.0 + (rand.Float64()*0.02 - 0.01))
 
 		for name, price := range prices {
-			err := repo.Save(ctx, model.Commodity{
-				Name:      name,
-				Date:      currentDate,
-				PriceKg:   price,
-				Unit:      "USD/kg",
-				FetchedAt: time.Now(),
+			nativeUnit := units.Native(name)
+			nativePrice, _ := units.FromPerKg(price, name, nativeUnit)
+			batch = append(batch, model.Commodity{
+				Name:        name,
+				Date:        currentDate,
+				PriceKg:     price,
+				Unit:        "USD/kg",
+				FetchedAt:   time.Now(),
+				NativePrice: nativePrice,
+				NativeUnit:  string(nativeUnit),
 			})
-			if err != nil {
-				log.Printf("Seeder failed to save %s: %v", name, err)
-			}
 		}
 	}
 
+	if err := repo.SaveBatch(ctx, batch); err != nil {
+		log.Printf("Seeder failed to save %d prices: %v", len(batch), err)
+		return
+	}
+
 	log.Println("Successfully seeded 365 days of historical data.")
 }
@@ -0,0 +1,399 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionCacheTTL bounds how long a revocation done by another instance can go unnoticed.
+	sessionCacheTTL = 30 * time.Second
+	// sessionTouchInterval throttles last-seen writes for busy sessions.
+	sessionTouchInterval = time.Minute
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected, session revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionsDisabled    = errors.New("session management is not enabled")
+)
+
+// RefreshToken exchanges a refresh token for a new access token and a new refresh token.
+// The presented token is single-use: presenting it again revokes its whole session, since
+// that means either the client or an attacker holds a stolen copy.
+func (s *UserService) RefreshToken(ctx context.Context, refreshToken, ip string) (AuthResult, error) {
+	if s.sessionRepo == nil || refreshToken == "" {
+		return AuthResult{}, ErrInvalidRefreshToken
+	}
+
+	token, err := s.sessionRepo.FindRefreshToken(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuthResult{}, ErrInvalidRefreshToken
+		}
+		return AuthResult{}, err
+	}
+	session, err := s.sessionRepo.FindByID(ctx, token.SessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuthResult{}, ErrInvalidRefreshToken
+		}
+		return AuthResult{}, err
+	}
+
+	now := time.Now()
+	if session.RevokedAt != nil || now.After(token.ExpiresAt) {
+		return AuthResult{}, ErrInvalidRefreshToken
+	}
+	if token.RotatedAt != nil {
+		return AuthResult{}, s.revokeReusedSession(ctx, session)
+	}
+
+	if err := s.sessionRepo.MarkRefreshTokenRotated(ctx, token.ID, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Lost a race against another refresh with the same token.
+			return AuthResult{}, s.revokeReusedSession(ctx, session)
+		}
+		return AuthResult{}, err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		return AuthResult{}, ErrInvalidRefreshToken
+	}
+	if user.Disabled {
+		if err := s.revokeSession(ctx, session.ID); err != nil {
+			log.Printf("Error revoking session %s of disabled user %d: %v", session.ID, user.ID, err)
+		}
+		return AuthResult{}, ErrInvalidRefreshToken
+	}
+
+	result, err := s.issueTokens(ctx, user, session.ID)
+	if err != nil {
+		return AuthResult{}, err
+	}
+	if err := s.sessionRepo.Touch(ctx, session.ID, now, ip, result.RefreshExpiresAt); err != nil {
+		log.Printf("Error updating session %s activity: %v", session.ID, err)
+	}
+	return result, nil
+}
+
+// Logout revokes the session the refresh token belongs to. Unknown tokens are ignored.
+func (s *UserService) Logout(ctx context.Context, refreshToken string) error {
+	if s.sessionRepo == nil || refreshToken == "" {
+		return nil
+	}
+
+	token, err := s.sessionRepo.FindRefreshToken(ctx, auth.HashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	session, err := s.sessionRepo.FindByID(ctx, token.SessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	if err := s.revokeSession(ctx, session.ID); err != nil {
+		return err
+	}
+	s.auditUserAction(ctx, session.UserID, session.UserID, model.AuditLogout, "", nil)
+	return nil
+}
+
+// ListSessions returns the active sessions of a user, flagging currentID as the caller's own.
+func (s *UserService) ListSessions(ctx context.Context, userID uint, currentID string) ([]model.Session, error) {
+	if s.sessionRepo == nil {
+		return nil, ErrSessionsDisabled
+	}
+
+	sessions, err := s.sessionRepo.ListActive(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs one of the user's sessions out. Sessions of other users are reported
+// as not found.
+func (s *UserService) RevokeSession(ctx context.Context, userID uint, sessionID string) error {
+	if s.sessionRepo == nil {
+		return ErrSessionsDisabled
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+	if err := s.revokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+	s.auditUserAction(ctx, userID, userID, model.AuditSessionRevoke, "session "+sessionID, nil)
+	return nil
+}
+
+// RevokeOtherSessions signs the user out everywhere except currentID and returns how many
+// sessions were revoked.
+func (s *UserService) RevokeOtherSessions(ctx context.Context, userID uint, currentID string) (int, error) {
+	if s.sessionRepo == nil {
+		return 0, ErrSessionsDisabled
+	}
+
+	ids, err := s.sessionRepo.RevokeAllExcept(ctx, userID, currentID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range ids {
+		s.sessionCache.set(id, false)
+	}
+	s.auditUserAction(ctx, userID, userID, model.AuditSessionRevoke, fmt.Sprintf("%d other sessions", len(ids)), nil)
+	return len(ids), nil
+}
+
+// IsSessionActive reports whether access tokens of the session may still be used. Answers
+// are cached for a short while so authenticated requests rarely hit the database.
+func (s *UserService) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	if s.sessionRepo == nil {
+		return true, nil
+	}
+	if active, ok := s.sessionCache.get(sessionID); ok {
+		return active, nil
+	}
+
+	session, err := s.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			s.sessionCache.set(sessionID, false)
+			return false, nil
+		}
+		return false, err
+	}
+
+	now := time.Now()
+	active := session.RevokedAt == nil && now.Before(session.ExpiresAt)
+	s.sessionCache.set(sessionID, active)
+
+	if active && now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		if err := s.sessionRepo.Touch(ctx, session.ID, now, session.IP, session.ExpiresAt); err != nil {
+			log.Printf("Error updating session %s activity: %v", session.ID, err)
+		}
+	}
+	return active, nil
+}
+
+// startSession opens a new session for a freshly authenticated user.
+func (s *UserService) startSession(ctx context.Context, user model.User, ip, userAgent string) (AuthResult, error) {
+	if s.sessionRepo == nil {
+		perms, err := s.permissionsFor(ctx, user.Role)
+		if err != nil {
+			return AuthResult{}, err
+		}
+		token, err := auth.GenerateSessionJWTToken(s.jwtKeys, user.ID, user.Username, user.Role, perms, "")
+		return AuthResult{AccessToken: token}, err
+	}
+
+	id, err := auth.NewSessionID()
+	if err != nil {
+		return AuthResult{}, err
+	}
+	now := time.Now()
+	session := model.Session{
+		ID:         id,
+		UserID:     user.ID,
+		Device:     describeDevice(userAgent),
+		IP:         ip,
+		UserAgent:  userAgent,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.refreshTTL),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return AuthResult{}, fmt.Errorf("create session: %w", err)
+	}
+
+	return s.issueTokens(ctx, user, id)
+}
+
+// issueTokens stores a new refresh token for the session and signs an access token bound to it.
+func (s *UserService) issueTokens(ctx context.Context, user model.User, sessionID string) (AuthResult, error) {
+	id, err := auth.NewSessionID()
+	if err != nil {
+		return AuthResult{}, err
+	}
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return AuthResult{}, err
+	}
+
+	now := time.Now()
+	token := model.RefreshToken{
+		ID:        id,
+		SessionID: sessionID,
+		TokenHash: hash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+	}
+	if err := s.sessionRepo.CreateRefreshToken(ctx, token); err != nil {
+		return AuthResult{}, fmt.Errorf("create refresh token: %w", err)
+	}
+
+	perms, err := s.permissionsFor(ctx, user.Role)
+	if err != nil {
+		return AuthResult{}, err
+	}
+	accessToken, err := auth.GenerateSessionJWTToken(s.jwtKeys, user.ID, user.Username, user.Role, perms, sessionID)
+	if err != nil {
+		return AuthResult{}, err
+	}
+
+	return AuthResult{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// permissionsFor returns the permissions to embed in access tokens for role. Unknown roles
+// get none.
+func (s *UserService) permissionsFor(ctx context.Context, role string) ([]string, error) {
+	if s.roleRepo == nil {
+		for _, r := range model.DefaultRoles() {
+			if r.Name == role {
+				return r.Permissions, nil
+			}
+		}
+		return nil, nil
+	}
+
+	r, err := s.roleRepo.Get(ctx, role)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("User role %q does not exist, issuing token without permissions", role)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("load role %s: %w", role, err)
+	}
+	return r.Permissions, nil
+}
+
+func (s *UserService) revokeSession(ctx context.Context, sessionID string) error {
+	if err := s.sessionRepo.Revoke(ctx, sessionID, time.Now()); err != nil {
+		return err
+	}
+	s.sessionCache.set(sessionID, false)
+	return nil
+}
+
+func (s *UserService) revokeReusedSession(ctx context.Context, session model.Session) error {
+	log.Printf("Refresh token reuse detected for user %d, revoking session %s", session.UserID, session.ID)
+	if err := s.revokeSession(ctx, session.ID); err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	s.auditUserAction(ctx, session.UserID, session.UserID, model.AuditRefreshTokenReuse, "session "+session.ID+" revoked", ErrRefreshTokenReused)
+	return ErrRefreshTokenReused
+}
+
+// describeDevice turns a user agent into a short label such as "Firefox on Linux".
+func describeDevice(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return "Unknown device"
+	}
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(ua, "edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "opr/") || strings.Contains(ua, "opera"):
+		browser = "Opera"
+	case strings.Contains(ua, "firefox/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "chrome/") || strings.Contains(ua, "crios/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "safari/"):
+		browser = "Safari"
+	case strings.Contains(ua, "curl/"):
+		browser = "curl"
+	}
+
+	os := ""
+	switch {
+	case strings.Contains(ua, "android"):
+		os = "Android"
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipad"):
+		os = "iOS"
+	case strings.Contains(ua, "windows"):
+		os = "Windows"
+	case strings.Contains(ua, "mac os"):
+		os = "macOS"
+	case strings.Contains(ua, "linux"):
+		os = "Linux"
+	}
+
+	if os == "" {
+		return browser
+	}
+	return browser + " on " + os
+}
+
+type sessionCacheEntry struct {
+	active    bool
+	checkedAt time.Time
+}
+
+// sessionCache remembers recent session and account status checks. Changes made through
+// this process update it immediately; others are picked up once the entry expires.
+type sessionCache struct {
+	mu      sync.Mutex
+	entries map[string]sessionCacheEntry
+}
+
+func (c *sessionCache) get(id string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[id]
+	if !ok || time.Since(e.checkedAt) > sessionCacheTTL {
+		return false, false
+	}
+	return e.active, true
+}
+
+func (c *sessionCache) set(id string, active bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]sessionCacheEntry)
+	}
+	now := time.Now()
+	// Drop stale entries on the way so the map stays bounded by recent traffic.
+	for k, e := range c.entries {
+		if now.Sub(e.checkedAt) > sessionCacheTTL {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[id] = sessionCacheEntry{active: active, checkedAt: now}
+}
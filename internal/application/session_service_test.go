@@ -0,0 +1,192 @@
+package application
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newSessionTestService(t *testing.T) (*UserService, *fakeSessionRepository) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	user := model.User{ID: 7, Username: "alice", Password: string(hash), Role: "user"}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) { return user, nil },
+		findByIDFn:              func(id uint) (model.User, error) { return user, nil },
+	}
+	sessions := newFakeSessionRepository()
+	return NewUserService(repo, loginTestKeys, WithSessionRepository(sessions), WithRefreshTokenTTL(time.Hour)), sessions
+}
+
+func login(t *testing.T, svc *UserService) AuthResult {
+	t.Helper()
+	_, tokens, err := svc.Login(context.Background(), LoginInput{
+		Identifier: "alice",
+		Password:   correctPassword,
+		IP:         "203.0.113.7",
+		UserAgent:  "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	return tokens
+}
+
+func TestLoginIssuesRefreshToken(t *testing.T) {
+	svc, sessions := newSessionTestService(t)
+
+	tokens := login(t, svc)
+	if tokens.RefreshToken == "" {
+		t.Fatal("expected refresh token")
+	}
+	if len(sessions.sessions) != 1 {
+		t.Fatalf("sessions = %d, want 1", len(sessions.sessions))
+	}
+	for _, s := range sessions.sessions {
+		if s.Device != "Firefox on Linux" || s.IP != "203.0.113.7" {
+			t.Fatalf("session client = %q from %q", s.Device, s.IP)
+		}
+	}
+	for _, tok := range sessions.tokens {
+		if tok.TokenHash == tokens.RefreshToken {
+			t.Fatal("refresh token stored in clear")
+		}
+	}
+}
+
+func TestRefreshTokenRotates(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	first := login(t, svc)
+
+	second, err := svc.RefreshToken(context.Background(), first.RefreshToken, "")
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.AccessToken == "" {
+		t.Fatalf("expected new tokens, got %+v", second)
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), second.RefreshToken, ""); err != nil {
+		t.Fatalf("RefreshToken() with rotated token error = %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	svc, sessions := newSessionTestService(t)
+	first := login(t, svc)
+
+	second, err := svc.RefreshToken(context.Background(), first.RefreshToken, "")
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), first.RefreshToken, ""); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reuse error = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	if _, err := svc.RefreshToken(context.Background(), second.RefreshToken, ""); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("descendant token error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+	if active, _ := svc.IsSessionActive(context.Background(), onlySessionID(t, sessions)); active {
+		t.Fatal("session should be revoked after token reuse")
+	}
+}
+
+func TestRefreshTokenRejectsExpiredToken(t *testing.T) {
+	svc, sessions := newSessionTestService(t)
+	tokens := login(t, svc)
+
+	for _, tok := range sessions.tokens {
+		tok.ExpiresAt = time.Now().Add(-time.Minute)
+	}
+
+	if _, err := svc.RefreshToken(context.Background(), tokens.RefreshToken, ""); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func TestLogoutRevokesRefreshToken(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	tokens := login(t, svc)
+
+	if err := svc.Logout(context.Background(), tokens.RefreshToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := svc.RefreshToken(context.Background(), tokens.RefreshToken, ""); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidRefreshToken)
+	}
+}
+
+func onlySessionID(t *testing.T, sessions *fakeSessionRepository) string {
+	t.Helper()
+	if len(sessions.sessions) != 1 {
+		t.Fatalf("sessions = %d, want 1", len(sessions.sessions))
+	}
+	for id := range sessions.sessions {
+		return id
+	}
+	return ""
+}
+
+func TestRevokeOtherSessionsKeepsCurrent(t *testing.T) {
+	svc, _ := newSessionTestService(t)
+	login(t, svc)
+	login(t, svc)
+
+	list, err := svc.ListSessions(context.Background(), 7, "")
+	if err != nil || len(list) != 2 {
+		t.Fatalf("ListSessions() = %d sessions, err %v", len(list), err)
+	}
+	current := list[0].ID
+
+	revoked, err := svc.RevokeOtherSessions(context.Background(), 7, current)
+	if err != nil || revoked != 1 {
+		t.Fatalf("RevokeOtherSessions() = %d, %v", revoked, err)
+	}
+
+	list, err = svc.ListSessions(context.Background(), 7, current)
+	if err != nil || len(list) != 1 || list[0].ID != current || !list[0].Current {
+		t.Fatalf("remaining sessions = %+v, err %v", list, err)
+	}
+	if active, _ := svc.IsSessionActive(context.Background(), list[0].ID); !active {
+		t.Fatal("current session should stay active")
+	}
+}
+
+func TestRevokeSessionOfAnotherUserIsNotFound(t *testing.T) {
+	svc, sessions := newSessionTestService(t)
+	login(t, svc)
+
+	if err := svc.RevokeSession(context.Background(), 99, onlySessionID(t, sessions)); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("error = %v, want %v", err, ErrSessionNotFound)
+	}
+}
+
+func TestIsSessionActiveCachesLookups(t *testing.T) {
+	svc, sessions := newSessionTestService(t)
+	login(t, svc)
+	id := onlySessionID(t, sessions)
+
+	for i := 0; i < 3; i++ {
+		if active, err := svc.IsSessionActive(context.Background(), id); err != nil || !active {
+			t.Fatalf("IsSessionActive() = %v, %v", active, err)
+		}
+	}
+	if sessions.lookups != 1 {
+		t.Fatalf("repository lookups = %d, want 1", sessions.lookups)
+	}
+
+	if err := svc.RevokeSession(context.Background(), 7, id); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if active, _ := svc.IsSessionActive(context.Background(), id); active {
+		t.Fatal("revoked session should be inactive immediately")
+	}
+}
@@ -0,0 +1,235 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// twoFactorChallengeTTL is how long the user has to enter a code after their password.
+	twoFactorChallengeTTL = 5 * time.Minute
+	recoveryCodeCount     = 10
+)
+
+var (
+	ErrTwoFactorUnavailable    = errors.New("two-factor authentication is not enabled on this server")
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("start two-factor enrolment first")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrInvalidChallenge        = errors.New("invalid or expired two-factor challenge")
+)
+
+// TwoFactorEnrollment is what an authenticator app needs to start generating codes.
+type TwoFactorEnrollment struct {
+	Secret string
+	URI    string
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string
+	// Code is either a current TOTP code or an unused recovery code.
+	Code      string
+	IP        string
+	UserAgent string
+}
+
+// EnrollTwoFactor generates a new TOTP secret for the user. It stays inactive until
+// ConfirmTwoFactor receives a code generated from it.
+func (s *UserService) EnrollTwoFactor(ctx context.Context, userID uint) (TwoFactorEnrollment, error) {
+	if s.twoFactorRepo == nil {
+		return TwoFactorEnrollment{}, ErrTwoFactorUnavailable
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return TwoFactorEnrollment{}, errors.New("user not found")
+	}
+
+	current, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return TwoFactorEnrollment{}, err
+	}
+	if err == nil && current.Enabled {
+		return TwoFactorEnrollment{}, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+	if err := s.twoFactorRepo.SavePending(ctx, userID, secret, time.Now()); err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	return TwoFactorEnrollment{
+		Secret: secret,
+		URI:    auth.TOTPURI(s.totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTwoFactor activates a pending enrolment and returns fresh recovery codes. The codes
+// are only stored hashed, so this is the one time they can be shown to the user.
+func (s *UserService) ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error) {
+	if s.twoFactorRepo == nil {
+		return nil, ErrTwoFactorUnavailable
+	}
+
+	tf, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrTwoFactorNotEnrolled
+		}
+		return nil, err
+	}
+	if tf.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	now := time.Now()
+	step, ok := auth.ValidateTOTP(tf.Secret, code, now)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = auth.NewRecoveryCode(); err != nil {
+			return nil, err
+		}
+		hashes[i] = auth.HashRecoveryCode(codes[i])
+	}
+
+	if err := s.twoFactorRepo.Enable(ctx, userID, step, hashes, now); err != nil {
+		return nil, fmt.Errorf("enable two-factor: %w", err)
+	}
+	s.auditUserAction(ctx, userID, userID, model.AuditTwoFactorEnable, "", nil)
+	return codes, nil
+}
+
+// CompleteTwoFactorLogin finishes a login that Login answered with a challenge token.
+func (s *UserService) CompleteTwoFactorLogin(ctx context.Context, req TwoFactorLoginInput) (model.User, AuthResult, error) {
+	if s.twoFactorRepo == nil {
+		return model.User{}, AuthResult{}, ErrTwoFactorUnavailable
+	}
+
+	claims, err := auth.VerifyChallengeToken(s.jwtKeys, req.ChallengeToken)
+	if err != nil {
+		return model.User{}, AuthResult{}, ErrInvalidChallenge
+	}
+
+	user, err := s.userRepo.FindByID(ctx, claims.ID)
+	if err != nil {
+		return model.User{}, AuthResult{}, ErrInvalidChallenge
+	}
+	if err := checkAccountStatus(user); err != nil {
+		return model.User{}, AuthResult{}, err
+	}
+
+	tf, err := s.twoFactorRepo.Get(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.User{}, AuthResult{}, ErrInvalidChallenge
+		}
+		return model.User{}, AuthResult{}, err
+	}
+	if !tf.Enabled {
+		return model.User{}, AuthResult{}, ErrInvalidChallenge
+	}
+
+	attempt := model.LoginAttempt{
+		AccountKey: loginAccountKey(&user, ""),
+		Identifier: user.Username,
+		UserID:     &user.ID,
+		IP:         req.IP,
+		UserAgent:  req.UserAgent,
+	}
+	if err := s.checkAccountThrottle(ctx, attempt.AccountKey); err != nil {
+		attempt.Outcome = throttleOutcome(err)
+		s.recordAttempt(ctx, attempt)
+		return model.User{}, AuthResult{}, err
+	}
+
+	if err := s.checkSecondFactor(ctx, tf, req.Code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			attempt.Outcome = model.LoginOutcomeInvalidTwoFactor
+			s.recordAttempt(ctx, attempt)
+		}
+		return model.User{}, AuthResult{}, err
+	}
+
+	result, err := s.startSession(ctx, user, req.IP, req.UserAgent)
+	if err != nil {
+		return model.User{}, AuthResult{}, err
+	}
+
+	attempt.Outcome = model.LoginOutcomeSuccess
+	s.recordAttempt(ctx, attempt)
+	return user, result, nil
+}
+
+// ResetTwoFactor removes a user's enrolment and recovery codes, e.g. after they lost their
+// device. Their next login only asks for the password.
+func (s *UserService) ResetTwoFactor(ctx context.Context, actorID, userID uint) (err error) {
+	defer func() { s.auditUserAction(ctx, actorID, userID, model.AuditTwoFactorReset, "", err) }()
+
+	if s.twoFactorRepo == nil {
+		return ErrTwoFactorUnavailable
+	}
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return errors.New("user not found")
+	}
+	return s.twoFactorRepo.Delete(ctx, userID)
+}
+
+// twoFactorChallenge returns a challenge token when the user has two-factor enabled, or
+// an empty string when the password alone is enough.
+func (s *UserService) twoFactorChallenge(ctx context.Context, user model.User) (string, error) {
+	if s.twoFactorRepo == nil {
+		return "", nil
+	}
+
+	tf, err := s.twoFactorRepo.Get(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !tf.Enabled {
+		return "", nil
+	}
+	return auth.GenerateChallengeToken(s.jwtKeys, user.ID, twoFactorChallengeTTL)
+}
+
+// checkSecondFactor accepts a TOTP code that was not used before, or an unused recovery code.
+func (s *UserService) checkSecondFactor(ctx context.Context, tf model.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if code == "" {
+		return ErrInvalidTwoFactorCode
+	}
+
+	if step, ok := auth.ValidateTOTP(tf.Secret, code, time.Now()); ok {
+		if err := s.twoFactorRepo.UseStep(ctx, tf.UserID, step); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvalidTwoFactorCode
+			}
+			return err
+		}
+		return nil
+	}
+
+	if err := s.twoFactorRepo.UseRecoveryCode(ctx, tf.UserID, auth.HashRecoveryCode(code), time.Now()); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidTwoFactorCode
+		}
+		return err
+	}
+	return nil
+}
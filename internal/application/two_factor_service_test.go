@@ -0,0 +1,132 @@
+package application
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newTwoFactorTestService(t *testing.T) (*UserService, *fakeTwoFactorRepository) {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(correctPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("failed to hash test password: %v", err)
+	}
+	user := model.User{ID: 3, Username: "alice", Email: "alice@example.com", Password: string(hash), Role: "user"}
+	repo := &fakeUserRepository{
+		findByUsernameOrEmailFn: func(identifier string) (model.User, error) { return user, nil },
+		findByIDFn:              func(id uint) (model.User, error) { return user, nil },
+	}
+	tfRepo := newFakeTwoFactorRepository()
+	svc := NewUserService(repo, loginTestKeys,
+		WithSessionRepository(newFakeSessionRepository()),
+		WithTwoFactor(tfRepo, "PrimeTrading"),
+	)
+	return svc, tfRepo
+}
+
+// enableTwoFactor enrols user 3 and returns the secret and recovery codes.
+func enableTwoFactor(t *testing.T, svc *UserService) (string, []string) {
+	t.Helper()
+
+	enrollment, err := svc.EnrollTwoFactor(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("EnrollTwoFactor() error = %v", err)
+	}
+	if !strings.HasPrefix(enrollment.URI, "otpauth://totp/PrimeTrading:alice@example.com?") {
+		t.Fatalf("URI = %q", enrollment.URI)
+	}
+
+	code, _ := auth.TOTPCode(enrollment.Secret, auth.TOTPStep(time.Now())-1)
+	codes, err := svc.ConfirmTwoFactor(context.Background(), 3, code)
+	if err != nil {
+		t.Fatalf("ConfirmTwoFactor() error = %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("recovery codes = %d, want %d", len(codes), recoveryCodeCount)
+	}
+	return enrollment.Secret, codes
+}
+
+func TestConfirmTwoFactorRejectsWrongCode(t *testing.T) {
+	svc, tfRepo := newTwoFactorTestService(t)
+
+	if _, err := svc.EnrollTwoFactor(context.Background(), 3); err != nil {
+		t.Fatalf("EnrollTwoFactor() error = %v", err)
+	}
+	if _, err := svc.ConfirmTwoFactor(context.Background(), 3, "000000x"); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("error = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+	if tfRepo.enrolments[3].Enabled {
+		t.Fatal("enrolment should stay pending")
+	}
+}
+
+func TestLoginWithTwoFactorRequiresChallenge(t *testing.T) {
+	svc, _ := newTwoFactorTestService(t)
+	secret, _ := enableTwoFactor(t, svc)
+
+	_, result, err := svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: correctPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if result.TwoFactorChallenge == "" || result.AccessToken != "" || result.RefreshToken != "" {
+		t.Fatalf("expected only a challenge, got %+v", result)
+	}
+
+	code, _ := auth.TOTPCode(secret, auth.TOTPStep(time.Now()))
+	input := TwoFactorLoginInput{ChallengeToken: result.TwoFactorChallenge, Code: code}
+	_, tokens, err := svc.CompleteTwoFactorLogin(context.Background(), input)
+	if err != nil {
+		t.Fatalf("CompleteTwoFactorLogin() error = %v", err)
+	}
+	if tokens.AccessToken == "" || tokens.RefreshToken == "" {
+		t.Fatalf("expected tokens, got %+v", tokens)
+	}
+
+	if _, _, err := svc.CompleteTwoFactorLogin(context.Background(), input); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("replayed code error = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+}
+
+func TestRecoveryCodeIsSingleUse(t *testing.T) {
+	svc, _ := newTwoFactorTestService(t)
+	_, codes := enableTwoFactor(t, svc)
+
+	_, result, err := svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: correctPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	input := TwoFactorLoginInput{ChallengeToken: result.TwoFactorChallenge, Code: strings.ToUpper(codes[0])}
+	if _, _, err := svc.CompleteTwoFactorLogin(context.Background(), input); err != nil {
+		t.Fatalf("CompleteTwoFactorLogin() with recovery code error = %v", err)
+	}
+	if _, _, err := svc.CompleteTwoFactorLogin(context.Background(), input); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("reused recovery code error = %v, want %v", err, ErrInvalidTwoFactorCode)
+	}
+}
+
+func TestResetTwoFactorRestoresPasswordOnlyLogin(t *testing.T) {
+	svc, _ := newTwoFactorTestService(t)
+	enableTwoFactor(t, svc)
+
+	if err := svc.ResetTwoFactor(context.Background(), 1, 3); err != nil {
+		t.Fatalf("ResetTwoFactor() error = %v", err)
+	}
+
+	_, result, err := svc.Login(context.Background(), LoginInput{Identifier: "alice", Password: correctPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if result.TwoFactorChallenge != "" || result.AccessToken == "" {
+		t.Fatalf("expected tokens without challenge, got %+v", result)
+	}
+}
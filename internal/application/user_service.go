@@ -1,19 +1,136 @@
 package application
 
 import (
+	"backend/internal/auth"
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
 	"context"
+	"time"
 )
 
+// DefaultRefreshTokenTTL is how long a refresh token stays valid when not configured.
+const DefaultRefreshTokenTTL = 7 * 24 * time.Hour
+
 type UserService struct {
-	userRepo  repository.UserRepository
-	jwtSecret []byte
+	userRepo    repository.UserRepository
+	jwtKeys     *auth.KeySet
+	roleRepo    repository.RoleRepository
+	sessionRepo repository.SessionRepository
+	refreshTTL  time.Duration
+
+	twoFactorRepo repository.TwoFactorRepository
+	totpIssuer    string
+
+	attemptRepo repository.LoginAttemptRepository
+	throttle    LoginThrottlePolicy
+	clock       Clock
+
+	tokenRepo            repository.UserTokenRepository
+	mailer               Mailer
+	appBaseURL           string
+	requireVerifiedEmail bool
+
+	apiKeyRepo repository.APIKeyRepository
+
+	preferencesRepo repository.PreferencesRepository
+	deletionGrace   time.Duration
+
+	identityRepo      repository.IdentityRepository
+	identityProviders map[string]IdentityProvider
+
+	audit *AuditLog
+
+	sessionCache    sessionCache
+	userStatusCache sessionCache
+}
+
+// UserServiceOption configures optional collaborators of the UserService.
+type UserServiceOption func(*UserService)
+
+// WithSessionRepository enables refresh tokens backed by a server-side session store.
+// Without it, Login only issues access tokens.
+func WithSessionRepository(repo repository.SessionRepository) UserServiceOption {
+	return func(s *UserService) { s.sessionRepo = repo }
+}
+
+// WithRoles resolves the permissions put in access tokens from the role store. Without it,
+// the built-in defaults of model.DefaultRoles apply.
+func WithRoles(repo repository.RoleRepository) UserServiceOption {
+	return func(s *UserService) { s.roleRepo = repo }
+}
+
+// WithRefreshTokenTTL sets the lifetime of refresh tokens.
+func WithRefreshTokenTTL(ttl time.Duration) UserServiceOption {
+	return func(s *UserService) {
+		if ttl > 0 {
+			s.refreshTTL = ttl
+		}
+	}
+}
+
+// WithTwoFactor enables TOTP two-factor authentication. issuer is the name authenticator
+// apps display next to the codes.
+func WithTwoFactor(repo repository.TwoFactorRepository, issuer string) UserServiceOption {
+	return func(s *UserService) {
+		s.twoFactorRepo = repo
+		s.totpIssuer = issuer
+	}
+}
+
+// WithLoginAttempts enables failed-login tracking, throttling and lockout.
+func WithLoginAttempts(repo repository.LoginAttemptRepository, policy LoginThrottlePolicy) UserServiceOption {
+	return func(s *UserService) {
+		s.attemptRepo = repo
+		s.throttle = policy
+	}
+}
+
+// WithAccountEmails enables email verification and password reset. appBaseURL is the
+// frontend address the emailed links point to.
+func WithAccountEmails(tokens repository.UserTokenRepository, mailer Mailer, appBaseURL string) UserServiceOption {
+	return func(s *UserService) {
+		s.tokenRepo = tokens
+		s.mailer = mailer
+		s.appBaseURL = appBaseURL
+	}
+}
+
+// WithEmailVerificationRequired refuses logins until the user verified their email.
+func WithEmailVerificationRequired(required bool) UserServiceOption {
+	return func(s *UserService) { s.requireVerifiedEmail = required }
+}
+
+// WithAPIKeys enables API keys for programmatic access.
+func WithAPIKeys(repo repository.APIKeyRepository) UserServiceOption {
+	return func(s *UserService) { s.apiKeyRepo = repo }
+}
+
+// WithAuditLog records security-relevant actions in the audit trail.
+func WithAuditLog(audit *AuditLog) UserServiceOption {
+	return func(s *UserService) { s.audit = audit }
+}
+
+// WithClock replaces the system clock, for tests.
+func WithClock(clock Clock) UserServiceOption {
+	return func(s *UserService) { s.clock = clock }
+}
+
+// AuthResult holds the tokens issued to a client after a successful login or refresh.
+type AuthResult struct {
+	AccessToken      string
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+	// TwoFactorChallenge is set instead of the tokens when the login needs a second factor;
+	// it is exchanged through CompleteTwoFactorLogin.
+	TwoFactorChallenge string
 }
 
 type LoginInput struct {
 	Identifier string
 	Password   string
+	// IP and UserAgent describe the client and are recorded on the new session.
+	IP        string
+	UserAgent string
 }
 
 type RegisterInput struct {
@@ -29,8 +146,19 @@ type ChangePasswordInput struct {
 	NewPassword string
 }
 
-func NewUserService(userRepo repository.UserRepository, jwtSecret string) *UserService {
-	return &UserService{userRepo: userRepo, jwtSecret: []byte(jwtSecret)}
+func NewUserService(userRepo repository.UserRepository, jwtKeys *auth.KeySet, opts ...UserServiceOption) *UserService {
+	s := &UserService{
+		userRepo:      userRepo,
+		jwtKeys:       jwtKeys,
+		refreshTTL:    DefaultRefreshTokenTTL,
+		throttle:      DefaultLoginThrottlePolicy(),
+		clock:         systemClock{},
+		deletionGrace: DefaultAccountDeletionGrace,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *UserService) FindByID(ctx context.Context, id uint) (model.User, error) {
@@ -2,25 +2,31 @@ package application
 
 import (
 	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
 	"context"
+	"database/sql"
+	"time"
 )
 
 type fakeUserRepository struct {
-	migrateFn               func() error
 	saveFn                  func(user model.User) error
 	findByUsernameOrEmailFn func(identifier string) (model.User, error)
 	findByIDFn              func(id uint) (model.User, error)
+	updatePasswordFn        func(id uint, hashedPassword string) error
+	markEmailVerifiedFn     func(id uint) error
+	listFn                  func(filter repository.UserFilter) ([]model.User, int, error)
+	updateRoleFn            func(id uint, role string) error
+	setDisabledFn           func(id uint, disabled bool) error
+	requirePasswordResetFn  func(id uint) error
+	updateEmailFn           func(id uint, email string, verified bool) error
+	updateUsernameFn        func(id uint, username string) error
+	scheduleDeletionFn      func(id uint, at *time.Time) error
+	listDueForDeletionFn    func(t time.Time) ([]uint, error)
+	deleteFn                func(id uint) error
 
 	savedUsers []model.User
 }
 
-func (f *fakeUserRepository) Migrate() error {
-	if f.migrateFn != nil {
-		return f.migrateFn()
-	}
-	return nil
-}
-
 func (f *fakeUserRepository) Save(ctx context.Context, user model.User) error {
 	f.savedUsers = append(f.savedUsers, user)
 	if f.saveFn != nil {
@@ -44,5 +50,531 @@ func (f *fakeUserRepository) FindByID(ctx context.Context, id uint) (model.User,
 }
 
 func (f *fakeUserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	if f.updatePasswordFn != nil {
+		return f.updatePasswordFn(id, hashedPassword)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	if f.markEmailVerifiedFn != nil {
+		return f.markEmailVerifiedFn(id)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int, error) {
+	if f.listFn != nil {
+		return f.listFn(filter)
+	}
+	return nil, 0, nil
+}
+
+func (f *fakeUserRepository) UpdateRole(ctx context.Context, id uint, role string) error {
+	if f.updateRoleFn != nil {
+		return f.updateRoleFn(id, role)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	if f.setDisabledFn != nil {
+		return f.setDisabledFn(id, disabled)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) RequirePasswordReset(ctx context.Context, id uint) error {
+	if f.requirePasswordResetFn != nil {
+		return f.requirePasswordResetFn(id)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) UpdateEmail(ctx context.Context, id uint, email string, verified bool) error {
+	if f.updateEmailFn != nil {
+		return f.updateEmailFn(id, email, verified)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) UpdateUsername(ctx context.Context, id uint, username string) error {
+	if f.updateUsernameFn != nil {
+		return f.updateUsernameFn(id, username)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) ScheduleDeletion(ctx context.Context, id uint, at *time.Time) error {
+	if f.scheduleDeletionFn != nil {
+		return f.scheduleDeletionFn(id, at)
+	}
+	return nil
+}
+
+func (f *fakeUserRepository) ListDueForDeletion(ctx context.Context, t time.Time) ([]uint, error) {
+	if f.listDueForDeletionFn != nil {
+		return f.listDueForDeletionFn(t)
+	}
+	return nil, nil
+}
+
+func (f *fakeUserRepository) Delete(ctx context.Context, id uint) error {
+	if f.deleteFn != nil {
+		return f.deleteFn(id)
+	}
+	return nil
+}
+
+type fakeSessionRepository struct {
+	sessions map[string]*model.Session
+	tokens   map[string]*model.RefreshToken
+	lookups  int
+}
+
+func newFakeSessionRepository() *fakeSessionRepository {
+	return &fakeSessionRepository{
+		sessions: map[string]*model.Session{},
+		tokens:   map[string]*model.RefreshToken{},
+	}
+}
+
+func (f *fakeSessionRepository) Create(ctx context.Context, session model.Session) error {
+	f.sessions[session.ID] = &session
+	return nil
+}
+
+func (f *fakeSessionRepository) FindByID(ctx context.Context, id string) (model.Session, error) {
+	f.lookups++
+	s, ok := f.sessions[id]
+	if !ok {
+		return model.Session{}, sql.ErrNoRows
+	}
+	return *s, nil
+}
+
+func (f *fakeSessionRepository) ListActive(ctx context.Context, userID uint, now time.Time) ([]model.Session, error) {
+	var out []model.Session
+	for _, s := range f.sessions {
+		if s.UserID == userID && s.RevokedAt == nil && s.ExpiresAt.After(now) {
+			out = append(out, *s)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSessionRepository) Touch(ctx context.Context, id string, seenAt time.Time, ip string, expiresAt time.Time) error {
+	if s, ok := f.sessions[id]; ok {
+		s.LastSeenAt = seenAt
+		s.IP = ip
+		if expiresAt.After(s.ExpiresAt) {
+			s.ExpiresAt = expiresAt
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessionRepository) Revoke(ctx context.Context, id string, at time.Time) error {
+	if s, ok := f.sessions[id]; ok && s.RevokedAt == nil {
+		s.RevokedAt = &at
+	}
+	return nil
+}
+
+func (f *fakeSessionRepository) RevokeAllExcept(ctx context.Context, userID uint, keepID string, at time.Time) ([]string, error) {
+	var ids []string
+	for _, s := range f.sessions {
+		if s.UserID == userID && s.ID != keepID && s.RevokedAt == nil {
+			s.RevokedAt = &at
+			ids = append(ids, s.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (f *fakeSessionRepository) CreateRefreshToken(ctx context.Context, token model.RefreshToken) error {
+	f.tokens[token.ID] = &token
+	return nil
+}
+
+func (f *fakeSessionRepository) FindRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, error) {
+	for _, t := range f.tokens {
+		if t.TokenHash == tokenHash {
+			return *t, nil
+		}
+	}
+	return model.RefreshToken{}, sql.ErrNoRows
+}
+
+func (f *fakeSessionRepository) MarkRefreshTokenRotated(ctx context.Context, id string, at time.Time) error {
+	t, ok := f.tokens[id]
+	if !ok || t.RotatedAt != nil {
+		return sql.ErrNoRows
+	}
+	t.RotatedAt = &at
+	return nil
+}
+
+type fakeTwoFactorRepository struct {
+	enrolments map[uint]*model.TwoFactor
+	codes      map[uint]map[string]bool // hash -> used
+}
+
+func newFakeTwoFactorRepository() *fakeTwoFactorRepository {
+	return &fakeTwoFactorRepository{
+		enrolments: map[uint]*model.TwoFactor{},
+		codes:      map[uint]map[string]bool{},
+	}
+}
+
+func (f *fakeTwoFactorRepository) Get(ctx context.Context, userID uint) (model.TwoFactor, error) {
+	tf, ok := f.enrolments[userID]
+	if !ok {
+		return model.TwoFactor{}, sql.ErrNoRows
+	}
+	return *tf, nil
+}
+
+func (f *fakeTwoFactorRepository) SavePending(ctx context.Context, userID uint, secret string, at time.Time) error {
+	f.enrolments[userID] = &model.TwoFactor{UserID: userID, Secret: secret, CreatedAt: at}
+	return nil
+}
+
+func (f *fakeTwoFactorRepository) Enable(ctx context.Context, userID uint, step int64, hashes []string, at time.Time) error {
+	tf, ok := f.enrolments[userID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	tf.Enabled = true
+	tf.EnabledAt = &at
+	tf.LastUsedStep = step
+	f.codes[userID] = map[string]bool{}
+	for _, h := range hashes {
+		f.codes[userID][h] = false
+	}
+	return nil
+}
+
+func (f *fakeTwoFactorRepository) UseStep(ctx context.Context, userID uint, step int64) error {
+	tf, ok := f.enrolments[userID]
+	if !ok || step <= tf.LastUsedStep {
+		return sql.ErrNoRows
+	}
+	tf.LastUsedStep = step
+	return nil
+}
+
+func (f *fakeTwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uint, hash string, at time.Time) error {
+	used, ok := f.codes[userID][hash]
+	if !ok || used {
+		return sql.ErrNoRows
+	}
+	f.codes[userID][hash] = true
+	return nil
+}
+
+func (f *fakeTwoFactorRepository) Delete(ctx context.Context, userID uint) error {
+	delete(f.enrolments, userID)
+	delete(f.codes, userID)
+	return nil
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+type fakeLoginAttemptRepository struct {
+	attempts []model.LoginAttempt
+}
+
+func (f *fakeLoginAttemptRepository) Record(ctx context.Context, attempt model.LoginAttempt) error {
+	f.attempts = append(f.attempts, attempt)
+	return nil
+}
+
+func (f *fakeLoginAttemptRepository) ListByUser(ctx context.Context, userID uint) ([]model.LoginAttempt, error) {
+	var out []model.LoginAttempt
+	for i := len(f.attempts) - 1; i >= 0; i-- {
+		if a := f.attempts[i]; a.UserID != nil && *a.UserID == userID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeLoginAttemptRepository) DeleteByUser(ctx context.Context, userID uint) error {
+	kept := f.attempts[:0]
+	for _, a := range f.attempts {
+		if a.UserID == nil || *a.UserID != userID {
+			kept = append(kept, a)
+		}
+	}
+	f.attempts = kept
+	return nil
+}
+
+func isFailedLogin(a model.LoginAttempt) bool {
+	return a.Outcome == model.LoginOutcomeInvalidCredentials || a.Outcome == model.LoginOutcomeInvalidTwoFactor
+}
+
+func (f *fakeLoginAttemptRepository) AccountFailures(ctx context.Context, accountKey string, since time.Time) (repository.AttemptFailures, error) {
+	for _, a := range f.attempts {
+		if a.AccountKey == accountKey && a.Outcome == model.LoginOutcomeSuccess && a.CreatedAt.After(since) {
+			since = a.CreatedAt
+		}
+	}
+	return f.count(func(a model.LoginAttempt) bool { return a.AccountKey == accountKey }, since), nil
+}
+
+func (f *fakeLoginAttemptRepository) IPFailures(ctx context.Context, ip string, since time.Time) (repository.AttemptFailures, error) {
+	return f.count(func(a model.LoginAttempt) bool { return a.IP == ip }, since), nil
+}
+
+func (f *fakeLoginAttemptRepository) count(match func(model.LoginAttempt) bool, since time.Time) repository.AttemptFailures {
+	var out repository.AttemptFailures
+	for _, a := range f.attempts {
+		if match(a) && isFailedLogin(a) && a.CreatedAt.After(since) {
+			out.Count++
+			if a.CreatedAt.After(out.Last) {
+				out.Last = a.CreatedAt
+			}
+		}
+	}
+	return out
+}
+
+type fakeUserTokenRepository struct {
+	tokens []*model.UserToken
+}
+
+func (f *fakeUserTokenRepository) Create(ctx context.Context, token model.UserToken) error {
+	token.ID = int64(len(f.tokens) + 1)
+	f.tokens = append(f.tokens, &token)
+	return nil
+}
+
+func (f *fakeUserTokenRepository) Consume(ctx context.Context, purpose, tokenHash string, at time.Time) (model.UserToken, error) {
+	for _, t := range f.tokens {
+		if t.TokenHash == tokenHash && t.Purpose == purpose && t.UsedAt == nil && t.ExpiresAt.After(at) {
+			t.UsedAt = &at
+			return *t, nil
+		}
+	}
+	return model.UserToken{}, sql.ErrNoRows
+}
+
+func (f *fakeUserTokenRepository) InvalidateAll(ctx context.Context, userID uint, purpose string, at time.Time) error {
+	for _, t := range f.tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &at
+		}
+	}
+	return nil
+}
+
+type fakeMailer struct {
+	sent []Email
+}
+
+func (f *fakeMailer) Send(ctx context.Context, email Email) error {
+	f.sent = append(f.sent, email)
+	return nil
+}
+
+type fakeRoleRepository struct {
+	roles map[string]model.Role
+}
+
+func newFakeRoleRepository() *fakeRoleRepository {
+	f := &fakeRoleRepository{roles: map[string]model.Role{}}
+	for _, r := range model.DefaultRoles() {
+		f.roles[r.Name] = r
+	}
+	return f
+}
+
+func (f *fakeRoleRepository) SeedDefaults(ctx context.Context) error { return nil }
+
+func (f *fakeRoleRepository) List(ctx context.Context) ([]model.Role, error) {
+	var out []model.Role
+	for _, r := range f.roles {
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func (f *fakeRoleRepository) Get(ctx context.Context, name string) (model.Role, error) {
+	r, ok := f.roles[name]
+	if !ok {
+		return model.Role{}, sql.ErrNoRows
+	}
+	return r, nil
+}
+
+func (f *fakeRoleRepository) Save(ctx context.Context, role model.Role) error {
+	role.BuiltIn = f.roles[role.Name].BuiltIn
+	f.roles[role.Name] = role
+	return nil
+}
+
+func (f *fakeRoleRepository) Delete(ctx context.Context, name string) error {
+	if _, ok := f.roles[name]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(f.roles, name)
+	return nil
+}
+
+type fakeAPIKeyRepository struct {
+	keys []*model.APIKey
+}
+
+func (f *fakeAPIKeyRepository) Create(ctx context.Context, key model.APIKey) error {
+	f.keys = append(f.keys, &key)
+	return nil
+}
+
+func (f *fakeAPIKeyRepository) FindByPrefix(ctx context.Context, prefix string) (model.APIKey, error) {
+	for _, k := range f.keys {
+		if k.Prefix == prefix {
+			return *k, nil
+		}
+	}
+	return model.APIKey{}, sql.ErrNoRows
+}
+
+func (f *fakeAPIKeyRepository) ListByUser(ctx context.Context, userID uint) ([]model.APIKey, error) {
+	var out []model.APIKey
+	for _, k := range f.keys {
+		if k.UserID == userID && k.RevokedAt == nil {
+			out = append(out, *k)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAPIKeyRepository) Revoke(ctx context.Context, userID uint, id string, at time.Time) error {
+	for _, k := range f.keys {
+		if k.ID == id && k.UserID == userID && k.RevokedAt == nil {
+			k.RevokedAt = &at
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+func (f *fakeAPIKeyRepository) TouchLastUsed(ctx context.Context, id string, at time.Time) error {
+	for _, k := range f.keys {
+		if k.ID == id {
+			k.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+type fakeAuditRepository struct {
+	events []model.AuditEvent
+	cutoff time.Time
+}
+
+func (f *fakeAuditRepository) Record(ctx context.Context, event model.AuditEvent) error {
+	event.ID = int64(len(f.events) + 1)
+	f.events = append(f.events, event)
+	return nil
+}
+
+func (f *fakeAuditRepository) Query(ctx context.Context, filter repository.AuditFilter) ([]model.AuditEvent, error) {
+	var out []model.AuditEvent
+	for i := len(f.events) - 1; i >= 0; i-- {
+		e := f.events[i]
+		if (filter.Action == "" || e.Action == filter.Action) &&
+			(filter.ActorID == nil || (e.ActorID != nil && *e.ActorID == *filter.ActorID)) &&
+			(filter.TargetType == "" || e.TargetType == filter.TargetType) &&
+			(filter.TargetID == "" || e.TargetID == filter.TargetID) {
+			out = append(out, e)
+		}
+	}
+	if filter.Offset >= len(out) {
+		return nil, nil
+	}
+	out = out[filter.Offset:]
+	if filter.Limit > 0 && len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func (f *fakeAuditRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	f.cutoff = cutoff
+	kept := f.events[:0]
+	for _, e := range f.events {
+		if !e.CreatedAt.Before(cutoff) {
+			kept = append(kept, e)
+		}
+	}
+	n := int64(len(f.events) - len(kept))
+	f.events = kept
+	return n, nil
+}
+
+type fakeIdentityRepository struct {
+	identities []model.UserIdentity
+}
+
+func (f *fakeIdentityRepository) Find(ctx context.Context, provider, subject string) (model.UserIdentity, error) {
+	for _, i := range f.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return model.UserIdentity{}, sql.ErrNoRows
+}
+
+func (f *fakeIdentityRepository) Create(ctx context.Context, identity model.UserIdentity) error {
+	f.identities = append(f.identities, identity)
+	return nil
+}
+
+func (f *fakeIdentityRepository) ListByUser(ctx context.Context, userID uint) ([]model.UserIdentity, error) {
+	var out []model.UserIdentity
+	for _, i := range f.identities {
+		if i.UserID == userID {
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeIdentityRepository) TouchLogin(ctx context.Context, provider, subject string, at time.Time) error {
+	for i := range f.identities {
+		if f.identities[i].Provider == provider && f.identities[i].Subject == subject {
+			f.identities[i].LastLoginAt = &at
+		}
+	}
+	return nil
+}
+
+type fakePreferencesRepository struct {
+	prefs map[uint]model.UserPreferences
+}
+
+func newFakePreferencesRepository() *fakePreferencesRepository {
+	return &fakePreferencesRepository{prefs: map[uint]model.UserPreferences{}}
+}
+
+func (f *fakePreferencesRepository) Get(ctx context.Context, userID uint) (model.UserPreferences, error) {
+	p, ok := f.prefs[userID]
+	if !ok {
+		return model.UserPreferences{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (f *fakePreferencesRepository) Save(ctx context.Context, prefs model.UserPreferences) error {
+	f.prefs[prefs.UserID] = prefs
 	return nil
 }
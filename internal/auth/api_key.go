@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidAPIKey is returned for unknown, revoked or expired API keys.
+var ErrInvalidAPIKey = errors.New("invalid or expired API key")
+
+// apiKeyTag starts every API key so they are easy to spot in logs and secret scanners.
+const apiKeyTag = "ptk_"
+
+const apiKeyPrefixLen = 8
+
+// NewAPIKey returns a new API key, its public prefix and the hash to store. Keys look like
+// ptk_<8 hex prefix>_<secret>; the prefix identifies the key and may be shown, the full key
+// is only returned once.
+func NewAPIKey() (key, prefix, hash string, err error) {
+	p := make([]byte, apiKeyPrefixLen/2)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", "", err
+	}
+	s := make([]byte, 32)
+	if _, err := rand.Read(s); err != nil {
+		return "", "", "", err
+	}
+	prefix = apiKeyTag + hex.EncodeToString(p)
+	key = prefix + "_" + base64.RawURLEncoding.EncodeToString(s)
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// APIKeyPrefix returns the public prefix of key, or false if it is not shaped like an API key.
+func APIKeyPrefix(key string) (string, bool) {
+	n := len(apiKeyTag) + apiKeyPrefixLen
+	if !strings.HasPrefix(key, apiKeyTag) || len(key) <= n+1 || key[n] != '_' {
+		return "", false
+	}
+	return key[:n], true
+}
+
+// HashAPIKey returns the hex SHA-256 of an API key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
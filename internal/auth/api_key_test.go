@@ -0,0 +1,24 @@
+package auth
+
+import "testing"
+
+func TestNewAPIKeyPrefixRoundTrip(t *testing.T) {
+	key, prefix, hash, err := NewAPIKey()
+	if err != nil {
+		t.Fatalf("NewAPIKey() error = %v", err)
+	}
+
+	got, ok := APIKeyPrefix(key)
+	if !ok || got != prefix {
+		t.Fatalf("APIKeyPrefix() = %q, %v; want %q", got, ok, prefix)
+	}
+	if HashAPIKey(key) != hash {
+		t.Fatal("HashAPIKey(key) does not match the returned hash")
+	}
+
+	for _, bad := range []string{"", "ptk_", prefix, prefix + "_", "xyz_12345678_secret", "ptk_1234567_x"} {
+		if _, ok := APIKeyPrefix(bad); ok {
+			t.Errorf("APIKeyPrefix(%q) accepted a malformed key", bad)
+		}
+	}
+}
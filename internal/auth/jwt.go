@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,31 +14,118 @@ type JWTTokenClaims struct {
 	Username 		string 			`json:"username"`
 	// The role of the user
 	Role 			string			`json:"role"`
+	// Permissions granted to the role when the token was issued.
+	Permissions 	[]string		`json:"perms,omitempty"`
+	// Purpose is empty for access tokens and names the flow for special-purpose tokens.
+	Purpose 		string			`json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWTToken(secret []byte, id uint, username string, role string) (string, error) {
+// SessionID returns the session the access token was issued for,
+// carried in the standard jti claim. It is empty for tokens issued outside a session.
+func (c *JWTTokenClaims) SessionID() string {
+	return c.RegisteredClaims.ID
+}
+
+func GenerateJWTToken(keys *KeySet, id uint, username string, role string) (string, error) {
+	return GenerateSessionJWTToken(keys, id, username, role, nil, "")
+}
+
+// GenerateSessionJWTToken issues an access token carrying the role's permissions, bound to a
+// session through its jti claim. sessionID may be empty for tokens issued outside a session.
+func GenerateSessionJWTToken(keys *KeySet, id uint, username string, role string, permissions []string, sessionID string) (string, error) {
 	expirationTime := time.Now().Add(60 * time.Minute)
 	claims := &JWTTokenClaims{
-		ID:       id,
-		Username: username,
-		Role:     role,
+		ID:          id,
+		Username:    username,
+		Role:        role,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID,
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secret)
+	return keys.Sign(claims)
+}
+
+// PurposeTwoFactor marks the challenge token issued between the password and TOTP steps
+// of a login.
+const PurposeTwoFactor = "2fa_challenge"
+
+// ErrWrongTokenPurpose is returned when a token is presented to the wrong flow.
+var ErrWrongTokenPurpose = errors.New("token not valid for this purpose")
+
+// GenerateChallengeToken issues a short-lived token proving the password step of a
+// two-factor login succeeded. It is never accepted as an access token.
+func GenerateChallengeToken(keys *KeySet, id uint, ttl time.Duration) (string, error) {
+	claims := &JWTTokenClaims{
+		ID:      id,
+		Purpose: PurposeTwoFactor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+	return keys.Sign(claims)
+}
+
+// VerifyChallengeToken validates a token issued by GenerateChallengeToken.
+func VerifyChallengeToken(keys *KeySet, tokenString string) (*JWTTokenClaims, error) {
+	claims, err := parseJWTToken(keys, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeTwoFactor {
+		return nil, ErrWrongTokenPurpose
+	}
+	return claims, nil
+}
+
+// PurposeReauthentication marks the token proving a signed-in user just signed in again at
+// their identity provider.
+const PurposeReauthentication = "reauth"
+
+// GenerateReauthenticationToken issues a short-lived token that stands in for the password
+// when a user without one confirms a sensitive change. It is never accepted as an access
+// token.
+func GenerateReauthenticationToken(keys *KeySet, id uint, ttl time.Duration) (string, error) {
+	claims := &JWTTokenClaims{
+		ID:      id,
+		Purpose: PurposeReauthentication,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+		},
+	}
+	return keys.Sign(claims)
+}
+
+// VerifyReauthenticationToken validates a token issued by GenerateReauthenticationToken.
+func VerifyReauthenticationToken(keys *KeySet, tokenString string) (*JWTTokenClaims, error) {
+	claims, err := parseJWTToken(keys, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeReauthentication {
+		return nil, ErrWrongTokenPurpose
+	}
+	return claims, nil
+}
+
+// VerifyJWTToken validates an access token.
+func VerifyJWTToken(keys *KeySet, tokenString string) (*JWTTokenClaims, error) {
+	claims, err := parseJWTToken(keys, tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, ErrWrongTokenPurpose
+	}
+	return claims, nil
 }
 
-func VerifyJWTToken(secret []byte, tokenString string) (*JWTTokenClaims, error) {
+func parseJWTToken(keys *KeySet, tokenString string) (*JWTTokenClaims, error) {
 	claims := &JWTTokenClaims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token)(interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrTokenMalformed
-		}
-		return secret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.keyFunc,
+		jwt.WithValidMethods([]string{"HS256", "RS256", "EdDSA"}))
 
 	if err != nil {
 		return nil, err
@@ -49,11 +137,3 @@ func VerifyJWTToken(secret []byte, tokenString string) (*JWTTokenClaims, error)
 
 	return claims, nil
 }
-
-func RefreshJWToken(secret []byte, claims *JWTTokenClaims) (string, error) {
-	expirationTime := time.Now().Add(60 * time.Minute)
-	claims.ExpiresAt = jwt.NewNumericDate(expirationTime)
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secret)
-}
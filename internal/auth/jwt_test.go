@@ -10,9 +10,9 @@ import (
 const jwtTestSecret = "test-secret"
 
 func TestGenerateAndVerifyJWTTokenSuccess(t *testing.T) {
-	secret := []byte(jwtTestSecret)
+	keys := NewHMACKeySet([]byte(jwtTestSecret))
 
-	token, err := GenerateJWTToken(secret, 42, "alice", "admin")
+	token, err := GenerateJWTToken(keys, 42, "alice", "admin")
 	if err != nil {
 		t.Fatalf("GenerateJWTToken() error = %v", err)
 	}
@@ -20,7 +20,7 @@ func TestGenerateAndVerifyJWTTokenSuccess(t *testing.T) {
 		t.Fatal("GenerateJWTToken() returned empty token")
 	}
 
-	claims, err := VerifyJWTToken(secret, token)
+	claims, err := VerifyJWTToken(keys, token)
 	if err != nil {
 		t.Fatalf("VerifyJWTToken() error = %v", err)
 	}
@@ -55,7 +55,7 @@ func TestVerifyJWTTokenExpiredToken(t *testing.T) {
 		t.Fatalf("failed to sign expired token: %v", err)
 	}
 
-	if _, err := VerifyJWTToken(secret, token); err == nil {
+	if _, err := VerifyJWTToken(NewHMACKeySet(secret), token); err == nil {
 		t.Fatal("VerifyJWTToken() expected error for expired token, got nil")
 	}
 }
@@ -74,7 +74,7 @@ func TestVerifyJWTTokenWrongSigningKey(t *testing.T) {
 		t.Fatalf("failed to sign token: %v", err)
 	}
 
-	if _, err := VerifyJWTToken([]byte("signing-key-a"), token); err == nil {
+	if _, err := VerifyJWTToken(NewHMACKeySet([]byte("signing-key-a")), token); err == nil {
 		t.Fatal("VerifyJWTToken() expected signature error, got nil")
 	}
 }
@@ -91,37 +91,60 @@ func TestVerifyJWTTokenRejectsNonHMACMethod(t *testing.T) {
 		t.Fatalf("failed to sign none token: %v", err)
 	}
 
-	if _, err := VerifyJWTToken([]byte(jwtTestSecret), token); err == nil {
+	if _, err := VerifyJWTToken(NewHMACKeySet([]byte(jwtTestSecret)), token); err == nil {
 		t.Fatal("VerifyJWTToken() expected error for non-HMAC method, got nil")
 	}
 }
 
-func TestRefreshJWTokenUpdatesExpiry(t *testing.T) {
-	secret := []byte(jwtTestSecret)
+func TestGenerateSessionJWTTokenCarriesSessionID(t *testing.T) {
+	keys := NewHMACKeySet([]byte(jwtTestSecret))
 
-	base := time.Now().Add(1 * time.Minute)
-	claims := &JWTTokenClaims{
-		ID:       9,
-		Username: "refresher",
-		Role:     "user",
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(base),
-		},
+	token, err := GenerateSessionJWTToken(keys, 9, "refresher", "user", []string{"commodity:read"}, "family-1")
+	if err != nil {
+		t.Fatalf("GenerateSessionJWTToken() error = %v", err)
+	}
+
+	claims, err := VerifyJWTToken(keys, token)
+	if err != nil {
+		t.Fatalf("VerifyJWTToken() error = %v", err)
+	}
+	if claims.SessionID() != "family-1" {
+		t.Fatalf("claims.SessionID() = %q, want family-1", claims.SessionID())
+	}
+	if claims.ID != 9 {
+		t.Fatalf("claims.ID = %d, want 9", claims.ID)
 	}
+	if len(claims.Permissions) != 1 || claims.Permissions[0] != "commodity:read" {
+		t.Fatalf("claims.Permissions = %v, want [commodity:read]", claims.Permissions)
+	}
+}
 
-	refreshed, err := RefreshJWToken(secret, claims)
+func TestOIDCStateTokenRoundTrip(t *testing.T) {
+	keys := NewHMACKeySet([]byte(jwtTestSecret))
+
+	state, err := NewOIDCState("corp", 0)
+	if err != nil {
+		t.Fatalf("NewOIDCState() error = %v", err)
+	}
+	token, err := GenerateOIDCStateToken(keys, state, time.Minute)
 	if err != nil {
-		t.Fatalf("RefreshJWToken() error = %v", err)
+		t.Fatalf("GenerateOIDCStateToken() error = %v", err)
 	}
 
-	newClaims, err := VerifyJWTToken(secret, refreshed)
+	got, err := VerifyOIDCStateToken(keys, token)
 	if err != nil {
-		t.Fatalf("VerifyJWTToken(refreshed) error = %v", err)
+		t.Fatalf("VerifyOIDCStateToken() error = %v", err)
 	}
-	if newClaims.ExpiresAt == nil {
-		t.Fatal("newClaims.ExpiresAt is nil")
+	if got.State != state.State || got.Nonce != state.Nonce || got.Verifier != state.Verifier || got.Provider != "corp" {
+		t.Fatalf("claims = %+v, want %+v", got, state)
+	}
+
+	// An access token is not a login state, nor the other way around.
+	access, _ := GenerateJWTToken(keys, 1, "alice", "user")
+	if _, err := VerifyOIDCStateToken(keys, access); err == nil {
+		t.Fatal("VerifyOIDCStateToken(access token) expected error, got nil")
 	}
-	if !newClaims.ExpiresAt.Time.After(base) {
-		t.Fatalf("new expiry %v is not after original %v", newClaims.ExpiresAt.Time, base)
+	if _, err := VerifyJWTToken(keys, token); err == nil {
+		t.Fatal("VerifyJWTToken(state token) expected error, got nil")
 	}
 }
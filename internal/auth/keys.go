@@ -0,0 +1,265 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrUnknownKey = errors.New("token signed with an unknown key")
+
+// Key is one JWT signing or verification key.
+type Key struct {
+	// ID is the kid header value. Asymmetric keys use their RFC 7638 thumbprint.
+	ID     string
+	Method jwt.SigningMethod
+	// signKey is the private key or HMAC secret; nil for verification-only keys.
+	signKey   any
+	verifyKey any
+}
+
+// KeySet signs tokens with one key and verifies them against every key it holds, so a
+// new signing key can be rolled out while tokens from the previous one are still valid.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// hmac verifies legacy tokens that carry no kid header.
+	hmac *Key
+}
+
+// NewHMACKeySet returns a key set that signs and verifies with a shared HS256 secret.
+func NewHMACKeySet(secret []byte) *KeySet {
+	k := &Key{Method: jwt.SigningMethodHS256, signKey: secret, verifyKey: secret}
+	return &KeySet{signing: k, keys: map[string]*Key{}, hmac: k}
+}
+
+// LoadKeySet builds a key set from PEM files. privateKeyFile holds the RSA or Ed25519
+// signing key; publicKeyFiles hold extra verification keys, typically the previous signing
+// keys during a rotation. hmacSecret, when set, keeps HS256 tokens verifiable, or signs
+// them when no private key file is given.
+func LoadKeySet(privateKeyFile string, publicKeyFiles []string, hmacSecret []byte) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]*Key{}}
+	if len(hmacSecret) > 0 {
+		ks.hmac = &Key{Method: jwt.SigningMethodHS256, signKey: hmacSecret, verifyKey: hmacSecret}
+		ks.signing = ks.hmac
+	}
+
+	if privateKeyFile != "" {
+		key, err := loadPrivateKey(privateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ks.signing = key
+		ks.keys[key.ID] = key
+	}
+
+	for _, file := range publicKeyFiles {
+		key, err := loadPublicKey(file)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := ks.keys[key.ID]; !ok {
+			ks.keys[key.ID] = key
+		}
+	}
+
+	if ks.signing == nil {
+		return nil, errors.New("no JWT signing key configured")
+	}
+	return ks, nil
+}
+
+// Sign serializes claims into a token signed with the current signing key.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.Method, claims)
+	if ks.signing.ID != "" {
+		token.Header["kid"] = ks.signing.ID
+	}
+	return token.SignedString(ks.signing.signKey)
+}
+
+// keyFunc resolves the verification key of a parsed token and checks that the token's
+// algorithm matches the key, so a public key can never be used as an HMAC secret.
+func (ks *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	key := ks.hmac
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		key = ks.keys[kid]
+	}
+	if key == nil {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	return key.verifyKey, nil
+}
+
+// JWK is a public key in JSON Web Key format.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKS is the document served at /.well-known/jwks.json.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set. HMAC secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	out := JWKS{Keys: []JWK{}}
+	if ks.signing != nil && ks.signing.ID != "" {
+		out.Keys = append(out.Keys, toJWK(ks.signing))
+	}
+	ids := make([]string, 0, len(ks.keys))
+	for id, key := range ks.keys {
+		if key != ks.signing {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		out.Keys = append(out.Keys, toJWK(ks.keys[id]))
+	}
+	return out
+}
+
+func toJWK(key *Key) JWK {
+	jwk := JWK{Use: "sig", Alg: key.Method.Alg(), Kid: key.ID}
+	switch pub := key.verifyKey.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = b64(pub.N.Bytes())
+		jwk.E = b64(big.NewInt(int64(pub.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = b64(pub)
+	}
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 JWK thumbprint used as kid. The members are listed in
+// the lexicographic order the RFC requires.
+func thumbprint(pub crypto.PublicKey) (string, error) {
+	var members any
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{b64(big.NewInt(int64(k.E)).Bytes()), "RSA", b64(k.N.Bytes())}
+	case ed25519.PublicKey:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{"Ed25519", "OKP", b64(k)}
+	default:
+		return "", fmt.Errorf("unsupported public key type %T", pub)
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return b64(sum[:]), nil
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func readPEM(file string) (*pem.Block, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", file, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s is not PEM encoded", file)
+	}
+	return block, nil
+}
+
+func loadPrivateKey(file string) (*Key, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var priv any
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse private key %s: %w", file, err)
+	}
+
+	switch k := priv.(type) {
+	case *rsa.PrivateKey:
+		return newAsymmetricKey(jwt.SigningMethodRS256, k, &k.PublicKey)
+	case ed25519.PrivateKey:
+		return newAsymmetricKey(jwt.SigningMethodEdDSA, k, k.Public())
+	default:
+		return nil, fmt.Errorf("private key %s: unsupported type %T, expected RSA or Ed25519", file, priv)
+	}
+}
+
+func loadPublicKey(file string) (*Key, error) {
+	block, err := readPEM(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var pub any
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse public key %s: %w", file, err)
+	}
+
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return newAsymmetricKey(jwt.SigningMethodRS256, nil, k)
+	case ed25519.PublicKey:
+		return newAsymmetricKey(jwt.SigningMethodEdDSA, nil, k)
+	default:
+		return nil, fmt.Errorf("public key %s: unsupported type %T, expected RSA or Ed25519", file, pub)
+	}
+}
+
+func newAsymmetricKey(method jwt.SigningMethod, priv any, pub crypto.PublicKey) (*Key, error) {
+	kid, err := thumbprint(pub)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{ID: kid, Method: method, signKey: priv, verifyKey: pub}, nil
+}
@@ -0,0 +1,157 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+func writeEd25519Key(t *testing.T) (privFile, pubFile string) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshal private key: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	return writePEM(t, "ed25519.pem", "PRIVATE KEY", privDER), writePEM(t, "ed25519.pub", "PUBLIC KEY", pubDER)
+}
+
+func TestLoadKeySetSignsWithRS256(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	file := writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(priv))
+
+	keys, err := LoadKeySet(file, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadKeySet() error = %v", err)
+	}
+
+	token, err := GenerateJWTToken(keys, 5, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateJWTToken() error = %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &JWTTokenClaims{})
+	if err != nil {
+		t.Fatalf("ParseUnverified() error = %v", err)
+	}
+	if parsed.Method.Alg() != "RS256" || parsed.Header["kid"] == "" {
+		t.Fatalf("header = %v, want RS256 with kid", parsed.Header)
+	}
+
+	if _, err := VerifyJWTToken(keys, token); err != nil {
+		t.Fatalf("VerifyJWTToken() error = %v", err)
+	}
+
+	jwks := keys.JWKS()
+	if len(jwks.Keys) != 1 || jwks.Keys[0].Kty != "RSA" || jwks.Keys[0].Kid != parsed.Header["kid"] {
+		t.Fatalf("JWKS = %+v", jwks)
+	}
+}
+
+func TestKeySetRotationKeepsPreviousKeyVerifiable(t *testing.T) {
+	oldPriv, oldPub := writeEd25519Key(t)
+	newPriv, _ := writeEd25519Key(t)
+
+	oldKeys, err := LoadKeySet(oldPriv, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadKeySet(old) error = %v", err)
+	}
+	oldToken, err := GenerateJWTToken(oldKeys, 1, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateJWTToken() error = %v", err)
+	}
+
+	rotated, err := LoadKeySet(newPriv, []string{oldPub}, nil)
+	if err != nil {
+		t.Fatalf("LoadKeySet(rotated) error = %v", err)
+	}
+	if _, err := VerifyJWTToken(rotated, oldToken); err != nil {
+		t.Fatalf("token from previous key rejected: %v", err)
+	}
+	if n := len(rotated.JWKS().Keys); n != 2 {
+		t.Fatalf("JWKS keys = %d, want 2", n)
+	}
+
+	withoutOld, err := LoadKeySet(newPriv, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadKeySet(new) error = %v", err)
+	}
+	if _, err := VerifyJWTToken(withoutOld, oldToken); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("error = %v, want %v", err, ErrUnknownKey)
+	}
+}
+
+func TestKeySetKeepsLegacyHMACTokensVerifiable(t *testing.T) {
+	secret := []byte(jwtTestSecret)
+	legacy, err := GenerateJWTToken(NewHMACKeySet(secret), 3, "bob", "user")
+	if err != nil {
+		t.Fatalf("GenerateJWTToken() error = %v", err)
+	}
+
+	privFile, _ := writeEd25519Key(t)
+	keys, err := LoadKeySet(privFile, nil, secret)
+	if err != nil {
+		t.Fatalf("LoadKeySet() error = %v", err)
+	}
+	if _, err := VerifyJWTToken(keys, legacy); err != nil {
+		t.Fatalf("legacy HS256 token rejected: %v", err)
+	}
+	for _, k := range keys.JWKS().Keys {
+		if k.Alg == "HS256" {
+			t.Fatal("HMAC secret must not be published")
+		}
+	}
+}
+
+func TestKeySetRejectsAlgorithmMismatch(t *testing.T) {
+	privFile, _ := writeEd25519Key(t)
+	keys, err := LoadKeySet(privFile, nil, nil)
+	if err != nil {
+		t.Fatalf("LoadKeySet() error = %v", err)
+	}
+	kid := keys.signing.ID
+
+	// An HS256 token whose kid points at the Ed25519 key must not verify.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTTokenClaims{
+		ID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	forged.Header["kid"] = kid
+	token, err := forged.SignedString([]byte("guess"))
+	if err != nil {
+		t.Fatalf("sign forged token: %v", err)
+	}
+
+	if _, err := VerifyJWTToken(keys, token); err == nil {
+		t.Fatal("VerifyJWTToken() accepted a token with a mismatched algorithm")
+	}
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewRefreshToken returns an opaque random refresh token and the hash to store for it.
+// Only the hash is persisted, so a database leak does not expose usable tokens.
+func NewRefreshToken() (token string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+// NewOneTimeToken returns a random token for emailed links (verification, password reset)
+// and the hash to store for it.
+func NewOneTimeToken() (token string, hash string, err error) {
+	return newOpaqueToken()
+}
+
+func newOpaqueToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the hex SHA-256 of a refresh or one-time token. A fast hash is
+// enough here because the token carries 256 bits of entropy.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewSessionID returns a random identifier for sessions and refresh tokens.
+func NewSessionID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
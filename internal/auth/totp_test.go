@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 test key of RFC 6238 appendix B, base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeMatchesRFC6238Vectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode() error = %v", err)
+		}
+		if got != tc.want {
+			t.Fatalf("TOTPCode(t=%d) = %s, want %s", tc.unix, got, tc.want)
+		}
+	}
+}
+
+func TestValidateTOTPAcceptsAdjacentStepOnly(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := TOTPStep(now)
+
+	prev, _ := TOTPCode(rfc6238Secret, step-1)
+	if got, ok := ValidateTOTP(rfc6238Secret, prev, now); !ok || got != step-1 {
+		t.Fatalf("previous step code: step=%d ok=%v", got, ok)
+	}
+
+	old, _ := TOTPCode(rfc6238Secret, step-3)
+	if _, ok := ValidateTOTP(rfc6238Secret, old, now); ok {
+		t.Fatal("code from three periods ago should be rejected")
+	}
+}
+
+func TestRecoveryCodeHashIgnoresFormatting(t *testing.T) {
+	code, err := NewRecoveryCode()
+	if err != nil {
+		t.Fatalf("NewRecoveryCode() error = %v", err)
+	}
+	if len(code) != 19 || strings.Count(code, "-") != 3 {
+		t.Fatalf("code = %q, want xxxx-xxxx-xxxx-xxxx", code)
+	}
+
+	typed := strings.ToUpper(strings.ReplaceAll(code, "-", " "))
+	if HashRecoveryCode(typed) != HashRecoveryCode(code) {
+		t.Fatal("hash should not depend on case or separators")
+	}
+}
+
+func TestChallengeTokenIsNotAnAccessToken(t *testing.T) {
+	keys := NewHMACKeySet([]byte(jwtTestSecret))
+
+	challenge, err := GenerateChallengeToken(keys, 4, time.Minute)
+	if err != nil {
+		t.Fatalf("GenerateChallengeToken() error = %v", err)
+	}
+	if _, err := VerifyJWTToken(keys, challenge); err == nil {
+		t.Fatal("challenge token accepted as access token")
+	}
+	claims, err := VerifyChallengeToken(keys, challenge)
+	if err != nil || claims.ID != 4 {
+		t.Fatalf("VerifyChallengeToken() = %+v, %v", claims, err)
+	}
+
+	access, err := GenerateJWTToken(keys, 4, "alice", "user")
+	if err != nil {
+		t.Fatalf("GenerateJWTToken() error = %v", err)
+	}
+	if _, err := VerifyChallengeToken(keys, access); err == nil {
+		t.Fatal("access token accepted as challenge token")
+	}
+}
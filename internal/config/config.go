@@ -3,8 +3,10 @@ package config
 import (
 	"fmt"
 	"log"
+	"net/netip"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,9 +19,29 @@ type Config struct {
 	JWT    JWTConfig
 	Server ServerConfig
 	Alpha  AlphaConfig
+	Prices PriceGuardConfig
+	Login  LoginGuardConfig
+	Mail   MailConfig
+	Audit  AuditConfig
+	Accounts AccountConfig
+	Storage  StorageConfig
+	Cache    CacheConfig
+	OIDC   OIDCConfig
+	// Demo runs without a database: data lives in memory and is lost on exit.
+	Demo bool
 }
 
+// Database drivers selectable with DB_DRIVER.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DBConfig struct {
+	// Driver is DriverPostgres or DriverSQLite. SQLite stores users, prices and correlations
+	// in the file at Path; the other data is kept in memory.
+	Driver   string
+	Path     string
 	Host     string
 	Port     string
 	User     string
@@ -31,6 +53,13 @@ type DBConfig struct {
 type JWTConfig struct {
 	SigningKey string
 	TokenTTL  time.Duration
+	RefreshTTL time.Duration
+	// PrivateKeyFile is a PEM RSA or Ed25519 key; when set it signs tokens instead of SigningKey.
+	PrivateKeyFile string
+	// PublicKeyFiles are extra PEM keys accepted for verification, e.g. the previous signing key.
+	PublicKeyFiles []string
+	// TOTPIssuer is the account issuer shown by authenticator apps.
+	TOTPIssuer string
 }
 
 type ServerConfig struct {
@@ -38,6 +67,9 @@ type ServerConfig struct {
 	Port         string
 	CookieSecure bool
 	CORSOrigins  []string
+	// TrustedProxies are the reverse proxies whose X-Forwarded-For and X-Real-IP headers
+	// are believed. Requests from anywhere else are attributed to their peer address.
+	TrustedProxies []netip.Prefix
 }
 
 type AlphaConfig struct {
@@ -45,11 +77,90 @@ type AlphaConfig struct {
 	GoldPricezKey   string
 }
 
+// PriceGuardConfig holds the thresholds used to quarantine suspicious fetched prices.
+type PriceGuardConfig struct {
+	MaxJumpPct float64
+	MaxZScore  float64
+}
+
+// LoginGuardConfig holds the failed-login limits.
+type LoginGuardConfig struct {
+	MaxAccountFailures int
+	MaxIPFailures      int
+	LockoutDuration    time.Duration
+}
+
+// OIDCConfig lists the external OpenID Connect providers users may sign in with.
+type OIDCConfig struct {
+	// PublicURL is this API's external address; provider callbacks are
+	// PublicURL/api/oauth/<name>/callback.
+	PublicURL string
+	Providers []OIDCProviderConfig
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+}
+
+// AuditConfig configures the security audit log.
+type AuditConfig struct {
+	// Retention is how long audit events are kept before the cleanup job deletes them.
+	Retention time.Duration
+}
+
+// AccountConfig configures self-service account management.
+type AccountConfig struct {
+	// DeletionGrace is how long a deleted account can still be restored before it is erased.
+	DeletionGrace time.Duration
+}
+
+// StorageConfig configures the monthly partitions of price and correlation history.
+type StorageConfig struct {
+	// PriceRetention is how long every fetched price is kept before old months are reduced
+	// to one price per commodity and day; zero keeps them all.
+	PriceRetention time.Duration
+	// CorrelationRetention is how long correlations are kept; zero keeps them all.
+	CorrelationRetention time.Duration
+	// PartitionsAhead is how many upcoming months get their partition in advance.
+	PartitionsAhead int
+}
+
+// CacheConfig sets how long latest prices and correlations are served from memory.
+type CacheConfig struct {
+	LatestPriceTTL       time.Duration
+	LatestCorrelationTTL time.Duration
+	// LatestPriceSource is "storage" to serve the prices the refresh cycle saved, or "live"
+	// to fetch every latest price from the provider.
+	LatestPriceSource string
+	// LatestPriceMaxAge is the age past which a price is stale; in storage mode the provider
+	// is only asked for prices older than this.
+	LatestPriceMaxAge time.Duration
+}
+
+// MailConfig configures outgoing account emails. Without an SMTP host, messages are
+// written to OutboxDir instead of being sent; without either, account emails are off.
+type MailConfig struct {
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	OutboxDir    string
+	// AppBaseURL is the frontend address used in emailed links.
+	AppBaseURL string
+	// RequireVerifiedEmail refuses logins until the address is verified.
+	RequireVerifiedEmail bool
+}
+
 // Load reads environment variables (optionally from .env) and returns a validated Config.
 func Load() (*Config, error) {
 	godotenv.Load() // .env file is optional
 
-	cfg := &Config{}
+	cfg := &Config{Demo: parseBool(os.Getenv("DEMO_MODE"))}
 
 	// Database
 	if databaseURL := os.Getenv("DATABASE_URL"); databaseURL != "" {
@@ -66,11 +177,17 @@ func Load() (*Config, error) {
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
 		}
 	}
+	cfg.DB.Driver = strings.ToLower(getEnv("DB_DRIVER", DriverPostgres))
+	cfg.DB.Path = getEnv("SQLITE_PATH", "primetrading.db")
 
 	// JWT
 	cfg.JWT = JWTConfig{
 		SigningKey: os.Getenv("JWT_SIGNING_KEY"),
 		TokenTTL:  60 * time.Minute,
+		RefreshTTL: getEnvDuration("REFRESH_TOKEN_TTL", 7*24*time.Hour),
+		PrivateKeyFile: os.Getenv("JWT_PRIVATE_KEY_FILE"),
+		PublicKeyFiles: splitList(os.Getenv("JWT_PUBLIC_KEY_FILES")),
+		TOTPIssuer:     getEnv("TOTP_ISSUER", "PrimeTrading"),
 	}
 
 	// Server
@@ -80,6 +197,11 @@ func Load() (*Config, error) {
 		CookieSecure: parseBool(os.Getenv("COOKIE_SECURE")),
 		CORSOrigins:  parseCORSOrigins(os.Getenv("CORS_ALLOWED_ORIGINS")),
 	}
+	trusted, err := parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("parse TRUSTED_PROXIES: %w", err)
+	}
+	cfg.Server.TrustedProxies = trusted
 
 	// External APIs
 	cfg.Alpha = AlphaConfig{
@@ -87,12 +209,77 @@ func Load() (*Config, error) {
 		GoldPricezKey:   os.Getenv("GOLD_PRICEZ_API_KEY"),
 	}
 
+	// Price validation
+	cfg.Prices = PriceGuardConfig{
+		MaxJumpPct: getEnvFloat("PRICE_MAX_JUMP_PCT", 25),
+		MaxZScore:  getEnvFloat("PRICE_MAX_ZSCORE", 8),
+	}
+
+	// Login brute-force protection
+	cfg.Login = LoginGuardConfig{
+		MaxAccountFailures: getEnvInt("LOGIN_MAX_FAILURES", 10),
+		MaxIPFailures:      getEnvInt("LOGIN_MAX_IP_FAILURES", 50),
+		LockoutDuration:    getEnvDuration("LOGIN_LOCKOUT", 15*time.Minute),
+	}
+
+	// Account emails
+	cfg.Mail = MailConfig{
+		SMTPHost:             os.Getenv("SMTP_HOST"),
+		SMTPPort:             getEnv("SMTP_PORT", "587"),
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		From:                 getEnv("MAIL_FROM", "no-reply@localhost"),
+		OutboxDir:            os.Getenv("MAIL_OUTBOX_DIR"),
+		AppBaseURL:           getEnv("APP_BASE_URL", "http://localhost:3100"),
+		RequireVerifiedEmail: parseBool(os.Getenv("REQUIRE_EMAIL_VERIFICATION")),
+	}
+
+	cfg.Audit = AuditConfig{
+		Retention: getEnvDuration("AUDIT_RETENTION", 365*24*time.Hour),
+	}
+
+	cfg.Accounts = AccountConfig{
+		DeletionGrace: getEnvDuration("ACCOUNT_DELETION_GRACE", 30*24*time.Hour),
+	}
+
+	cfg.Storage = StorageConfig{
+		PriceRetention:       getEnvRetention("PRICE_RAW_RETENTION", 90*24*time.Hour),
+		CorrelationRetention: getEnvRetention("CORRELATION_RETENTION", 0),
+		PartitionsAhead:      getEnvInt("PARTITIONS_AHEAD", 3),
+	}
+
+	cfg.Cache = CacheConfig{
+		LatestPriceTTL:       getEnvDuration("PRICE_CACHE_TTL", time.Minute),
+		LatestCorrelationTTL: getEnvDuration("CORRELATION_CACHE_TTL", 5*time.Minute),
+		LatestPriceSource:    strings.ToLower(getEnv("LATEST_PRICE_SOURCE", "storage")),
+		LatestPriceMaxAge:    getEnvDuration("LATEST_PRICE_MAX_AGE", 15*time.Minute),
+	}
+
+	// External sign-in: OIDC_PROVIDERS=google,corp with OIDC_GOOGLE_ISSUER, ... per provider
+	cfg.OIDC = OIDCConfig{PublicURL: getEnv("PUBLIC_URL", "http://localhost:8080")}
+	for _, name := range splitList(os.Getenv("OIDC_PROVIDERS")) {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg.OIDC.Providers = append(cfg.OIDC.Providers, OIDCProviderConfig{
+			Name:         strings.ToLower(name),
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		})
+	}
+
 	if err := cfg.validate(); err != nil {
 		return nil, err
 	}
 
-	log.Printf("Config loaded: db=%s:%s/%s server=%s:%s",
-		cfg.DB.Host, cfg.DB.Port, cfg.DB.Name, cfg.Server.Host, cfg.Server.Port)
+	if cfg.Demo {
+		log.Printf("Config loaded: demo mode, in-memory storage server=%s:%s", cfg.Server.Host, cfg.Server.Port)
+	} else if cfg.DB.Driver == DriverSQLite {
+		log.Printf("Config loaded: db=sqlite:%s server=%s:%s", cfg.DB.Path, cfg.Server.Host, cfg.Server.Port)
+	} else {
+		log.Printf("Config loaded: db=%s:%s/%s server=%s:%s",
+			cfg.DB.Host, cfg.DB.Port, cfg.DB.Name, cfg.Server.Host, cfg.Server.Port)
+	}
 
 	return cfg, nil
 }
@@ -123,11 +310,22 @@ func (db *DBConfig) parseURL(raw string) error {
 }
 
 func (c *Config) validate() error {
-	if c.DB.Host == "" {
+	if c.DB.Driver != DriverPostgres && c.DB.Driver != DriverSQLite {
+		return fmt.Errorf("DB_DRIVER must be %s or %s, got %q", DriverPostgres, DriverSQLite, c.DB.Driver)
+	}
+	if c.DB.Driver == DriverPostgres && c.DB.Host == "" && !c.Demo {
 		return fmt.Errorf("DB_HOST is required (set DATABASE_URL or DB_HOST)")
 	}
-	if c.JWT.SigningKey == "" {
-		return fmt.Errorf("JWT_SIGNING_KEY is required")
+	if c.Cache.LatestPriceSource != "storage" && c.Cache.LatestPriceSource != "live" {
+		return fmt.Errorf("LATEST_PRICE_SOURCE must be storage or live, got %q", c.Cache.LatestPriceSource)
+	}
+	if c.JWT.SigningKey == "" && c.JWT.PrivateKeyFile == "" {
+		return fmt.Errorf("JWT_SIGNING_KEY or JWT_PRIVATE_KEY_FILE is required")
+	}
+	for _, p := range c.OIDC.Providers {
+		if p.Issuer == "" || p.ClientID == "" {
+			return fmt.Errorf("OIDC provider %s needs OIDC_%s_ISSUER and OIDC_%s_CLIENT_ID", p.Name, strings.ToUpper(p.Name), strings.ToUpper(p.Name))
+		}
 	}
 	return nil
 }
@@ -140,6 +338,59 @@ func getEnv(key, fallback string) string {
 	return v
 }
 
+func getEnvFloat(key string, fallback float64) float64 {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %v", key, v, fallback)
+		return fallback
+	}
+	return f
+}
+
+func getEnvInt(key string, fallback int) int {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("invalid %s=%q, using default %v", key, v, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("invalid %s=%q, using default %v", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
+// getEnvRetention reads a retention period, where 0 keeps everything.
+func getEnvRetention(key string, fallback time.Duration) time.Duration {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		log.Printf("invalid %s=%q, using default %v", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
 func parseBool(s string) bool {
 	v := strings.ToLower(strings.TrimSpace(s))
 	return v == "1" || v == "true" || v == "yes"
@@ -154,16 +405,40 @@ func parseCORSOrigins(s string) []string {
 			"https://primetrading-nine.vercel.app",
 		}
 	}
-	parts := strings.Split(configured, ",")
-	origins := make([]string, 0, len(parts))
-	for _, p := range parts {
-		origin := strings.TrimSpace(p)
-		if origin != "" {
-			origins = append(origins, origin)
-		}
-	}
+	origins := splitList(configured)
 	if len(origins) == 0 {
 		return []string{"http://localhost:3100", "http://127.0.0.1:3100"}
 	}
 	return origins
 }
+
+// parseTrustedProxies parses a comma-separated list of networks in CIDR notation; a bare
+// address stands for itself.
+func parseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range splitList(s) {
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is neither an address nor a CIDR network", entry)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// splitList parses a comma-separated list, dropping blank entries.
+func splitList(s string) []string {
+	parts := strings.Split(s, ",")
+	items := make([]string, 0, len(parts))
+	for _, p := range parts {
+		item := strings.TrimSpace(p)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
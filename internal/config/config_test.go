@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetentionAcceptsZero(t *testing.T) {
+	tests := []struct {
+		name                string
+		price, corr         string
+		wantPrice, wantCorr time.Duration
+	}{
+		{"defaults", "", "", 90 * 24 * time.Hour, 0},
+		{"zero keeps everything", "0", "0s", 0, 0},
+		{"explicit periods", "720h", "8760h", 720 * time.Hour, 8760 * time.Hour},
+		{"negative falls back", "-1h", "-1h", 90 * 24 * time.Hour, 0},
+		{"garbage falls back", "forever", "soon", 90 * 24 * time.Hour, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEMO_MODE", "true")
+			t.Setenv("JWT_SIGNING_KEY", "test-key")
+			t.Setenv("PRICE_RAW_RETENTION", tt.price)
+			t.Setenv("CORRELATION_RETENTION", tt.corr)
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Storage.PriceRetention != tt.wantPrice || cfg.Storage.CorrelationRetention != tt.wantCorr {
+				t.Fatalf("retention = %v, %v; want %v, %v", cfg.Storage.PriceRetention, cfg.Storage.CorrelationRetention, tt.wantPrice, tt.wantCorr)
+			}
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	got, err := parseTrustedProxies("127.0.0.1, 10.0.0.0/8 ,::1,172.18.0.9/16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"127.0.0.1/32", "10.0.0.0/8", "::1/128", "172.18.0.0/16"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i, p := range got {
+		if p.String() != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	if _, err := parseTrustedProxies("10.0.0.0/8,proxy.local"); err == nil {
+		t.Fatal("expected a host name to be rejected")
+	}
+	if got, _ := parseTrustedProxies(""); len(got) != 0 {
+		t.Fatalf("nothing is trusted by default, got %v", got)
+	}
+}
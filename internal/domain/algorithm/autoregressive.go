@@ -0,0 +1,108 @@
+package algorithm
+
+import (
+	"errors"
+	"math"
+)
+
+// AutoRegressive returns a Forecaster fitting an AR(p) model with the Yule-Walker equations,
+// solved through the Levinson-Durbin recursion. Prediction intervals come from the
+// model's psi-weights, so they widen according to the fitted dynamics rather than sqrt(h).
+func AutoRegressive(order int) Forecaster {
+	return func(series []float64, horizon int, z float64) ([]ForecastPoint, error) {
+		if order < 1 {
+			return nil, errors.New("AR order must be at least 1")
+		}
+		if len(series) < 2*order+1 {
+			return nil, ErrInsufficientData
+		}
+		if horizon <= 0 {
+			return nil, errors.New("horizon must be positive")
+		}
+
+		mu := mean(series)
+		phi, sigma2, err := fitYuleWalker(series, mu, order)
+		if err != nil {
+			return nil, err
+		}
+
+		// Recursive forecasts on the demeaned series.
+		history := make([]float64, 0, len(series)+horizon)
+		for _, v := range series {
+			history = append(history, v-mu)
+		}
+
+		psi := psiWeights(phi, horizon)
+
+		points := make([]ForecastPoint, horizon)
+		var cumulative float64
+		for h := 1; h <= horizon; h++ {
+			var next float64
+			for i, coef := range phi {
+				next += coef * history[len(history)-1-i]
+			}
+			history = append(history, next)
+
+			cumulative += psi[h-1] * psi[h-1]
+			points[h-1] = withInterval(next+mu, z*math.Sqrt(sigma2*cumulative))
+		}
+		return points, nil
+	}
+}
+
+// fitYuleWalker estimates the AR coefficients and the innovation variance.
+func fitYuleWalker(series []float64, mu float64, order int) ([]float64, float64, error) {
+	n := len(series)
+	gamma := make([]float64, order+1)
+	for k := 0; k <= order; k++ {
+		var sum float64
+		for t := 0; t+k < n; t++ {
+			sum += (series[t] - mu) * (series[t+k] - mu)
+		}
+		gamma[k] = sum / float64(n)
+	}
+	if gamma[0] == 0 {
+		// A constant series: no dynamics and no uncertainty.
+		return make([]float64, order), 0, nil
+	}
+
+	phi := make([]float64, order)
+	prev := make([]float64, order)
+	variance := gamma[0]
+
+	for k := 1; k <= order; k++ {
+		acc := gamma[k]
+		for j := 1; j < k; j++ {
+			acc -= prev[j-1] * gamma[k-j]
+		}
+		reflection := acc / variance
+
+		phi[k-1] = reflection
+		for j := 1; j < k; j++ {
+			phi[j-1] = prev[j-1] - reflection*prev[k-j-1]
+		}
+
+		variance *= 1 - reflection*reflection
+		if variance <= 0 {
+			return nil, 0, errors.New("AR fit is not stationary")
+		}
+		copy(prev, phi)
+	}
+
+	return phi, variance, nil
+}
+
+// psiWeights returns the first n coefficients of the MA(infinity) representation of the AR model.
+func psiWeights(phi []float64, n int) []float64 {
+	psi := make([]float64, n)
+	if n == 0 {
+		return psi
+	}
+	psi[0] = 1
+	for j := 1; j < n; j++ {
+		for i := 1; i <= len(phi) && i <= j; i++ {
+			psi[j] += phi[i-1] * psi[j-i]
+		}
+	}
+	return psi
+}
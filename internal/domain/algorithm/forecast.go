@@ -0,0 +1,110 @@
+package algorithm
+
+import (
+	"errors"
+	"math"
+)
+
+// ForecastPoint is a single predicted value together with its prediction interval.
+type ForecastPoint struct {
+	Value float64
+	Lower float64
+	Upper float64
+}
+
+// Accuracy summarises how far a forecast landed from the observed values.
+type Accuracy struct {
+	MAE  float64
+	MAPE float64
+}
+
+// Forecaster fits a model on a series and predicts the next horizon values.
+type Forecaster func(series []float64, horizon int, z float64) ([]ForecastPoint, error)
+
+// ErrInsufficientData is returned when a series is too short to fit a model.
+var ErrInsufficientData = errors.New("insufficient data for forecast")
+
+// NaiveForecast predicts every future value as the last observation.
+// The interval widens with sqrt(h), using the standard deviation of one-step changes.
+func NaiveForecast(series []float64, horizon int, z float64) ([]ForecastPoint, error) {
+	if len(series) < 2 {
+		return nil, ErrInsufficientData
+	}
+	if horizon <= 0 {
+		return nil, errors.New("horizon must be positive")
+	}
+
+	residuals := make([]float64, 0, len(series)-1)
+	for i := 1; i < len(series); i++ {
+		residuals = append(residuals, series[i]-series[i-1])
+	}
+	sigma := rmse(residuals)
+
+	last := series[len(series)-1]
+	points := make([]ForecastPoint, horizon)
+	for h := 1; h <= horizon; h++ {
+		points[h-1] = withInterval(last, z*sigma*math.Sqrt(float64(h)))
+	}
+	return points, nil
+}
+
+// Backtest holds out the last `holdout` values of the series, fits the forecaster
+// on the remainder and measures the error of the out-of-sample predictions.
+func Backtest(series []float64, holdout int, forecaster Forecaster) (Accuracy, error) {
+	if holdout <= 0 || holdout >= len(series) {
+		return Accuracy{}, errors.New("holdout must be between 1 and len(series)-1")
+	}
+
+	train := series[:len(series)-holdout]
+	actual := series[len(series)-holdout:]
+
+	predicted, err := forecaster(train, holdout, 0)
+	if err != nil {
+		return Accuracy{}, err
+	}
+	return MeasureAccuracy(actual, predicted)
+}
+
+// MeasureAccuracy computes the mean absolute error and mean absolute percentage error.
+// Observations equal to zero are skipped for MAPE since the ratio is undefined.
+func MeasureAccuracy(actual []float64, predicted []ForecastPoint) (Accuracy, error) {
+	if len(actual) != len(predicted) {
+		return Accuracy{}, errors.New("actual and predicted must have the same length")
+	}
+	if len(actual) == 0 {
+		return Accuracy{}, ErrInsufficientData
+	}
+
+	var sumAbs, sumPct float64
+	pctCount := 0
+	for i, a := range actual {
+		diff := math.Abs(a - predicted[i].Value)
+		sumAbs += diff
+		if a != 0 {
+			sumPct += diff / math.Abs(a)
+			pctCount++
+		}
+	}
+
+	acc := Accuracy{MAE: sumAbs / float64(len(actual))}
+	if pctCount > 0 {
+		acc.MAPE = 100 * sumPct / float64(pctCount)
+	}
+	return acc, nil
+}
+
+func withInterval(value, halfWidth float64) ForecastPoint {
+	return ForecastPoint{Value: value, Lower: value - halfWidth, Upper: value + halfWidth}
+}
+
+// rmse returns the root mean square of the residuals (their standard deviation around zero).
+func rmse(residuals []float64) float64 {
+	if len(residuals) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, r := range residuals {
+		sum += r * r
+	}
+	return math.Sqrt(sum / float64(len(residuals)))
+}
@@ -0,0 +1,156 @@
+package algorithm
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestNaiveForecastRepeatsLastValue(t *testing.T) {
+	points, err := NaiveForecast([]float64{1, 2, 3, 4}, 3, 1.96)
+	if err != nil {
+		t.Fatalf("NaiveForecast() error = %v", err)
+	}
+	if len(points) != 3 {
+		t.Fatalf("len(points) = %d, want 3", len(points))
+	}
+	for i, p := range points {
+		if p.Value != 4 {
+			t.Fatalf("points[%d].Value = %v, want 4", i, p.Value)
+		}
+		if !(p.Lower < p.Value && p.Value < p.Upper) {
+			t.Fatalf("points[%d] interval [%v, %v] does not contain %v", i, p.Lower, p.Upper, p.Value)
+		}
+	}
+	if points[2].Upper-points[2].Lower <= points[0].Upper-points[0].Lower {
+		t.Fatal("interval should widen with the horizon")
+	}
+}
+
+func TestNaiveForecastRejectsShortSeries(t *testing.T) {
+	if _, err := NaiveForecast([]float64{1}, 3, 1.96); err != ErrInsufficientData {
+		t.Fatalf("error = %v, want ErrInsufficientData", err)
+	}
+}
+
+func TestHoltWintersFollowsLinearTrend(t *testing.T) {
+	series := make([]float64, 40)
+	for i := range series {
+		series[i] = 100 + 2*float64(i)
+	}
+
+	points, err := HoltWinters(7)(series, 5, 1.96)
+	if err != nil {
+		t.Fatalf("HoltWinters() error = %v", err)
+	}
+	for h, p := range points {
+		want := 100 + 2*float64(len(series)+h)
+		if math.Abs(p.Value-want) > 1e-6 {
+			t.Fatalf("points[%d].Value = %v, want %v", h, p.Value, want)
+		}
+	}
+}
+
+func TestHoltWintersCapturesSeasonality(t *testing.T) {
+	pattern := []float64{0, 5, 10, 5, 0, -5, -10}
+	series := make([]float64, 70)
+	for i := range series {
+		series[i] = 50 + pattern[i%len(pattern)]
+	}
+
+	points, err := HoltWinters(len(pattern))(series, len(pattern), 1.96)
+	if err != nil {
+		t.Fatalf("HoltWinters() error = %v", err)
+	}
+	for h, p := range points {
+		want := 50 + pattern[(len(series)+h)%len(pattern)]
+		if math.Abs(p.Value-want) > 1e-6 {
+			t.Fatalf("points[%d].Value = %v, want %v", h, p.Value, want)
+		}
+	}
+}
+
+func TestHoltWintersKeepsSeasonalityAfterAShortSeries(t *testing.T) {
+	pattern := []float64{0, 5, 10, 5, 0, -5, -10}
+	forecast := HoltWinters(len(pattern))
+
+	if _, err := forecast([]float64{1, 2, 3, 4, 5}, 3, 1.96); err != nil {
+		t.Fatalf("short series error = %v", err)
+	}
+
+	series := make([]float64, 70)
+	for i := range series {
+		series[i] = 50 + pattern[i%len(pattern)]
+	}
+	points, err := forecast(series, len(pattern), 1.96)
+	if err != nil {
+		t.Fatalf("HoltWinters() error = %v", err)
+	}
+	for h, p := range points {
+		want := 50 + pattern[(len(series)+h)%len(pattern)]
+		if math.Abs(p.Value-want) > 1e-6 {
+			t.Fatalf("points[%d].Value = %v, want %v: seasonality lost after a short series", h, p.Value, want)
+		}
+	}
+}
+
+func TestAutoRegressiveRecoversAR1(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const phi = 0.8
+
+	series := make([]float64, 2000)
+	for i := 1; i < len(series); i++ {
+		series[i] = phi*series[i-1] + rng.NormFloat64()
+	}
+
+	points, err := AutoRegressive(1)(series, 2, 1.96)
+	if err != nil {
+		t.Fatalf("AutoRegressive() error = %v", err)
+	}
+
+	mu := mean(series)
+	want := mu + phi*(series[len(series)-1]-mu)
+	if math.Abs(points[0].Value-want) > 0.1 {
+		t.Fatalf("one-step forecast = %v, want about %v", points[0].Value, want)
+	}
+
+	width1 := points[0].Upper - points[0].Lower
+	width2 := points[1].Upper - points[1].Lower
+	// With unit innovations the 95% interval width is 2*1.96 at h=1 and 2*1.96*sqrt(1+phi^2) at h=2.
+	if math.Abs(width1-2*1.96) > 0.2 {
+		t.Fatalf("h=1 interval width = %v, want about %v", width1, 2*1.96)
+	}
+	if math.Abs(width2-2*1.96*math.Sqrt(1+phi*phi)) > 0.2 {
+		t.Fatalf("h=2 interval width = %v, want about %v", width2, 2*1.96*math.Sqrt(1+phi*phi))
+	}
+}
+
+func TestMeasureAccuracy(t *testing.T) {
+	actual := []float64{100, 200, 0}
+	predicted := []ForecastPoint{{Value: 110}, {Value: 180}, {Value: 5}}
+
+	acc, err := MeasureAccuracy(actual, predicted)
+	if err != nil {
+		t.Fatalf("MeasureAccuracy() error = %v", err)
+	}
+	if math.Abs(acc.MAE-35.0/3) > 1e-9 {
+		t.Fatalf("MAE = %v, want %v", acc.MAE, 35.0/3)
+	}
+	// The zero observation is excluded: (10% + 10%) / 2.
+	if math.Abs(acc.MAPE-10) > 1e-9 {
+		t.Fatalf("MAPE = %v, want 10", acc.MAPE)
+	}
+}
+
+func TestBacktestUsesHoldout(t *testing.T) {
+	series := []float64{1, 2, 3, 4, 5, 6}
+
+	acc, err := Backtest(series, 2, NaiveForecast)
+	if err != nil {
+		t.Fatalf("Backtest() error = %v", err)
+	}
+	// Trained on 1..4, naive predicts 4 for both 5 and 6.
+	if acc.MAE != 1.5 {
+		t.Fatalf("MAE = %v, want 1.5", acc.MAE)
+	}
+}
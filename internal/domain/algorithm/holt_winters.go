@@ -0,0 +1,115 @@
+package algorithm
+
+import (
+	"errors"
+	"math"
+)
+
+// smoothingGrid is the set of values tried for alpha, beta and gamma when fitting.
+var smoothingGrid = []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+
+type holtWintersFit struct {
+	level     float64
+	trend     float64
+	seasonals []float64
+	sigma     float64
+}
+
+// HoltWinters returns a Forecaster using additive triple exponential smoothing with the given
+// seasonal period. When the series covers fewer than two full seasons (or period < 2) the
+// seasonal component is dropped and Holt's linear trend method is used instead.
+// Smoothing parameters are chosen by grid search on the in-sample one-step squared error.
+func HoltWinters(period int) Forecaster {
+	return func(series []float64, horizon int, z float64) ([]ForecastPoint, error) {
+		if len(series) < 3 {
+			return nil, ErrInsufficientData
+		}
+		if horizon <= 0 {
+			return nil, errors.New("horizon must be positive")
+		}
+
+		// The forecaster is shared between calls, so a short series must not change period.
+		p := period
+		gammas := smoothingGrid
+		if p < 2 || len(series) < 2*p {
+			p = 0
+			gammas = []float64{0}
+		}
+
+		var best *holtWintersFit
+		for _, alpha := range smoothingGrid {
+			for _, beta := range smoothingGrid {
+				for _, gamma := range gammas {
+					fit := fitHoltWinters(series, alpha, beta, gamma, p)
+					if best == nil || fit.sigma < best.sigma {
+						best = fit
+					}
+				}
+			}
+		}
+
+		n := len(series)
+		points := make([]ForecastPoint, horizon)
+		for h := 1; h <= horizon; h++ {
+			value := best.level + float64(h)*best.trend
+			if p > 0 {
+				value += best.seasonals[n-p+(h-1)%p]
+			}
+			points[h-1] = withInterval(value, z*best.sigma*math.Sqrt(float64(h)))
+		}
+		return points, nil
+	}
+}
+
+func fitHoltWinters(series []float64, alpha, beta, gamma float64, period int) *holtWintersFit {
+	n := len(series)
+
+	if period == 0 {
+		level, trend := series[0], series[1]-series[0]
+		residuals := make([]float64, 0, n-1)
+		for t := 1; t < n; t++ {
+			residuals = append(residuals, series[t]-(level+trend))
+			prevLevel := level
+			level = alpha*series[t] + (1-alpha)*(level+trend)
+			trend = beta*(level-prevLevel) + (1-beta)*trend
+		}
+		return &holtWintersFit{level: level, trend: trend, sigma: rmse(residuals)}
+	}
+
+	firstMean := mean(series[:period])
+	secondMean := mean(series[period : 2*period])
+
+	// Initial state sits at the end of the first season; seasonal offsets are measured
+	// against the trend line so a pure trend does not leak into the seasonal component.
+	trend := (secondMean - firstMean) / float64(period)
+	centre := float64(period-1) / 2
+	level := firstMean + trend*centre
+	seasonals := make([]float64, n)
+	for i := 0; i < period; i++ {
+		seasonals[i] = series[i] - (firstMean + trend*(float64(i)-centre))
+	}
+
+	residuals := make([]float64, 0, n-period)
+	for t := period; t < n; t++ {
+		season := seasonals[t-period]
+		residuals = append(residuals, series[t]-(level+trend+season))
+
+		prevLevel := level
+		level = alpha*(series[t]-season) + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+		seasonals[t] = gamma*(series[t]-level) + (1-gamma)*season
+	}
+
+	return &holtWintersFit{level: level, trend: trend, seasonals: seasonals, sigma: rmse(residuals)}
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,103 @@
+package algorithm
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	RegimeSignFlip  = "sign_flip"
+	RegimeDeviation = "deviation"
+
+	// minSignificantCorrelation is the absolute correlation below which a sign is treated as noise.
+	minSignificantCorrelation = 0.3
+	// minBaselineSpread keeps a very stable baseline from turning tiny wiggles into events.
+	minBaselineSpread = 0.05
+)
+
+// RegimeShift marks the point where a rolling correlation left its historical norm.
+type RegimeShift struct {
+	Index     int
+	Kind      string
+	Baseline  float64
+	Value     float64
+	Magnitude float64
+}
+
+// RollingCorrelation returns the Pearson correlation of each trailing window.
+// Element i covers x[i : i+window], so the result has len(x)-window+1 values.
+func RollingCorrelation(x, y []float64, window int) ([]float64, error) {
+	if len(x) != len(y) {
+		return nil, errors.New("input slices must have the same length")
+	}
+	if window < 2 || len(x) < window {
+		return nil, errors.New("insufficient data for rolling correlation")
+	}
+
+	out := make([]float64, 0, len(x)-window+1)
+	for i := 0; i+window <= len(x); i++ {
+		r, err := Pearson(x[i:i+window], y[i:i+window])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// DetectRegimeShifts scans a rolling correlation series and reports threshold crossings
+// against a trailing baseline of `baselineWindow` values: either the correlation flipped
+// sign from a meaningful level, or it moved more than `threshold` standard deviations away
+// from the baseline mean. Once a shift is reported no new one is emitted until the series
+// returns within the threshold, so a sustained break yields a single event at its start.
+func DetectRegimeShifts(series []float64, baselineWindow int, threshold float64) []RegimeShift {
+	var shifts []RegimeShift
+	inShift := false
+
+	for i := baselineWindow; i < len(series); i++ {
+		baseline := series[i-baselineWindow : i]
+		mu := mean(baseline)
+		sigma := math.Max(stdDev(baseline, mu), minBaselineSpread)
+
+		value := series[i]
+		deviation := value - mu
+
+		kind := ""
+		switch {
+		case math.Abs(mu) >= minSignificantCorrelation && math.Abs(value) >= minSignificantCorrelation && math.Signbit(mu) != math.Signbit(value):
+			kind = RegimeSignFlip
+		case math.Abs(deviation) > threshold*sigma:
+			kind = RegimeDeviation
+		}
+
+		if kind == "" {
+			inShift = false
+			continue
+		}
+		if inShift {
+			continue
+		}
+
+		inShift = true
+		shifts = append(shifts, RegimeShift{
+			Index:     i,
+			Kind:      kind,
+			Baseline:  mu,
+			Value:     value,
+			Magnitude: deviation,
+		})
+	}
+
+	return shifts
+}
+
+func stdDev(values []float64, mu float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += (v - mu) * (v - mu)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
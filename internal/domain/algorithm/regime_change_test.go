@@ -0,0 +1,72 @@
+package algorithm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRollingCorrelationWindows(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	y := []float64{2, 4, 6, 5, 3}
+
+	rolling, err := RollingCorrelation(x, y, 3)
+	if err != nil {
+		t.Fatalf("RollingCorrelation() error = %v", err)
+	}
+	if len(rolling) != 3 {
+		t.Fatalf("len = %d, want 3", len(rolling))
+	}
+	if math.Abs(rolling[0]-1) > 1e-9 {
+		t.Fatalf("first window = %v, want 1", rolling[0])
+	}
+	if rolling[2] >= 0 {
+		t.Fatalf("last window = %v, want negative", rolling[2])
+	}
+}
+
+func TestDetectRegimeShiftsReportsSignFlipOnce(t *testing.T) {
+	series := make([]float64, 0, 80)
+	for i := 0; i < 50; i++ {
+		series = append(series, 0.8+0.02*float64(i%3-1))
+	}
+	for i := 0; i < 30; i++ {
+		series = append(series, -0.6)
+	}
+
+	shifts := DetectRegimeShifts(series, 20, 2.5)
+	if len(shifts) != 1 {
+		t.Fatalf("shifts = %+v, want exactly one", shifts)
+	}
+	if shifts[0].Index != 50 || shifts[0].Kind != RegimeSignFlip {
+		t.Fatalf("shift = %+v, want sign flip at 50", shifts[0])
+	}
+	if shifts[0].Magnitude >= 0 {
+		t.Fatalf("magnitude = %v, want negative", shifts[0].Magnitude)
+	}
+}
+
+func TestDetectRegimeShiftsReportsDeviation(t *testing.T) {
+	series := make([]float64, 0, 40)
+	for i := 0; i < 30; i++ {
+		series = append(series, 0.9)
+	}
+	for i := 0; i < 10; i++ {
+		series = append(series, 0.5)
+	}
+
+	shifts := DetectRegimeShifts(series, 20, 2.5)
+	if len(shifts) != 1 || shifts[0].Kind != RegimeDeviation || shifts[0].Index != 30 {
+		t.Fatalf("shifts = %+v, want one deviation at 30", shifts)
+	}
+}
+
+func TestDetectRegimeShiftsIgnoresStableSeries(t *testing.T) {
+	series := make([]float64, 100)
+	for i := range series {
+		series[i] = 0.5 + 0.01*float64(i%5-2)
+	}
+
+	if shifts := DetectRegimeShifts(series, 30, 2.5); len(shifts) != 0 {
+		t.Fatalf("shifts = %+v, want none", shifts)
+	}
+}
@@ -0,0 +1,47 @@
+package algorithm
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// madScale makes the median absolute deviation a consistent estimator of the
+// standard deviation for normally distributed data.
+const madScale = 1.4826
+
+// Median returns the middle value of the sample (the mean of the two middle values for even sizes).
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// RobustZScore measures how many scaled median absolute deviations a value lies from the
+// sample median. Unlike a classic z-score it is not dragged around by the outliers it is
+// meant to catch. It returns 0 when the sample has no spread.
+func RobustZScore(value float64, sample []float64) (float64, error) {
+	if len(sample) < 3 {
+		return 0, errors.New("insufficient data for robust z-score (need at least 3 points)")
+	}
+
+	median := Median(sample)
+	deviations := make([]float64, len(sample))
+	for i, v := range sample {
+		deviations[i] = math.Abs(v - median)
+	}
+
+	mad := Median(deviations) * madScale
+	if mad == 0 {
+		return 0, nil
+	}
+	return (value - median) / mad, nil
+}
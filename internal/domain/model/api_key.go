@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// APIKey lets a user's scripts call the API without a browser session. Only the hash of
+// the key is stored; Prefix is the visible part used to recognise it.
+type APIKey struct {
+	ID      string   `json:"id"`
+	UserID  uint     `json:"-"`
+	Name    string   `json:"name"`
+	Prefix  string   `json:"prefix"`
+	KeyHash string   `json:"-"`
+	Scopes  []string `json:"scopes"`
+	// RateLimit is the number of requests allowed per minute.
+	RateLimit  int        `json:"rate_limit"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// APIKeyAccess is what a valid API key grants to a request.
+type APIKeyAccess struct {
+	KeyID       string
+	UserID      uint
+	Role        string
+	Permissions []string
+	RateLimit   int
+}
@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+// Audit actions.
+const (
+	AuditLogin                  = "auth.login"
+	AuditLogout                 = "auth.logout"
+	AuditRefreshTokenReuse      = "auth.refresh_token_reuse"
+	AuditReauthenticate         = "auth.reauthenticate"
+	AuditPasswordChange         = "password.change"
+	AuditPasswordResetRequest   = "password.reset_request"
+	AuditPasswordReset          = "password.reset"
+	AuditEmailVerify            = "email.verify"
+	AuditEmailChange            = "email.change"
+	AuditUsernameChange         = "username.change"
+	AuditAccountDeletionRequest = "account.deletion_request"
+	AuditAccountDeletionCancel  = "account.deletion_cancel"
+	AuditAccountErase           = "account.erase"
+	AuditTwoFactorEnable        = "2fa.enable"
+	AuditTwoFactorReset         = "2fa.reset"
+	AuditSessionRevoke          = "session.revoke"
+	AuditAPIKeyCreate           = "api_key.create"
+	AuditAPIKeyRevoke           = "api_key.revoke"
+	AuditIdentityLink           = "identity.link"
+	AuditUserRoleChange         = "user.role_change"
+	AuditUserDisable            = "user.disable"
+	AuditUserEnable             = "user.enable"
+	AuditUserForcePasswordReset = "user.force_password_reset"
+	AuditUserDelete             = "user.delete"
+	AuditRoleSave               = "role.save"
+	AuditRoleDelete             = "role.delete"
+	AuditQuarantineApprove      = "quarantine.approve"
+	AuditQuarantineReject       = "quarantine.reject"
+	AuditCorrelationRecompute   = "correlation.recompute"
+)
+
+const (
+	AuditResultSuccess = "success"
+	AuditResultFailure = "failure"
+)
+
+// AuditEvent records a security-relevant action. Events are never updated.
+type AuditEvent struct {
+	ID int64 `json:"id"`
+	// ActorID is the user who acted; nil for anonymous requests such as failed logins of
+	// unknown accounts.
+	ActorID *uint  `json:"actor_id"`
+	Action  string `json:"action"`
+	// TargetType and TargetID name what was acted on, e.g. "user" and "42".
+	TargetType string    `json:"target_type,omitempty"`
+	TargetID   string    `json:"target_id,omitempty"`
+	IP         string    `json:"ip,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	Result     string    `json:"result"`
+	Detail     string    `json:"detail,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// CacheStats are the counters of an in-memory read-through cache.
+type CacheStats struct {
+	Name       string  `json:"name"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+	Entries    int     `json:"entries"`
+	Hits       int64   `json:"hits"`
+	Misses     int64   `json:"misses"`
+	// Coalesced counts misses that waited for a load already in flight instead of
+	// starting their own.
+	Coalesced int64 `json:"coalesced"`
+}
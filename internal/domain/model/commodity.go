@@ -3,18 +3,48 @@ package model
 import "time"
 
 type Commodity struct {
-    ID         int64     `json:"id" db:"id"`
-    Name       string    `json:"commodity" db:"commodity_name"`
-    Date       time.Time `json:"date" db:"date"`
-    PriceKg    float64   `json:"price_kg" db:"price_kg"`
-    Unit       string    `json:"unit" db:"unit"`
-    FetchedAt  time.Time `json:"fetched_at,omitempty" db:"fetched_at"`
+	ID        int64     `json:"id" db:"id"`
+	Name      string    `json:"commodity" db:"commodity_name"`
+	Date      time.Time `json:"date" db:"date"`
+	PriceKg   float64   `json:"price_kg" db:"price_kg"`
+	Unit      string    `json:"unit" db:"unit"`
+	FetchedAt time.Time `json:"fetched_at,omitempty" db:"fetched_at"`
+	// NativePrice is the raw provider quote, expressed per NativeUnit (e.g. USD per troy ounce).
+	NativePrice float64 `json:"native_price,omitempty" db:"native_price"`
+	NativeUnit  string  `json:"native_unit,omitempty" db:"native_unit"`
+	// Price is PriceKg expressed in the unit requested by the caller; Unit describes it.
+	Price float64 `json:"price,omitempty" db:"-"`
 }
 
 type CommodityStatus struct {
-	Name       string    `json:"name"`
-	Source     string    `json:"source"`
-	Available  bool      `json:"available"`
-    LastDate   *time.Time `json:"last_date,omitempty"`
-	LastError  string    `json:"last_error,omitempty"`
+	Name        string     `json:"name"`
+	Source      string     `json:"source"`
+	Available   bool       `json:"available"`
+	LastDate    *time.Time `json:"last_date,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	Quarantined int        `json:"quarantined,omitempty"`
+}
+
+// Where a served latest price comes from.
+const (
+	// PriceSourceStorage is a price the refresh cycle fetched and saved.
+	PriceSourceStorage = "storage"
+	// PriceSourceLive is a price fetched from the provider for the request.
+	PriceSourceLive = "live"
+)
+
+// PriceFreshness tells clients how current a latest price is.
+type PriceFreshness struct {
+	Source string `json:"source"`
+	// AgeSeconds is the time since the price was fetched from the provider.
+	AgeSeconds float64 `json:"age_seconds"`
+	// Stale is set when the price is older than the configured maximum age, e.g. because
+	// the provider could not be reached.
+	Stale bool `json:"stale"`
+}
+
+// LatestPrice is a commodity's latest price with its freshness.
+type LatestPrice struct {
+	Commodity
+	PriceFreshness
 }
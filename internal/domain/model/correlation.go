@@ -11,4 +11,18 @@ type Correlation struct {
     SpearmanRho     float64   `json:"spearman_rho" db:"spearman_rho"`
     DataPoints      int       `json:"data_points" db:"data_points"`
     CreatedAt       time.Time `json:"created_at,omitempty" db:"created_at"`
-}
\ No newline at end of file
+}
+
+// CorrelationEvent records the start of a break in a pair's rolling correlation.
+type CorrelationEvent struct {
+	ID         int64     `json:"id"`
+	CommodityA string    `json:"commodity_a"`
+	CommodityB string    `json:"commodity_b"`
+	StartDate  time.Time `json:"start_date"`
+	Kind       string    `json:"kind"`
+	Baseline   float64   `json:"baseline"`
+	Value      float64   `json:"value"`
+	Magnitude  float64   `json:"magnitude"`
+	Window     int       `json:"window"`
+	CreatedAt  time.Time `json:"created_at,omitempty"`
+}
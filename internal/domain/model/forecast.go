@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+type ForecastPoint struct {
+	Date  time.Time `json:"date"`
+	Value float64   `json:"value"`
+	Lower float64   `json:"lower"`
+	Upper float64   `json:"upper"`
+}
+
+type ForecastAccuracy struct {
+	MAE     float64 `json:"mae"`
+	MAPE    float64 `json:"mape"`
+	Holdout int     `json:"holdout"`
+}
+
+type Forecast struct {
+	Model    string            `json:"model"`
+	Points   []ForecastPoint   `json:"points"`
+	Accuracy *ForecastAccuracy `json:"accuracy,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+type ForecastReport struct {
+	Commodity   string     `json:"commodity"`
+	Unit        string     `json:"unit"`
+	LastDate    time.Time  `json:"last_date"`
+	LastPrice   float64    `json:"last_price"`
+	Horizon     int        `json:"horizon"`
+	Confidence  float64    `json:"confidence"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Forecasts   []Forecast `json:"forecasts"`
+}
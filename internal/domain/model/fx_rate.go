@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// FXRate is the daily exchange rate from Base to Quote: 1 Base = Rate Quote.
+type FXRate struct {
+	ID        int64     `json:"id"`
+	Base      string    `json:"base"`
+	Quote     string    `json:"quote"`
+	Date      time.Time `json:"date"`
+	Rate      float64   `json:"rate"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	LoginOutcomeSuccess            = "success"
+	LoginOutcomeInvalidCredentials = "invalid_credentials"
+	LoginOutcomeInvalidTwoFactor   = "invalid_2fa_code"
+	LoginOutcomeTwoFactorRequired  = "2fa_required"
+	LoginOutcomeLocked             = "locked"
+	LoginOutcomeThrottled          = "throttled"
+	LoginOutcomeEmailNotVerified   = "email_not_verified"
+	LoginOutcomeAccountBlocked     = "account_blocked"
+)
+
+// LoginAttempt is one row of the login audit trail.
+type LoginAttempt struct {
+	ID int64 `json:"id"`
+	// AccountKey groups attempts on the same account whether the user typed their
+	// username or email; unknown identifiers are tracked under the identifier itself.
+	AccountKey string    `json:"account_key"`
+	Identifier string    `json:"identifier"`
+	UserID     *uint     `json:"user_id,omitempty"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	Outcome    string    `json:"outcome"`
+	CreatedAt  time.Time `json:"created_at"`
+}
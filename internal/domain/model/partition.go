@@ -0,0 +1,14 @@
+package model
+
+// Partition is one partition of a time-partitioned table, as shown to admins watching
+// storage growth.
+type Partition struct {
+	Table string `json:"table"`
+	Name  string `json:"name"`
+	// Bounds is the partition range as Postgres prints it, e.g.
+	// FOR VALUES FROM ('2026-10-01 00:00:00') TO ('2026-11-01 00:00:00'), or DEFAULT.
+	Bounds string `json:"bounds"`
+	// Rows is the planner's estimate, refreshed by autovacuum.
+	Rows  int64 `json:"rows"`
+	Bytes int64 `json:"bytes"`
+}
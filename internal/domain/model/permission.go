@@ -0,0 +1,51 @@
+package model
+
+// Permissions checked by the API. Roles are granted a set of these.
+const (
+	PermCommodityRead        = "commodity:read"
+	PermCorrelationRead      = "correlation:read"
+	PermCorrelationRecompute = "correlation:recompute"
+	PermQuarantineReview     = "quarantine:review"
+	PermUsersManage          = "users:manage"
+	PermRolesManage          = "roles:manage"
+	PermAuditRead            = "audit:read"
+	PermStorageRead          = "storage:read"
+)
+
+// AllPermissions lists every known permission.
+var AllPermissions = []string{
+	PermCommodityRead,
+	PermCorrelationRead,
+	PermCorrelationRecompute,
+	PermQuarantineReview,
+	PermUsersManage,
+	PermRolesManage,
+	PermAuditRead,
+	PermStorageRead,
+}
+
+// Role is a named set of permissions assigned to users.
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	// BuiltIn roles are created on first start and cannot be deleted.
+	BuiltIn bool `json:"built_in"`
+}
+
+// DefaultRoles are seeded when the role store is empty of them.
+func DefaultRoles() []Role {
+	return []Role{
+		{Name: RoleUser, Permissions: []string{PermCommodityRead, PermCorrelationRead}, BuiltIn: true},
+		{Name: RoleAdmin, Permissions: append([]string(nil), AllPermissions...), BuiltIn: true},
+	}
+}
+
+// IsPermission reports whether p is a known permission.
+func IsPermission(p string) bool {
+	for _, known := range AllPermissions {
+		if p == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+const (
+	QuarantineStatusPending  = "pending"
+	QuarantineStatusApproved = "approved"
+	QuarantineStatusRejected = "rejected"
+)
+
+// QuarantinedPrice is a fetched price that failed validation and is held back from the
+// commodities table until an admin reviews it.
+type QuarantinedPrice struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"commodity"`
+	Date        time.Time  `json:"date"`
+	PriceKg     float64    `json:"price_kg"`
+	Unit        string     `json:"unit"`
+	NativePrice float64    `json:"native_price,omitempty"`
+	NativeUnit  string     `json:"native_unit,omitempty"`
+	FetchedAt   time.Time  `json:"fetched_at"`
+	Reason      string     `json:"reason"`
+	Status      string     `json:"status"`
+	ReviewedBy  *uint      `json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
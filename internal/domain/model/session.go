@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Session is one login of a user on a device. Its ID is carried as the access token's jti,
+// so revoking the session invalidates every token issued for it.
+type Session struct {
+	ID         string     `json:"id"`
+	UserID     uint       `json:"-"`
+	Device     string     `json:"device"`
+	IP         string     `json:"ip"`
+	UserAgent  string     `json:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	Current    bool       `json:"current"`
+}
+
+// RefreshToken is a single-use credential of a session. Every refresh rotates it into a
+// new RefreshToken of the same session.
+type RefreshToken struct {
+	ID        string
+	SessionID string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RotatedAt *time.Time
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// TwoFactor is the TOTP enrolment of a user. A secret is pending until the user proves
+// possession of it with a valid code.
+type TwoFactor struct {
+	UserID    uint
+	Secret    string
+	Enabled   bool
+	CreatedAt time.Time
+	EnabledAt *time.Time
+	// LastUsedStep is the TOTP time step of the last accepted code, to prevent replays.
+	LastUsedStep int64
+}
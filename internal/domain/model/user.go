@@ -1,9 +1,24 @@
 package model
 
+import "time"
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	ID 			uint		`json:"id"`
 	Username 	string		`json:"username"`
 	Email 		string		`json:"email"`
 	Password 	string		`json:"password"`	
 	Role 		string		`json:"role"`
-}
\ No newline at end of file
+	EmailVerified	bool	`json:"email_verified"`
+	// Disabled accounts cannot sign in and their tokens are rejected.
+	Disabled	bool		`json:"disabled"`
+	// PasswordResetRequired blocks password logins until the password is reset by email.
+	PasswordResetRequired	bool	`json:"password_reset_required"`
+	// DeletionScheduledAt is when the account will be erased, if its owner asked for that.
+	DeletionScheduledAt	*time.Time	`json:"deletion_scheduled_at,omitempty"`
+	CreatedAt	time.Time	`json:"created_at"`
+}
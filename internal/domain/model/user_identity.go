@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// UserIdentity links an account at an external OpenID Connect provider to a user.
+type UserIdentity struct {
+	Provider string `json:"provider"`
+	// Subject is the provider's stable identifier of the account (the sub claim).
+	Subject     string     `json:"-"`
+	UserID      uint       `json:"-"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	ThemeSystem = "system"
+	ThemeLight  = "light"
+	ThemeDark   = "dark"
+)
+
+// UserPreferences holds a user's profile and display settings. Currency, Unit and
+// ChartInterval are the defaults of the commodity endpoints when a request does not set them.
+type UserPreferences struct {
+	UserID        uint      `json:"-"`
+	DisplayName   string    `json:"display_name"`
+	Timezone      string    `json:"timezone"`
+	Currency      string    `json:"currency"`
+	Unit          string    `json:"unit"`
+	ChartInterval string    `json:"chart_interval"`
+	Theme         string    `json:"theme"`
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+}
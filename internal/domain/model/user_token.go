@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeEmailChange       = "email_change"
+)
+
+// UserToken is a single-use token sent to a user by email. Only its hash is stored.
+type UserToken struct {
+	ID        int64
+	UserID    uint
+	Purpose   string
+	TokenHash string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	// Payload carries purpose-specific data, such as the new address of an email change.
+	Payload string
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key model.APIKey) error
+	// FindByPrefix returns sql.ErrNoRows when no key has the prefix.
+	FindByPrefix(ctx context.Context, prefix string) (model.APIKey, error)
+	// ListByUser returns the user's keys that are not revoked, newest first.
+	ListByUser(ctx context.Context, userID uint) ([]model.APIKey, error)
+	// Revoke returns sql.ErrNoRows unless the user owns an unrevoked key with that id.
+	Revoke(ctx context.Context, userID uint, id string, at time.Time) error
+	TouchLastUsed(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+// AuditFilter narrows an audit query. Zero values match every event.
+type AuditFilter struct {
+	ActorID    *uint
+	Action     string
+	TargetType string
+	TargetID   string
+	Result     string
+	Since      time.Time
+	Until      time.Time
+	Limit      int
+	Offset     int
+}
+
+type AuditRepository interface {
+	Record(ctx context.Context, event model.AuditEvent) error
+	// Query returns matching events, newest first.
+	Query(ctx context.Context, filter AuditFilter) ([]model.AuditEvent, error)
+	// DeleteBefore removes events older than cutoff and returns how many were removed.
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -6,9 +6,14 @@ import (
 )
 
 type CommodityRepository interface {
-	Migrate() error
 	Save(ctx context.Context, stock model.Commodity) error
+	// SaveBatch upserts many prices at once, all or none; a later duplicate (name, date)
+	// wins over an earlier one.
+	SaveBatch(ctx context.Context, stocks []model.Commodity) error
 	GetLatestPrice(ctx context.Context, commodity string) (model.Commodity, error)
 	GetPriceHistory(ctx context.Context, commodity string, limit int) ([]model.Commodity, error)
+	// GetDailyCloses returns the last price of each of the most recent days (UTC) that have
+	// prices, newest first, at most days of them.
+	GetDailyCloses(ctx context.Context, commodity string, days int) ([]model.Commodity, error)
 	HasRecentData(ctx context.Context) (bool, error)
 }
\ No newline at end of file
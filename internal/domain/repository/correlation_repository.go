@@ -6,10 +6,11 @@ import (
 )
 
 type CorrelationRepository interface {
-	Migrate() error
 	Save(ctx context.Context, correlation *model.Correlation) error
 	SaveBatch(ctx context.Context, correlations []*model.Correlation) error
 	GetLatest(ctx context.Context, commodityA, commodityB string) (*model.Correlation, error)
 	GetHistory(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.Correlation, error)
 	GetTopCorrelated(ctx context.Context, commodity string, limit int) ([]*model.Correlation, error)
+	SaveEvents(ctx context.Context, events []*model.CorrelationEvent) error
+	GetEvents(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.CorrelationEvent, error)
 }
\ No newline at end of file
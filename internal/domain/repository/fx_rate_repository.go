@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+)
+
+type FXRateRepository interface {
+	Save(ctx context.Context, rate model.FXRate) error
+	GetLatest(ctx context.Context, base, quote string) (model.FXRate, error)
+	ListRates(ctx context.Context, base, quote string) ([]model.FXRate, error)
+}
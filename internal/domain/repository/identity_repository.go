@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+type IdentityRepository interface {
+	// Find returns the identity for a provider account, or sql.ErrNoRows.
+	Find(ctx context.Context, provider, subject string) (model.UserIdentity, error)
+	Create(ctx context.Context, identity model.UserIdentity) error
+	ListByUser(ctx context.Context, userID uint) ([]model.UserIdentity, error)
+	TouchLogin(ctx context.Context, provider, subject string, at time.Time) error
+}
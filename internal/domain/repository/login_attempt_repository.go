@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+// AttemptFailures summarizes recent failed login attempts.
+type AttemptFailures struct {
+	Count int
+	Last  time.Time
+}
+
+type LoginAttemptRepository interface {
+	Record(ctx context.Context, attempt model.LoginAttempt) error
+	// AccountFailures counts failed attempts on an account made after since and after the
+	// account's last successful login.
+	AccountFailures(ctx context.Context, accountKey string, since time.Time) (AttemptFailures, error)
+	// IPFailures counts failed attempts from an address made after since, on any account.
+	IPFailures(ctx context.Context, ip string, since time.Time) (AttemptFailures, error)
+	// ListByUser returns the attempts attributed to a user, newest first.
+	ListByUser(ctx context.Context, userID uint) ([]model.LoginAttempt, error)
+	DeleteByUser(ctx context.Context, userID uint) error
+}
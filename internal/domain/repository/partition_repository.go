@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+// PartitionRepository maintains the monthly partitions of the price and correlation tables.
+type PartitionRepository interface {
+	// EnsurePartitions creates the missing partitions for every month from the month of
+	// from through the month of through, and returns their names.
+	EnsurePartitions(ctx context.Context, from, through time.Time) ([]string, error)
+	// DownsamplePrices keeps only the last price of each commodity and day for the months
+	// that ended by before, then drops their raw partitions and returns their names.
+	DownsamplePrices(ctx context.Context, before time.Time) ([]string, error)
+	// DropCorrelations drops the correlation partitions of the months that ended by before
+	// and returns their names.
+	DropCorrelations(ctx context.Context, before time.Time) ([]string, error)
+	List(ctx context.Context) ([]model.Partition, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+)
+
+type PreferencesRepository interface {
+	// Get returns the stored preferences of a user, or sql.ErrNoRows when there are none.
+	Get(ctx context.Context, userID uint) (model.UserPreferences, error)
+	// Save creates or replaces the preferences of prefs.UserID.
+	Save(ctx context.Context, prefs model.UserPreferences) error
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+type QuarantineRepository interface {
+	Save(ctx context.Context, price model.QuarantinedPrice) error
+	FindByID(ctx context.Context, id int64) (model.QuarantinedPrice, error)
+	List(ctx context.Context, status string, limit int) ([]model.QuarantinedPrice, error)
+	CountPending(ctx context.Context, commodity string) (int, error)
+	UpdateStatus(ctx context.Context, id int64, status string, reviewerID uint, reviewedAt time.Time) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+)
+
+type RoleRepository interface {
+	// SeedDefaults creates the built-in roles that do not exist yet and grants the admin role
+	// any permission it lacks.
+	SeedDefaults(ctx context.Context) error
+	List(ctx context.Context) ([]model.Role, error)
+	// Get returns sql.ErrNoRows for an unknown role.
+	Get(ctx context.Context, name string) (model.Role, error)
+	// Save creates the role or replaces its permissions.
+	Save(ctx context.Context, role model.Role) error
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session model.Session) error
+	FindByID(ctx context.Context, id string) (model.Session, error)
+	// ListActive returns the unrevoked, unexpired sessions of a user, most recently seen first.
+	ListActive(ctx context.Context, userID uint, now time.Time) ([]model.Session, error)
+	// Touch records activity on a session and pushes its expiry to expiresAt.
+	Touch(ctx context.Context, id string, seenAt time.Time, ip string, expiresAt time.Time) error
+	Revoke(ctx context.Context, id string, at time.Time) error
+	// RevokeAllExcept revokes every active session of a user but keepID and returns the
+	// IDs it revoked.
+	RevokeAllExcept(ctx context.Context, userID uint, keepID string, at time.Time) ([]string, error)
+
+	CreateRefreshToken(ctx context.Context, token model.RefreshToken) error
+	FindRefreshToken(ctx context.Context, tokenHash string) (model.RefreshToken, error)
+	// MarkRefreshTokenRotated flags a refresh token as used. It returns sql.ErrNoRows when the
+	// token was already rotated, so concurrent refreshes with the same token cannot both succeed.
+	MarkRefreshTokenRotated(ctx context.Context, id string, at time.Time) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	// Get returns sql.ErrNoRows when the user never started an enrolment.
+	Get(ctx context.Context, userID uint) (model.TwoFactor, error)
+	// SavePending stores a new, not yet enabled secret, replacing any previous enrolment.
+	SavePending(ctx context.Context, userID uint, secret string, at time.Time) error
+	// Enable activates the enrolment and replaces the user's recovery codes.
+	Enable(ctx context.Context, userID uint, step int64, recoveryCodeHashes []string, at time.Time) error
+	// UseStep records an accepted TOTP step. It returns sql.ErrNoRows when step is not
+	// newer than the last accepted one.
+	UseStep(ctx context.Context, userID uint, step int64) error
+	// UseRecoveryCode consumes an unused recovery code. It returns sql.ErrNoRows when the
+	// code does not exist or was already used.
+	UseRecoveryCode(ctx context.Context, userID uint, codeHash string, at time.Time) error
+	// Delete removes the enrolment and all recovery codes of the user.
+	Delete(ctx context.Context, userID uint) error
+}
@@ -3,12 +3,37 @@ package repository
 import (
 	"backend/internal/domain/model"
 	"context"
+	"time"
 )
 
+// UserFilter narrows a user listing. Zero values match every user.
+type UserFilter struct {
+	// Query matches a substring of the username or email, case-insensitively.
+	Query    string
+	Role     string
+	Disabled *bool
+	Limit    int
+	Offset   int
+}
+
 type UserRepository interface {
-	Migrate() error
 	Save(ctx context.Context, user model.User) error
 	FindByUsernameOrEmail(ctx context.Context, identifier string) (model.User, error)
 	FindByID(ctx context.Context, id uint) (model.User, error)
+	// UpdatePassword also clears a pending forced reset.
 	UpdatePassword(ctx context.Context, id uint, hashedPassword string) error
+	MarkEmailVerified(ctx context.Context, id uint) error
+	// List returns one page of users ordered by id, and the number of users matching the filter.
+	List(ctx context.Context, filter UserFilter) ([]model.User, int, error)
+	UpdateRole(ctx context.Context, id uint, role string) error
+	SetDisabled(ctx context.Context, id uint, disabled bool) error
+	RequirePasswordReset(ctx context.Context, id uint) error
+	// UpdateEmail sets a new address and whether it is verified.
+	UpdateEmail(ctx context.Context, id uint, email string, verified bool) error
+	UpdateUsername(ctx context.Context, id uint, username string) error
+	// ScheduleDeletion sets when the account is erased; nil cancels a scheduled deletion.
+	ScheduleDeletion(ctx context.Context, id uint, at *time.Time) error
+	// ListDueForDeletion returns the ids of accounts scheduled for deletion at or before t.
+	ListDueForDeletion(ctx context.Context, t time.Time) ([]uint, error)
+	Delete(ctx context.Context, id uint) error
 }
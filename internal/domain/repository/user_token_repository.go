@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"time"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token model.UserToken) error
+	// Consume marks a valid token as used and returns it. It returns sql.ErrNoRows when
+	// the token does not exist, has another purpose, is expired or was already used.
+	Consume(ctx context.Context, purpose, tokenHash string, at time.Time) (model.UserToken, error)
+	// InvalidateAll marks every unused token of the user for purpose as used.
+	InvalidateAll(ctx context.Context, userID uint, purpose string, at time.Time) error
+}
@@ -0,0 +1,107 @@
+// Package units converts commodity prices between units of measure.
+// Prices are stored per kilogram; each commodity also keeps the unit its provider quotes in.
+package units
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Unit string
+
+const (
+	TroyOunce Unit = "oz"
+	Gram      Unit = "g"
+	Kilogram  Unit = "kg"
+	Tonne     Unit = "t"
+	Pound     Unit = "lb"
+	Barrel    Unit = "bbl"
+)
+
+const (
+	kgPerTroyOunce = 0.0311034768
+	kgPerPound     = 0.45359237
+	// kgPerBarrelBrent is the approximate mass of one barrel of Brent crude.
+	kgPerBarrelBrent = 136.0
+)
+
+var (
+	ErrUnknownUnit      = errors.New("unknown unit, expected one of oz, g, kg, t, lb, bbl")
+	ErrIncompatibleUnit = errors.New("unit is not applicable to this commodity")
+)
+
+// massUnits maps every mass unit to its weight in kilograms.
+var massUnits = map[Unit]float64{
+	TroyOunce: kgPerTroyOunce,
+	Gram:      0.001,
+	Kilogram:  1,
+	Tonne:     1000,
+	Pound:     kgPerPound,
+}
+
+// barrelMass holds the mass of one barrel for the commodities that are quoted by volume.
+var barrelMass = map[string]float64{
+	"brent": kgPerBarrelBrent,
+}
+
+// nativeUnits records the unit each provider quotes a commodity in.
+var nativeUnits = map[string]Unit{
+	"gold":     TroyOunce,
+	"silver":   TroyOunce,
+	"copper":   Tonne,
+	"aluminum": Tonne,
+	"brent":    Barrel,
+}
+
+// Parse validates a unit symbol. An empty symbol means kilograms.
+func Parse(symbol string) (Unit, error) {
+	u := Unit(strings.ToLower(strings.TrimSpace(symbol)))
+	if u == "" {
+		return Kilogram, nil
+	}
+	if _, ok := massUnits[u]; ok || u == Barrel {
+		return u, nil
+	}
+	return "", ErrUnknownUnit
+}
+
+// Native returns the unit the commodity is quoted in by its provider, or kilograms when unknown.
+func Native(commodity string) Unit {
+	if u, ok := nativeUnits[strings.ToLower(commodity)]; ok {
+		return u
+	}
+	return Kilogram
+}
+
+// KgPer returns how many kilograms of the commodity one unit represents.
+func KgPer(commodity string, unit Unit) (float64, error) {
+	if kg, ok := massUnits[unit]; ok {
+		return kg, nil
+	}
+	if unit == Barrel {
+		if kg, ok := barrelMass[strings.ToLower(commodity)]; ok {
+			return kg, nil
+		}
+		return 0, fmt.Errorf("%w: %s is not quoted by volume", ErrIncompatibleUnit, commodity)
+	}
+	return 0, ErrUnknownUnit
+}
+
+// ToPerKg converts a price per `unit` into a price per kilogram.
+func ToPerKg(price float64, commodity string, unit Unit) (float64, error) {
+	kg, err := KgPer(commodity, unit)
+	if err != nil {
+		return 0, err
+	}
+	return price / kg, nil
+}
+
+// FromPerKg converts a price per kilogram into a price per `unit`.
+func FromPerKg(pricePerKg float64, commodity string, unit Unit) (float64, error) {
+	kg, err := KgPer(commodity, unit)
+	if err != nil {
+		return 0, err
+	}
+	return pricePerKg * kg, nil
+}
@@ -0,0 +1,74 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func TestParse(t *testing.T) {
+	cases := map[string]Unit{"": Kilogram, "OZ": TroyOunce, " g ": Gram, "t": Tonne, "lb": Pound, "bbl": Barrel}
+	for in, want := range cases {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Fatalf("Parse(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := Parse("stone"); !errors.Is(err, ErrUnknownUnit) {
+		t.Fatalf("Parse(stone) error = %v, want ErrUnknownUnit", err)
+	}
+}
+
+func TestNativeUnits(t *testing.T) {
+	cases := map[string]Unit{"gold": TroyOunce, "silver": TroyOunce, "copper": Tonne, "aluminum": Tonne, "brent": Barrel, "unknown": Kilogram}
+	for commodity, want := range cases {
+		if got := Native(commodity); got != want {
+			t.Fatalf("Native(%s) = %q, want %q", commodity, got, want)
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	cases := []struct {
+		commodity string
+		unit      Unit
+		perUnit   float64
+		perKg     float64
+	}{
+		{"gold", TroyOunce, 2000, 2000 / 0.0311034768},
+		{"gold", Gram, 64.3, 64300},
+		{"copper", Tonne, 9500, 9.5},
+		{"copper", Pound, 4.309, 4.309 / 0.45359237},
+		{"brent", Barrel, 81.6, 0.6},
+		{"brent", Kilogram, 0.6, 0.6},
+	}
+
+	for _, c := range cases {
+		perKg, err := ToPerKg(c.perUnit, c.commodity, c.unit)
+		if err != nil || !approxEqual(perKg, c.perKg) {
+			t.Fatalf("ToPerKg(%v, %s, %s) = %v, %v; want %v", c.perUnit, c.commodity, c.unit, perKg, err, c.perKg)
+		}
+		back, err := FromPerKg(perKg, c.commodity, c.unit)
+		if err != nil || !approxEqual(back, c.perUnit) {
+			t.Fatalf("FromPerKg round trip for %s/%s = %v, %v; want %v", c.commodity, c.unit, back, err, c.perUnit)
+		}
+	}
+}
+
+func TestTroyOunceIsNotAvoirdupois(t *testing.T) {
+	// 1 kg is about 32.15 troy ounces but 35.27 avoirdupois ounces.
+	perOz, _ := FromPerKg(1, "gold", TroyOunce)
+	if math.Abs(1/perOz-32.1507) > 1e-3 {
+		t.Fatalf("troy ounces per kg = %v, want about 32.1507", 1/perOz)
+	}
+}
+
+func TestBarrelRequiresVolumeQuotedCommodity(t *testing.T) {
+	if _, err := FromPerKg(100, "gold", Barrel); !errors.Is(err, ErrIncompatibleUnit) {
+		t.Fatalf("error = %v, want ErrIncompatibleUnit", err)
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ExportAccountHandler serves GET /api/me/export as a downloadable JSON archive of the data
+// stored about the signed-in user.
+func (h *UserHandler) ExportAccountHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	export, err := h.userService.ExportAccountData(r.Context(), userID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			jsonError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		jsonError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	res := dto.AccountExportResponse{
+		ExportedAt: export.ExportedAt,
+		Profile: dto.AccountProfile{
+			ID:                  export.User.ID,
+			Username:            export.User.Username,
+			Email:               export.User.Email,
+			EmailVerified:       export.User.EmailVerified,
+			Role:                export.User.Role,
+			CreatedAt:           export.User.CreatedAt,
+			DeletionScheduledAt: export.User.DeletionScheduledAt,
+		},
+		Preferences:      export.Preferences,
+		TwoFactorEnabled: export.TwoFactorEnabled,
+		Sessions:         nonNil(export.Sessions),
+		APIKeys:          nonNil(export.APIKeys),
+		Identities:       nonNil(export.Identities),
+		LoginAttempts:    nonNil(export.LoginAttempts),
+		AuditEvents:      nonNil(export.AuditEvents),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", `attachment; filename="account-export.json"`)
+	w.Header().Set("Cache-Control", "no-store")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(res)
+}
+
+// DeleteAccountHandler serves DELETE /api/me. The password (or a reauth token) is asked
+// again; the account is erased after a grace period during which the deletion can be cancelled.
+func (h *UserHandler) DeleteAccountHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	at, err := h.userService.RequestAccountDeletion(r.Context(), userID, req.Password, req.ReauthToken)
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+	// Every session was revoked, including this one.
+	h.clearAuthCookies(w)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(dto.DeleteAccountResponse{DeletionScheduledAt: at})
+}
+
+// CancelAccountDeletionHandler serves POST /api/me/deletion/cancel
+func (h *UserHandler) CancelAccountDeletionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.userService.CancelAccountDeletion(r.Context(), userID); err != nil {
+		if errors.Is(err, application.ErrAccountDeletionNotScheduled) {
+			jsonError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "account deletion cancelled"})
+}
+
+// nonNil makes empty lists encode as [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// VerifyEmailHandler serves POST /api/verify-email with the token from the emailed link.
+func (h *UserHandler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.VerifyEmail(r.Context(), req.Token); err != nil {
+		writeAccountEmailError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "email verified"})
+}
+
+// ResendVerificationHandler serves POST /api/verify-email/resend for the signed-in user.
+func (h *UserHandler) ResendVerificationHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.userService.SendVerificationEmail(r.Context(), userID); err != nil {
+		writeAccountEmailError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "verification email sent"})
+}
+
+// ForgotPasswordHandler serves POST /api/password/forgot. The response is the same
+// whether or not the address belongs to an account.
+func (h *UserHandler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		writeAccountEmailError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"message": "if the address belongs to an account, a reset link has been sent"})
+}
+
+// ResetPasswordHandler serves POST /api/password/reset.
+func (h *UserHandler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		writeAccountEmailError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "password updated successfully"})
+}
+
+func writeAccountEmailError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidEmailToken):
+		jsonErrorCode(w, err.Error(), "invalid_token", http.StatusBadRequest)
+	case errors.Is(err, application.ErrEmailAlreadyVerified):
+		jsonError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrAccountEmailsUnavailable):
+		jsonError(w, err.Error(), http.StatusNotImplemented)
+	case err.Error() == "user not found":
+		jsonError(w, err.Error(), http.StatusNotFound)
+	default:
+		jsonError(w, err.Error(), http.StatusBadRequest)
+	}
+}
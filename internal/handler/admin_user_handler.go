@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListUsersHandler serves GET /api/admin/users?q=&role=&status=&page=&page_size=
+func (h *UserHandler) ListUsersHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := application.UserListInput{
+		Query:  q.Get("q"),
+		Role:   q.Get("role"),
+		Status: q.Get("status"),
+	}
+	if v := q.Get("page"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.Page = n
+		}
+	}
+	if v := q.Get("page_size"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.PageSize = n
+		}
+	}
+
+	page, err := h.userService.ListUsers(r.Context(), req)
+	if err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+
+	resp := dto.AdminUserListResponse{
+		Users:    make([]dto.AdminUserResponse, 0, len(page.Users)),
+		Total:    page.Total,
+		Page:     page.Page,
+		PageSize: page.PageSize,
+	}
+	for _, u := range page.Users {
+		resp.Users = append(resp.Users, toAdminUserResponse(u))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// GetUserHandler serves GET /api/admin/users/{id}
+func (h *UserHandler) GetUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	details, err := h.userService.GetUserDetails(r.Context(), id)
+	if err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+
+	sessions := details.Sessions
+	if sessions == nil {
+		sessions = []model.Session{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.AdminUserDetailsResponse{
+		AdminUserResponse: toAdminUserResponse(details.User),
+		TwoFactorEnabled:  details.TwoFactorEnabled,
+		Sessions:          sessions,
+	})
+}
+
+// UpdateUserRoleHandler serves PUT /api/admin/users/{id}/role
+func (h *UserHandler) UpdateUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.ChangeUserRole(r.Context(), actorID, id, req.Role); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DisableUserHandler serves POST /api/admin/users/{id}/disable
+func (h *UserHandler) DisableUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+// EnableUserHandler serves POST /api/admin/users/{id}/enable
+func (h *UserHandler) EnableUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+func (h *UserHandler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	actorID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.SetUserDisabled(r.Context(), actorID, id, disabled); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForcePasswordResetHandler serves POST /api/admin/users/{id}/force-password-reset
+func (h *UserHandler) ForcePasswordResetHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.ForcePasswordReset(r.Context(), actorID, id); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteUserHandler serves DELETE /api/admin/users/{id}
+func (h *UserHandler) DeleteUserHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.DeleteUser(r.Context(), actorID, id); err != nil {
+		writeAdminUserError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// userIDParam parses the {id} URL parameter, answering 400 when it is not a user id.
+func userIDParam(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id == 0 {
+		jsonError(w, "invalid user id", http.StatusBadRequest)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func toAdminUserResponse(u model.User) dto.AdminUserResponse {
+	return dto.AdminUserResponse{
+		ID:                    u.ID,
+		Username:              u.Username,
+		Email:                 u.Email,
+		Role:                  u.Role,
+		EmailVerified:         u.EmailVerified,
+		Disabled:              u.Disabled,
+		PasswordResetRequired: u.PasswordResetRequired,
+		CreatedAt:             u.CreatedAt,
+	}
+}
+
+func writeAdminUserError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrCannotModifySelf):
+		jsonError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrAccountEmailsUnavailable):
+		jsonError(w, err.Error(), http.StatusNotImplemented)
+	case err.Error() == "user not found":
+		jsonError(w, err.Error(), http.StatusNotFound)
+	default:
+		jsonError(w, err.Error(), http.StatusBadRequest)
+	}
+}
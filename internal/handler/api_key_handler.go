@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListAPIKeysHandler serves GET /api/user/api-keys
+func (h *UserHandler) ListAPIKeysHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	keys, err := h.userService.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+	if keys == nil {
+		keys = []model.APIKey{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// CreateAPIKeyHandler serves POST /api/user/api-keys. The key is only shown in this response.
+func (h *UserHandler) CreateAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	secret, key, err := h.userService.CreateAPIKey(r.Context(), userID, application.CreateAPIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+		RateLimit: req.RateLimit,
+	})
+	if err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(dto.CreateAPIKeyResponse{Key: secret, APIKey: key})
+}
+
+// RevokeAPIKeyHandler serves DELETE /api/user/api-keys/{id}
+func (h *UserHandler) RevokeAPIKeyHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.userService.RevokeAPIKey(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeAPIKeyError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAPIKeyError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrAPIKeyNotFound):
+		jsonError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, application.ErrScopeNotGranted):
+		jsonError(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, application.ErrTooManyAPIKeys):
+		jsonError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrAPIKeysUnavailable):
+		jsonError(w, err.Error(), http.StatusNotImplemented)
+	default:
+		jsonError(w, err.Error(), http.StatusBadRequest)
+	}
+}
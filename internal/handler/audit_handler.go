@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/middleware"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AuditRecorder appends events to the security audit log.
+type AuditRecorder interface {
+	Record(ctx context.Context, event model.AuditEvent)
+}
+
+type AuditServicePort interface {
+	Query(ctx context.Context, q application.AuditQuery) ([]model.AuditEvent, error)
+}
+
+type AuditHandler struct {
+	auditService AuditServicePort
+}
+
+func NewAuditHandler(auditService AuditServicePort) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditEventsHandler serves
+// GET /api/admin/audit?actor=&action=&target=&result=&since=&until=&limit=&offset=
+// where since and until are RFC 3339 timestamps.
+func (h *AuditHandler) ListAuditEventsHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	req := application.AuditQuery{
+		Action:   q.Get("action"),
+		TargetID: q.Get("target"),
+		Result:   q.Get("result"),
+	}
+	if v := q.Get("actor"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			jsonError(w, "invalid actor id", http.StatusBadRequest)
+			return
+		}
+		actor := uint(id)
+		req.ActorID = &actor
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &req.Since}, {"until", &req.Until}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				jsonError(w, p.name+" must be an RFC 3339 timestamp", http.StatusBadRequest)
+				return
+			}
+			*p.dst = t
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.Limit = n
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			req.Offset = n
+		}
+	}
+
+	events, err := h.auditService.Query(r.Context(), req)
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidAuditFilter) {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jsonError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if events == nil {
+		events = []model.AuditEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// RequestInfoMiddleware makes the client address and user agent available to the audit log.
+func RequestInfoMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := application.ContextWithRequestInfo(r.Context(), application.RequestInfo{
+			IP:        clientIP(r),
+			UserAgent: r.UserAgent(),
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// recordAdminAction audits an action the authenticated admin took on target.
+func recordAdminAction(r *http.Request, audit AuditRecorder, action, targetType, targetID string, err error) {
+	if audit == nil {
+		return
+	}
+	event := model.AuditEvent{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Result:     model.AuditResultSuccess,
+	}
+	if actorID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		event.ActorID = &actorID
+	}
+	if err != nil {
+		event.Result = model.AuditResultFailure
+		event.Detail = err.Error()
+	}
+	audit.Record(r.Context(), event)
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"backend/internal/domain/model"
+	"encoding/json"
+	"net/http"
+)
+
+// CacheStatsSource is a service with an in-memory cache.
+type CacheStatsSource interface {
+	CacheStats() model.CacheStats
+}
+
+type CacheHandler struct {
+	sources []CacheStatsSource
+}
+
+func NewCacheHandler(sources ...CacheStatsSource) *CacheHandler {
+	return &CacheHandler{sources: sources}
+}
+
+// GetCacheStatsHandler serves GET /api/admin/cache: the hit and miss counters of the latest
+// price and correlation caches.
+func (h *CacheHandler) GetCacheStatsHandler(w http.ResponseWriter, r *http.Request) {
+	stats := make([]model.CacheStats, 0, len(h.sources))
+	for _, s := range h.sources {
+		stats = append(stats, s.CacheStats())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"backend/internal/application"
 	"backend/internal/domain/model"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -11,17 +13,20 @@ import (
 )
 
 type CommodityServicePort interface {
-	GetCommodityByType(ctx context.Context, commodityType string) (*model.Commodity, error)
-	GetHistory(ctx context.Context, name string, limit int) ([]model.Commodity, error)
+	GetCommodityByType(ctx context.Context, commodityType string, opts application.PriceOptions) (*model.LatestPrice, error)
+	GetHistory(ctx context.Context, name string, limit int, interval string, opts application.PriceOptions) ([]model.Commodity, error)
 	GetStatuses(ctx context.Context) ([]model.CommodityStatus, error)
 }
 
 type CommodityHandler struct {
 	commodityService CommodityServicePort
+	preferences      PreferencesReader
 }
 
-func NewCommodityHandler(commodityService CommodityServicePort) *CommodityHandler {
-	return &CommodityHandler{commodityService: commodityService}
+// NewCommodityHandler creates the handler. preferences may be nil; the user's preferred
+// currency, unit and chart interval are then not applied.
+func NewCommodityHandler(commodityService CommodityServicePort, preferences PreferencesReader) *CommodityHandler {
+	return &CommodityHandler{commodityService: commodityService, preferences: preferences}
 }
 
 func (h *CommodityHandler) GetCommodityHandler(w http.ResponseWriter, r *http.Request) {
@@ -31,12 +36,16 @@ func (h *CommodityHandler) GetCommodityHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	commodity, err := h.commodityService.GetCommodityByType(r.Context(), commodityType)
+	commodity, err := h.commodityService.GetCommodityByType(r.Context(), commodityType, priceOptions(r, h.preferences, commodityType))
 	if err != nil {
 		if err.Error() == "unknown commodity type" {
 			jsonError(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, application.ErrFXRateUnavailable) {
+			jsonError(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 		jsonError(w, err.Error(), http.StatusBadRequest)
 		return
 	}
@@ -54,8 +63,21 @@ func (h *CommodityHandler) GetCommodityHistoryHandler(w http.ResponseWriter, r *
 		return
 	}
 
+	opts := priceOptionsFromQuery(r)
+	interval := r.URL.Query().Get("interval")
+	if prefs, ok := userPreferences(r, h.preferences); ok {
+		opts = application.PriceOptionsWithPreferences(opts, prefs, name)
+		if interval == "" {
+			interval = prefs.ChartInterval
+		}
+	}
+
 	limitStr := r.URL.Query().Get("limit")
 	limit := 100
+	// A bounded interval selects the rows, so it gets the largest page unless a limit is given.
+	if interval != "" && interval != "max" {
+		limit = 500
+	}
 	if limitStr != "" {
 		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
 			limit = l
@@ -65,9 +87,16 @@ func (h *CommodityHandler) GetCommodityHistoryHandler(w http.ResponseWriter, r *
 		limit = 500
 	}
 
-	history, err := h.commodityService.GetHistory(r.Context(), name, limit)
+	history, err := h.commodityService.GetHistory(r.Context(), name, limit, interval, opts)
 	if err != nil {
-		jsonError(w, err.Error(), http.StatusInternalServerError)
+		switch {
+		case isPriceOptionsError(err), errors.Is(err, application.ErrUnknownChartInterval):
+			jsonError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrFXRateUnavailable):
+			jsonError(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+		}
 		return
 	}
 
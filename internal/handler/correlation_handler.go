@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"backend/internal/application"
 	"backend/internal/domain/model"
 	"context"
 	"encoding/json"
+	"errors"
 	"math"
 	"net/http"
 	"strconv"
@@ -12,14 +14,17 @@ import (
 type CorrelationServicePort interface {
 	GetCorrelationByType(ctx context.Context, correlationType string) (*model.Correlation, error)
 	GetHistory(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.Correlation, error)
+	GetEvents(ctx context.Context, commodityA, commodityB string, limit int) ([]*model.CorrelationEvent, error)
+	RecomputeCorrelation(ctx context.Context, commodityA, commodityB string) (*model.Correlation, error)
 }
 
 type CorrelationHandler struct {
 	correlationService CorrelationServicePort
+	audit              AuditRecorder
 }
 
-func NewCorrelationHandler(correlationService CorrelationServicePort) *CorrelationHandler {
-	return &CorrelationHandler{correlationService: correlationService}
+func NewCorrelationHandler(correlationService CorrelationServicePort, audit AuditRecorder) *CorrelationHandler {
+	return &CorrelationHandler{correlationService: correlationService, audit: audit}
 }
 
 func (h *CorrelationHandler) GetCorrelationHandler(w http.ResponseWriter, r *http.Request) {
@@ -87,6 +92,71 @@ func (h *CorrelationHandler) GetCorrelationHistoryHandler(w http.ResponseWriter,
 	}
 }
 
+// GetCorrelationEventsHandler serves GET /api/correlation/events?a=gold&b=copper&limit=50.
+// Without a pair, the latest events across all pairs are returned.
+func (h *CorrelationHandler) GetCorrelationEventsHandler(w http.ResponseWriter, r *http.Request) {
+	commodityA := r.URL.Query().Get("a")
+	commodityB := r.URL.Query().Get("b")
+
+	if (commodityA == "") != (commodityB == "") {
+		jsonError(w, "'a' and 'b' query parameters must be provided together", http.StatusBadRequest)
+		return
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 100
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	events, err := h.correlationService.GetEvents(r.Context(), commodityA, commodityB, limit)
+	if err != nil {
+		jsonError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if events == nil {
+		events = []*model.CorrelationEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(events); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// RecomputeCorrelationHandler serves POST /api/admin/correlation/recompute?a=gold&b=silver
+func (h *CorrelationHandler) RecomputeCorrelationHandler(w http.ResponseWriter, r *http.Request) {
+	commodityA := r.URL.Query().Get("a")
+	commodityB := r.URL.Query().Get("b")
+
+	correlation, err := h.correlationService.RecomputeCorrelation(r.Context(), commodityA, commodityB)
+	recordAdminAction(r, h.audit, model.AuditCorrelationRecompute, "correlation", commodityA+"-"+commodityB, err)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrInvalidCorrelationPair):
+			jsonError(w, "'a' and 'b' query parameters are required", http.StatusBadRequest)
+		case errors.Is(err, application.ErrInsufficientCorrelationData):
+			jsonError(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	sanitizeCorrelation(correlation)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(correlation); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
 func sanitizeCorrelation(c *model.Correlation) {
 	if c == nil {
 		return
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"backend/internal/domain/model"
+	"time"
+)
+
+// AccountExportResponse is the personal data archive of a user.
+type AccountExportResponse struct {
+	ExportedAt       time.Time             `json:"exported_at"`
+	Profile          AccountProfile        `json:"profile"`
+	Preferences      model.UserPreferences `json:"preferences"`
+	TwoFactorEnabled bool                  `json:"two_factor_enabled"`
+	Sessions         []model.Session       `json:"sessions"`
+	APIKeys          []model.APIKey        `json:"api_keys"`
+	Identities       []model.UserIdentity  `json:"identities"`
+	LoginAttempts    []model.LoginAttempt  `json:"login_attempts"`
+	AuditEvents      []model.AuditEvent    `json:"audit_events"`
+}
+
+// AccountProfile is the exported account itself. It never includes the password hash.
+type AccountProfile struct {
+	ID                  uint       `json:"id"`
+	Username            string     `json:"username"`
+	Email               string     `json:"email"`
+	EmailVerified       bool       `json:"email_verified"`
+	Role                string     `json:"role"`
+	CreatedAt           time.Time  `json:"created_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	Password    string `json:"password"`
+	ReauthToken string `json:"reauth_token"`
+}
+
+type DeleteAccountResponse struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
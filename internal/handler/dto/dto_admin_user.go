@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"backend/internal/domain/model"
+	"time"
+)
+
+// AdminUserResponse is a user as shown to admins. It never includes the password hash.
+type AdminUserResponse struct {
+	ID                    uint      `json:"id"`
+	Username              string    `json:"username"`
+	Email                 string    `json:"email"`
+	Role                  string    `json:"role"`
+	EmailVerified         bool      `json:"email_verified"`
+	Disabled              bool      `json:"disabled"`
+	PasswordResetRequired bool      `json:"password_reset_required"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+type AdminUserListResponse struct {
+	Users    []AdminUserResponse `json:"users"`
+	Total    int                 `json:"total"`
+	Page     int                 `json:"page"`
+	PageSize int                 `json:"page_size"`
+}
+
+type AdminUserDetailsResponse struct {
+	AdminUserResponse
+	TwoFactorEnabled bool            `json:"two_factor_enabled"`
+	Sessions         []model.Session `json:"sessions"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role"`
+}
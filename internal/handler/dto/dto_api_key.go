@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"backend/internal/domain/model"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RateLimit int        `json:"rate_limit,omitempty"` // requests per minute
+}
+
+// CreateAPIKeyResponse is the only response that includes the full key.
+type CreateAPIKeyResponse struct {
+	Key string `json:"key"`
+	model.APIKey
+}
@@ -1,7 +1,17 @@
 package dto
 
+import (
+	"backend/internal/domain/model"
+	"time"
+)
+
 type MeResponse struct{
 	Username string `json:"username"`
 	Email    string `json:"email"`
 	UserID   uint   `json:"user_id"`
-}
\ No newline at end of file
+	EmailVerified bool `json:"email_verified"`
+	Role          string                 `json:"role"`
+	CreatedAt     time.Time              `json:"created_at"`
+	Preferences   *model.UserPreferences `json:"preferences,omitempty"`
+	DeletionScheduledAt *time.Time       `json:"deletion_scheduled_at,omitempty"`
+}
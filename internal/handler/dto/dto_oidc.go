@@ -0,0 +1,5 @@
+package dto
+
+type OIDCAuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
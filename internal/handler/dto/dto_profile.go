@@ -0,0 +1,30 @@
+package dto
+
+// UpdatePreferencesRequest is a partial update: omitted fields are left alone and an empty
+// string restores the default.
+type UpdatePreferencesRequest struct {
+	DisplayName   *string `json:"display_name"`
+	Timezone      *string `json:"timezone"`
+	Currency      *string `json:"currency"`
+	Unit          *string `json:"unit"`
+	ChartInterval *string `json:"chart_interval"`
+	Theme         *string `json:"theme"`
+}
+
+// ChangeEmailRequest, ChangeUsernameRequest and DeleteAccountRequest take the password or,
+// for accounts without one, the reauth_token from signing in again at a linked provider.
+type ChangeEmailRequest struct {
+	Password    string `json:"password"`
+	ReauthToken string `json:"reauth_token"`
+	NewEmail    string `json:"new_email"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token"`
+}
+
+type ChangeUsernameRequest struct {
+	Password    string `json:"password"`
+	ReauthToken string `json:"reauth_token"`
+	NewUsername string `json:"new_username"`
+}
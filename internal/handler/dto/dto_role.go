@@ -0,0 +1,5 @@
+package dto
+
+type SaveRoleRequest struct {
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type ForecastServicePort interface {
+	Forecast(ctx context.Context, name, modelName string, horizon int, opts application.PriceOptions) (*model.ForecastReport, error)
+}
+
+type ForecastHandler struct {
+	forecastService ForecastServicePort
+	preferences     PreferencesReader
+}
+
+// NewForecastHandler creates the handler. preferences may be nil; the user's preferred
+// currency and unit are then not applied.
+func NewForecastHandler(forecastService ForecastServicePort, preferences PreferencesReader) *ForecastHandler {
+	return &ForecastHandler{forecastService: forecastService, preferences: preferences}
+}
+
+// GetForecastHandler serves GET /api/commodity/{name}/forecast?model=naive|holt_winters|ar&horizon=30&currency=EUR
+func (h *ForecastHandler) GetForecastHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if name == "" {
+		jsonError(w, "commodity name is required", http.StatusBadRequest)
+		return
+	}
+
+	horizon := application.DefaultForecastHorizon
+	if horizonStr := r.URL.Query().Get("horizon"); horizonStr != "" {
+		n, err := strconv.Atoi(horizonStr)
+		if err != nil || n <= 0 || n > application.MaxForecastHorizon {
+			jsonError(w, "'horizon' must be between 1 and "+strconv.Itoa(application.MaxForecastHorizon), http.StatusBadRequest)
+			return
+		}
+		horizon = n
+	}
+
+	report, err := h.forecastService.Forecast(r.Context(), name, r.URL.Query().Get("model"), horizon, priceOptions(r, h.preferences, name))
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrUnknownCommodity):
+			jsonError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, application.ErrUnknownForecastModel), isPriceOptionsError(err):
+			jsonError(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, application.ErrInsufficientHistory):
+			jsonError(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, application.ErrFXRateUnavailable):
+			jsonError(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -1,7 +1,15 @@
 package handler
 
 import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/domain/units"
+	"backend/internal/middleware"
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
+	"net"
 	"net/http"
 )
 
@@ -11,3 +19,67 @@ func jsonError(w http.ResponseWriter, msg string, code int) {
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(map[string]string{"error": msg})
 }
+
+// jsonErrorCode sends an error response with a stable machine-readable code next to the message.
+func jsonErrorCode(w http.ResponseWriter, msg, code string, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg, "code": code})
+}
+
+// priceOptionsFromQuery reads the price presentation parameters (?currency=EUR&unit=oz) from the query.
+func priceOptionsFromQuery(r *http.Request) application.PriceOptions {
+	return application.PriceOptions{
+		Currency: r.URL.Query().Get("currency"),
+		Unit:     r.URL.Query().Get("unit"),
+	}
+}
+
+// PreferencesReader supplies the signed-in user's defaults to the commodity endpoints.
+type PreferencesReader interface {
+	GetPreferences(ctx context.Context, userID uint) (model.UserPreferences, error)
+}
+
+// userPreferences loads the preferences of the signed-in user. It reports false when there
+// is no user or the preferences cannot be loaded; the server defaults apply then.
+func userPreferences(r *http.Request, prefs PreferencesReader) (model.UserPreferences, bool) {
+	if prefs == nil {
+		return model.UserPreferences{}, false
+	}
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		return model.UserPreferences{}, false
+	}
+	p, err := prefs.GetPreferences(r.Context(), userID)
+	if err != nil {
+		log.Printf("Error loading preferences of user %d: %v", userID, err)
+		return model.UserPreferences{}, false
+	}
+	return p, true
+}
+
+// priceOptions reads the price options from the query and fills the missing ones from the
+// user's preferences.
+func priceOptions(r *http.Request, prefs PreferencesReader, commodity string) application.PriceOptions {
+	opts := priceOptionsFromQuery(r)
+	if p, ok := userPreferences(r, prefs); ok {
+		opts = application.PriceOptionsWithPreferences(opts, p, commodity)
+	}
+	return opts
+}
+
+// isPriceOptionsError reports whether err was caused by an invalid currency or unit parameter.
+func isPriceOptionsError(err error) bool {
+	return errors.Is(err, application.ErrUnsupportedCurrency) ||
+		errors.Is(err, units.ErrUnknownUnit) ||
+		errors.Is(err, units.ErrIncompatibleUnit)
+}
+
+// clientIP returns the address of the client that sent the request, without the port.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"backend/internal/auth"
+	"encoding/json"
+	"net/http"
+)
+
+type JWKSHandler struct {
+	keys *auth.KeySet
+}
+
+func NewJWKSHandler(keys *auth.KeySet) *JWKSHandler {
+	return &JWKSHandler{keys: keys}
+}
+
+// GetJWKSHandler serves GET /.well-known/jwks.json so other services can verify access
+// tokens with the public keys.
+func (h *JWKSHandler) GetJWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	if err := json.NewEncoder(w).Encode(h.keys.JWKS()); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	// oidcCookiePath limits the state cookie to the callback routes.
+	oidcCookiePath = "/api/oauth"
+)
+
+type OIDCServicePort interface {
+	IdentityProviderNames() []string
+	StartOIDCLogin(ctx context.Context, provider string, linkUserID uint) (application.OIDCAuthorization, error)
+	StartOIDCReauthentication(ctx context.Context, provider string, userID uint) (application.OIDCAuthorization, error)
+	CompleteOIDCLogin(ctx context.Context, req application.OIDCCallbackInput) (application.OIDCLoginResult, error)
+	ListIdentities(ctx context.Context, userID uint) ([]model.UserIdentity, error)
+}
+
+// OIDCHandler serves sign-in with external OpenID Connect providers. The browser is
+// redirected to the provider and back, then on to the frontend at appBaseURL.
+type OIDCHandler struct {
+	oidcService  OIDCServicePort
+	cookieSecure bool
+	appBaseURL   string
+}
+
+func NewOIDCHandler(oidcService OIDCServicePort, cookieSecure bool, appBaseURL string) *OIDCHandler {
+	return &OIDCHandler{oidcService: oidcService, cookieSecure: cookieSecure, appBaseURL: strings.TrimRight(appBaseURL, "/")}
+}
+
+// ListProvidersHandler serves GET /api/oauth/providers
+func (h *OIDCHandler) ListProvidersHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.oidcService.IdentityProviderNames())
+}
+
+// LoginHandler serves GET /api/oauth/{provider}/login, redirecting to the provider.
+func (h *OIDCHandler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	authz, err := h.oidcService.StartOIDCLogin(r.Context(), chi.URLParam(r, "provider"), 0)
+	if err != nil {
+		log.Printf("OIDC login start error: %v", err)
+		h.redirectWithError(w, r, err)
+		return
+	}
+	h.setStateCookie(w, authz)
+	http.Redirect(w, r, authz.URL, http.StatusFound)
+}
+
+// LinkIdentityHandler serves POST /api/user/identities/{provider}. It answers with the
+// provider URL the frontend must navigate to; the identity is linked on the way back.
+func (h *OIDCHandler) LinkIdentityHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	authz, err := h.oidcService.StartOIDCLogin(r.Context(), chi.URLParam(r, "provider"), userID)
+	if err != nil {
+		if errors.Is(err, application.ErrUnknownIdentityProvider) {
+			jsonError(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		jsonError(w, "identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+	h.setStateCookie(w, authz)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.OIDCAuthorizationResponse{AuthorizationURL: authz.URL})
+}
+
+// ReauthenticateHandler serves POST /api/user/reauthenticate/{provider}. Like linking, it
+// answers with the provider URL; the callback hands the frontend a reauth token that stands
+// in for the password when changing the email or username or deleting the account.
+func (h *OIDCHandler) ReauthenticateHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	authz, err := h.oidcService.StartOIDCReauthentication(r.Context(), chi.URLParam(r, "provider"), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrUnknownIdentityProvider):
+			jsonError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, application.ErrIdentityNotLinked):
+			jsonError(w, err.Error(), http.StatusConflict)
+		default:
+			jsonError(w, "identity provider unavailable", http.StatusBadGateway)
+		}
+		return
+	}
+	h.setStateCookie(w, authz)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.OIDCAuthorizationResponse{AuthorizationURL: authz.URL})
+}
+
+// CallbackHandler serves GET /api/oauth/{provider}/callback, where the provider sends the
+// browser back after the login.
+func (h *OIDCHandler) CallbackHandler(w http.ResponseWriter, r *http.Request) {
+	stateToken := ""
+	if cookie, err := r.Cookie(oidcStateCookie); err == nil {
+		stateToken = cookie.Value
+	}
+	h.clearStateCookie(w)
+
+	q := r.URL.Query()
+	if providerErr := q.Get("error"); providerErr != "" {
+		log.Printf("OIDC provider returned error %s: %s", providerErr, q.Get("error_description"))
+		http.Redirect(w, r, h.appURL("/login", url.Values{"error": {"provider_denied"}}), http.StatusFound)
+		return
+	}
+
+	result, err := h.oidcService.CompleteOIDCLogin(r.Context(), application.OIDCCallbackInput{
+		Provider:   chi.URLParam(r, "provider"),
+		Code:       q.Get("code"),
+		State:      q.Get("state"),
+		StateToken: stateToken,
+		IP:         clientIP(r),
+		UserAgent:  r.UserAgent(),
+	})
+	if err != nil {
+		log.Printf("OIDC login error: %v", err)
+		h.redirectWithError(w, r, err)
+		return
+	}
+
+	switch {
+	case result.Linked:
+		http.Redirect(w, r, h.appURL("/settings/security", url.Values{"linked": {chi.URLParam(r, "provider")}}), http.StatusFound)
+	case result.ReauthToken != "":
+		http.Redirect(w, r, h.appURL("/settings/account", nil)+"#reauth_token="+url.QueryEscape(result.ReauthToken), http.StatusFound)
+	case result.Auth.TwoFactorChallenge != "":
+		// The fragment keeps the challenge out of server logs and Referer headers.
+		http.Redirect(w, r, h.appURL("/login", nil)+"#two_factor_challenge="+url.QueryEscape(result.Auth.TwoFactorChallenge), http.StatusFound)
+	default:
+		setAuthCookies(w, result.Auth, h.cookieSecure)
+		http.Redirect(w, r, h.appURL("/", nil), http.StatusFound)
+	}
+}
+
+// ListIdentitiesHandler serves GET /api/user/identities
+func (h *OIDCHandler) ListIdentitiesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	identities, err := h.oidcService.ListIdentities(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, application.ErrUnknownIdentityProvider) {
+			jsonError(w, "external sign-in is not enabled", http.StatusNotImplemented)
+			return
+		}
+		jsonError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if identities == nil {
+		identities = []model.UserIdentity{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(identities)
+}
+
+func (h *OIDCHandler) setStateCookie(w http.ResponseWriter, authz application.OIDCAuthorization) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    authz.StateToken,
+		Path:     oidcCookiePath,
+		HttpOnly: true,
+		Secure:   h.cookieSecure,
+		// Lax, so the cookie comes along on the top-level redirect back from the provider.
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(time.Until(authz.ExpiresAt).Seconds()),
+	})
+}
+
+func (h *OIDCHandler) clearStateCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    "",
+		Path:     oidcCookiePath,
+		HttpOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+	})
+}
+
+// redirectWithError sends the browser back to the frontend login page with an error code
+// it can display.
+func (h *OIDCHandler) redirectWithError(w http.ResponseWriter, r *http.Request, err error) {
+	http.Redirect(w, r, h.appURL("/login", url.Values{"error": {oidcErrorCode(err)}}), http.StatusFound)
+}
+
+func (h *OIDCHandler) appURL(path string, query url.Values) string {
+	u := h.appBaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	return u
+}
+
+func oidcErrorCode(err error) string {
+	switch {
+	case errors.Is(err, application.ErrUnknownIdentityProvider):
+		return "unknown_provider"
+	case errors.Is(err, application.ErrInvalidOIDCState):
+		return "invalid_state"
+	case errors.Is(err, application.ErrIdentityEmailRequired):
+		return "email_required"
+	case errors.Is(err, application.ErrIdentityEmailConflict):
+		return "email_conflict"
+	case errors.Is(err, application.ErrIdentityAlreadyLinked), errors.Is(err, application.ErrProviderAlreadyLinked):
+		return "already_linked"
+	case errors.Is(err, application.ErrIdentityNotLinked):
+		return "not_linked"
+	case errors.Is(err, application.ErrAccountDisabled):
+		return "account_disabled"
+	default:
+		return "login_failed"
+	}
+}
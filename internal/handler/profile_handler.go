@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// GetPreferencesHandler serves GET /api/me/preferences
+func (h *UserHandler) GetPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.userService.GetPreferences(r.Context(), userID)
+	if err != nil {
+		jsonError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// UpdatePreferencesHandler serves PATCH /api/me/preferences
+func (h *UserHandler) UpdatePreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	prefs, err := h.userService.UpdatePreferences(r.Context(), userID, application.PreferencesUpdate{
+		DisplayName:   req.DisplayName,
+		Timezone:      req.Timezone,
+		Currency:      req.Currency,
+		Unit:          req.Unit,
+		ChartInterval: req.ChartInterval,
+		Theme:         req.Theme,
+	})
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(prefs)
+}
+
+// ChangeEmailHandler serves POST /api/me/email. The password (or a reauth token) is asked
+// again; when account emails are enabled the change completes through the link sent to the
+// new address.
+func (h *UserHandler) ChangeEmailHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ChangeEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pending, err := h.userService.ChangeEmail(r.Context(), application.ChangeEmailInput{
+		UserID:      userID,
+		Password:    req.Password,
+		ReauthToken: req.ReauthToken,
+		NewEmail:    req.NewEmail,
+	})
+	if err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if pending {
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]string{"message": "confirmation link sent to the new address"})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"message": "email updated"})
+}
+
+// ConfirmEmailChangeHandler serves POST /api/me/email/confirm with the token from the
+// emailed link.
+func (h *UserHandler) ConfirmEmailChangeHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.ConfirmEmailChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.ConfirmEmailChange(r.Context(), req.Token); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "email updated"})
+}
+
+// ChangeUsernameHandler serves POST /api/me/username
+func (h *UserHandler) ChangeUsernameHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ChangeUsernameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.userService.ChangeUsername(r.Context(), application.ChangeUsernameInput{
+		UserID:      userID,
+		Password:    req.Password,
+		ReauthToken: req.ReauthToken,
+		NewUsername: req.NewUsername,
+	}); err != nil {
+		writeProfileError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "username updated"})
+}
+
+func writeProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrIncorrectPassword), errors.Is(err, application.ErrReauthenticationFailed):
+		jsonErrorCode(w, err.Error(), "reauthentication_failed", http.StatusForbidden)
+	case errors.Is(err, application.ErrUsernameTaken), errors.Is(err, application.ErrEmailTaken):
+		jsonError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrPreferencesUnavailable):
+		jsonError(w, err.Error(), http.StatusNotImplemented)
+	default:
+		writeAccountEmailError(w, err)
+	}
+}
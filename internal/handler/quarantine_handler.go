@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/middleware"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type QuarantineServicePort interface {
+	ListQuarantined(ctx context.Context, status string, limit int) ([]model.QuarantinedPrice, error)
+	ApproveQuarantined(ctx context.Context, id int64, reviewerID uint) error
+	RejectQuarantined(ctx context.Context, id int64, reviewerID uint) error
+}
+
+type QuarantineHandler struct {
+	quarantineService QuarantineServicePort
+	audit             AuditRecorder
+}
+
+func NewQuarantineHandler(quarantineService QuarantineServicePort, audit AuditRecorder) *QuarantineHandler {
+	return &QuarantineHandler{quarantineService: quarantineService, audit: audit}
+}
+
+// ListQuarantineHandler serves GET /api/admin/quarantine?status=pending&limit=100
+func (h *QuarantineHandler) ListQuarantineHandler(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = model.QuarantineStatusPending
+	} else if status == "all" {
+		status = ""
+	}
+
+	limit := 100
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 500 {
+		limit = l
+	}
+
+	prices, err := h.quarantineService.ListQuarantined(r.Context(), status, limit)
+	if err != nil {
+		if errors.Is(err, application.ErrInvalidQuarantineStatus) {
+			jsonError(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jsonError(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if prices == nil {
+		prices = []model.QuarantinedPrice{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(prices); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// ApproveQuarantineHandler serves POST /api/admin/quarantine/{id}/approve
+func (h *QuarantineHandler) ApproveQuarantineHandler(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.quarantineService.ApproveQuarantined, model.AuditQuarantineApprove, "price approved")
+}
+
+// RejectQuarantineHandler serves POST /api/admin/quarantine/{id}/reject
+func (h *QuarantineHandler) RejectQuarantineHandler(w http.ResponseWriter, r *http.Request) {
+	h.review(w, r, h.quarantineService.RejectQuarantined, model.AuditQuarantineReject, "price rejected")
+}
+
+func (h *QuarantineHandler) review(w http.ResponseWriter, r *http.Request, decide func(context.Context, int64, uint) error, action, message string) {
+	reviewerID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		jsonError(w, "invalid quarantine id", http.StatusBadRequest)
+		return
+	}
+
+	err = decide(r.Context(), id, reviewerID)
+	recordAdminAction(r, h.audit, action, "quarantined_price", strconv.FormatInt(id, 10), err)
+	if err != nil {
+		switch {
+		case errors.Is(err, application.ErrQuarantineNotFound):
+			jsonError(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, application.ErrQuarantineAlreadyReviewed):
+			jsonError(w, err.Error(), http.StatusConflict)
+		default:
+			jsonError(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
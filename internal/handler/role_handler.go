@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/handler/dto"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type RoleServicePort interface {
+	ListPermissions() []string
+	ListRoles(ctx context.Context) ([]model.Role, error)
+	SaveRole(ctx context.Context, name string, permissions []string) (model.Role, error)
+	DeleteRole(ctx context.Context, name string) error
+}
+
+type RoleHandler struct {
+	roleService RoleServicePort
+	audit       AuditRecorder
+}
+
+func NewRoleHandler(roleService RoleServicePort, audit AuditRecorder) *RoleHandler {
+	return &RoleHandler{roleService: roleService, audit: audit}
+}
+
+// ListPermissionsHandler serves GET /api/admin/permissions
+func (h *RoleHandler) ListPermissionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.roleService.ListPermissions()); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// ListRolesHandler serves GET /api/admin/roles
+func (h *RoleHandler) ListRolesHandler(w http.ResponseWriter, r *http.Request) {
+	roles, err := h.roleService.ListRoles(r.Context())
+	if err != nil {
+		writeRoleError(w, err)
+		return
+	}
+	if roles == nil {
+		roles = []model.Role{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(roles); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// SaveRoleHandler serves PUT /api/admin/roles/{name}, creating the role or replacing its
+// permissions.
+func (h *RoleHandler) SaveRoleHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.SaveRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	role, err := h.roleService.SaveRole(r.Context(), name, req.Permissions)
+	recordAdminAction(r, h.audit, model.AuditRoleSave, "role", name, err)
+	if err != nil {
+		writeRoleError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(role); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// DeleteRoleHandler serves DELETE /api/admin/roles/{name}
+func (h *RoleHandler) DeleteRoleHandler(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	err := h.roleService.DeleteRole(r.Context(), name)
+	recordAdminAction(r, h.audit, model.AuditRoleDelete, "role", name, err)
+	if err != nil {
+		writeRoleError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeRoleError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrRoleNotFound):
+		jsonError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, application.ErrBuiltInRole), errors.Is(err, application.ErrAdminRoleLocked), errors.Is(err, application.ErrRoleInUse):
+		jsonError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrInvalidRoleName), errors.Is(err, application.ErrUnknownPermission):
+		jsonError(w, err.Error(), http.StatusBadRequest)
+	default:
+		jsonError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/domain/model"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListSessionsHandler serves GET /api/user/sessions
+func (h *UserHandler) ListSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	currentID, _ := middleware.GetSessionIDFromContext(r.Context())
+
+	sessions, err := h.userService.ListSessions(r.Context(), userID, currentID)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+	if sessions == nil {
+		sessions = []model.Session{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(sessions); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
+
+// RevokeSessionHandler serves DELETE /api/user/sessions/{id}
+func (h *UserHandler) RevokeSessionHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.userService.RevokeSession(r.Context(), userID, chi.URLParam(r, "id")); err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeOtherSessionsHandler serves DELETE /api/user/sessions and signs the user out of
+// every session but the one making the request.
+func (h *UserHandler) RevokeOtherSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	currentID, ok := middleware.GetSessionIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "current session unknown, please log in again", http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := h.userService.RevokeOtherSessions(r.Context(), userID, currentID)
+	if err != nil {
+		writeSessionError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]int{"revoked": revoked})
+}
+
+func writeSessionError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrSessionNotFound):
+		jsonError(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, application.ErrSessionsDisabled):
+		jsonError(w, err.Error(), http.StatusNotImplemented)
+	default:
+		jsonError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type StorageServicePort interface {
+	ListPartitions(ctx context.Context) ([]model.Partition, error)
+}
+
+type StorageHandler struct {
+	storageService StorageServicePort
+}
+
+func NewStorageHandler(storageService StorageServicePort) *StorageHandler {
+	return &StorageHandler{storageService: storageService}
+}
+
+// ListPartitionsHandler serves GET /api/admin/partitions: the monthly partitions of the
+// price and correlation tables with their estimated rows and on-disk size.
+func (h *StorageHandler) ListPartitionsHandler(w http.ResponseWriter, r *http.Request) {
+	partitions, err := h.storageService.ListPartitions(r.Context())
+	if err != nil {
+		jsonError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if partitions == nil {
+		partitions = []model.Partition{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(partitions); err != nil {
+		jsonError(w, "failed to encode response", http.StatusInternalServerError)
+	}
+}
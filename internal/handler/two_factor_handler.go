@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"backend/internal/application"
+	"backend/internal/handler/dto"
+	"backend/internal/middleware"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// EnrollTwoFactorHandler serves POST /api/user/2fa/enroll
+func (h *UserHandler) EnrollTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	enrollment, err := h.userService.EnrollTwoFactor(r.Context(), userID)
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.TwoFactorEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	})
+}
+
+// VerifyTwoFactorHandler serves POST /api/user/2fa/verify and activates a pending enrolment.
+func (h *UserHandler) VerifyTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.TwoFactorVerifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	codes, err := h.userService.ConfirmTwoFactor(r.Context(), userID, req.Code)
+	if err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.TwoFactorVerifyResponse{RecoveryCodes: codes})
+}
+
+// TwoFactorLoginHandler serves POST /api/login/2fa, the second step of a two-factor login.
+func (h *UserHandler) TwoFactorLoginHandler(w http.ResponseWriter, r *http.Request) {
+	var req dto.TwoFactorLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		jsonError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, tokens, err := h.userService.CompleteTwoFactorLogin(r.Context(), application.TwoFactorLoginInput{
+		ChallengeToken: req.ChallengeToken,
+		Code:           req.Code,
+		IP:             clientIP(r),
+		UserAgent:      r.UserAgent(),
+	})
+	if err != nil {
+		var throttled *application.LoginThrottledError
+		if errors.As(err, &throttled) {
+			writeLoginError(w, err)
+			return
+		}
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	h.setAuthCookies(w, tokens)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(dto.LoginResponse{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	})
+}
+
+// ResetTwoFactorHandler serves POST /api/admin/users/{id}/2fa/reset
+func (h *UserHandler) ResetTwoFactorHandler(w http.ResponseWriter, r *http.Request) {
+	actorID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		jsonError(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	id, ok := userIDParam(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.userService.ResetTwoFactor(r.Context(), actorID, id); err != nil {
+		writeTwoFactorError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeTwoFactorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, application.ErrInvalidTwoFactorCode), errors.Is(err, application.ErrInvalidChallenge):
+		jsonError(w, err.Error(), http.StatusUnauthorized)
+	case errors.Is(err, application.ErrTwoFactorAlreadyEnabled):
+		jsonError(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, application.ErrTwoFactorNotEnrolled):
+		jsonError(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, application.ErrTwoFactorUnavailable):
+		jsonError(w, err.Error(), http.StatusNotImplemented)
+	case err.Error() == "user not found":
+		jsonError(w, err.Error(), http.StatusNotFound)
+	default:
+		jsonError(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -8,17 +8,57 @@ import (
 	"backend/internal/middleware"
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	accessTokenCookie  = "access_token"
+	refreshTokenCookie = "refresh_token"
+	// refreshTokenPath limits the refresh cookie to API calls so it is not sent with static assets.
+	refreshTokenPath = "/api"
 )
 
 // UserServicePort defines the contract the handler depends on.
 type UserServicePort interface {
-	Login(ctx context.Context, req application.LoginInput) (model.User, string, error)
+	Login(ctx context.Context, req application.LoginInput) (model.User, application.AuthResult, error)
 	Register(ctx context.Context, req application.RegisterInput) error
 	FindByID(ctx context.Context, id uint) (model.User, error)
-	RefreshToken(tokenString string) (string, error)
+	RefreshToken(ctx context.Context, refreshToken, ip string) (application.AuthResult, error)
+	Logout(ctx context.Context, refreshToken string) error
 	ChangePassword(ctx context.Context, req application.ChangePasswordInput) error
+	ListSessions(ctx context.Context, userID uint, currentID string) ([]model.Session, error)
+	RevokeSession(ctx context.Context, userID uint, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID uint, currentID string) (int, error)
+	EnrollTwoFactor(ctx context.Context, userID uint) (application.TwoFactorEnrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userID uint, code string) ([]string, error)
+	CompleteTwoFactorLogin(ctx context.Context, req application.TwoFactorLoginInput) (model.User, application.AuthResult, error)
+	ResetTwoFactor(ctx context.Context, actorID, userID uint) error
+	SendVerificationEmail(ctx context.Context, userID uint) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	ListUsers(ctx context.Context, req application.UserListInput) (application.UserPage, error)
+	GetUserDetails(ctx context.Context, id uint) (application.UserDetails, error)
+	ChangeUserRole(ctx context.Context, actorID, id uint, role string) error
+	SetUserDisabled(ctx context.Context, actorID, id uint, disabled bool) error
+	ForcePasswordReset(ctx context.Context, actorID, id uint) error
+	DeleteUser(ctx context.Context, actorID, id uint) error
+	CreateAPIKey(ctx context.Context, userID uint, req application.CreateAPIKeyInput) (string, model.APIKey, error)
+	ListAPIKeys(ctx context.Context, userID uint) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID uint, id string) error
+	GetPreferences(ctx context.Context, userID uint) (model.UserPreferences, error)
+	UpdatePreferences(ctx context.Context, userID uint, req application.PreferencesUpdate) (model.UserPreferences, error)
+	ChangeEmail(ctx context.Context, req application.ChangeEmailInput) (bool, error)
+	ConfirmEmailChange(ctx context.Context, token string) error
+	ChangeUsername(ctx context.Context, req application.ChangeUsernameInput) error
+	ExportAccountData(ctx context.Context, userID uint) (application.AccountExport, error)
+	RequestAccountDeletion(ctx context.Context, userID uint, password, reauthToken string) (time.Time, error)
+	CancelAccountDeletion(ctx context.Context, userID uint) error
 }
 
 type UserHandler struct {
@@ -69,25 +109,29 @@ func (h *UserHandler) LoginUserHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, token, err := h.userService.Login(r.Context(), application.LoginInput{
+	user, tokens, err := h.userService.Login(r.Context(), application.LoginInput{
 		Identifier: req.Identifier,
 		Password:   req.Password,
+		IP:         clientIP(r),
+		UserAgent:  r.UserAgent(),
 	})
 	if err != nil {
 		log.Printf("Login error: %v", err)
-		jsonError(w, err.Error(), http.StatusBadRequest)
+		writeLoginError(w, err)
 		return
 	}
 
-	http.SetCookie(w, &http.Cookie{
-		Name:     "access_token",
-		Value:    token,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   h.cookieSecure,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   3600,
-	})
+	if tokens.TwoFactorChallenge != "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(dto.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    tokens.TwoFactorChallenge,
+		})
+		return
+	}
+
+	h.setAuthCookies(w, tokens)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -99,15 +143,12 @@ func (h *UserHandler) LoginUserHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) LogoutUserHandler(w http.ResponseWriter, r *http.Request) {
-	http.SetCookie(w, &http.Cookie{
-		Name:     "access_token",
-		Value:    "",
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   h.cookieSecure,
-		SameSite: http.SameSiteLaxMode,
-		MaxAge:   -1,
-	})
+	if cookie, err := r.Cookie(refreshTokenCookie); err == nil {
+		if err := h.userService.Logout(r.Context(), cookie.Value); err != nil {
+			log.Printf("Logout error: %v", err)
+		}
+	}
+	h.clearAuthCookies(w)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -123,13 +164,23 @@ func (h *UserHandler) MeHandler(w http.ResponseWriter, r *http.Request) {
 		jsonError(w, "user not found", http.StatusNotFound)
 		return
 	}
+	var preferences *model.UserPreferences
+	if prefs, ok := userPreferences(r, h.userService); ok {
+		preferences = &prefs
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(dto.MeResponse{
-		UserID:   user.ID,
-		Username: user.Username,
-		Email:    user.Email,
+		UserID:        user.ID,
+		Username:      user.Username,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Role:          user.Role,
+		CreatedAt:     user.CreatedAt,
+		Preferences:   preferences,
+		// Set while the account waits for deletion, so the frontend can offer to cancel it.
+		DeletionScheduledAt: user.DeletionScheduledAt,
 	})
 }
 
@@ -174,7 +225,7 @@ func (h *UserHandler) RefreshJWTokenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	cookie, err := r.Cookie("access_token")
+	cookie, err := r.Cookie(refreshTokenCookie)
 	if err != nil {
 		if err == http.ErrNoCookie {
 			jsonError(w, "unauthorized", http.StatusUnauthorized)
@@ -184,23 +235,95 @@ func (h *UserHandler) RefreshJWTokenHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	newToken, err := h.userService.RefreshToken(cookie.Value)
+	tokens, err := h.userService.RefreshToken(r.Context(), cookie.Value, clientIP(r))
 	if err != nil {
+		if errors.Is(err, application.ErrRefreshTokenReused) {
+			log.Printf("Refresh error: %v", err)
+		}
+		h.clearAuthCookies(w)
 		jsonError(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
+	h.setAuthCookies(w, tokens)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]string{"message": "token refreshed successfully"})
+}
+
+// writeLoginError reports a failed login with an error code clients can branch on.
+// Throttled logins carry a Retry-After header.
+func writeLoginError(w http.ResponseWriter, err error) {
+	var throttled *application.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		if errors.Is(err, application.ErrAccountLocked) {
+			jsonErrorCode(w, err.Error(), "account_locked", http.StatusLocked)
+		} else {
+			jsonErrorCode(w, err.Error(), "too_many_attempts", http.StatusTooManyRequests)
+		}
+	case errors.Is(err, application.ErrInvalidCredentials):
+		jsonErrorCode(w, err.Error(), "invalid_credentials", http.StatusBadRequest)
+	case errors.Is(err, application.ErrAccountDisabled):
+		jsonErrorCode(w, err.Error(), "account_disabled", http.StatusForbidden)
+	case errors.Is(err, application.ErrPasswordResetRequired):
+		jsonErrorCode(w, err.Error(), "password_reset_required", http.StatusForbidden)
+	case errors.Is(err, application.ErrEmailNotVerified):
+		jsonErrorCode(w, err.Error(), "email_not_verified", http.StatusForbidden)
+	default:
+		jsonErrorCode(w, err.Error(), "invalid_request", http.StatusBadRequest)
+	}
+}
+
+func (h *UserHandler) setAuthCookies(w http.ResponseWriter, tokens application.AuthResult) {
+	setAuthCookies(w, tokens, h.cookieSecure)
+}
+
+// setAuthCookies stores the tokens of a new login in the browser.
+func setAuthCookies(w http.ResponseWriter, tokens application.AuthResult, secure bool) {
 	http.SetCookie(w, &http.Cookie{
-		Name:     "access_token",
-		Value:    newToken,
+		Name:     accessTokenCookie,
+		Value:    tokens.AccessToken,
 		Path:     "/",
 		HttpOnly: true,
-		Secure:   h.cookieSecure,
+		Secure:   secure,
 		SameSite: http.SameSiteLaxMode,
 		MaxAge:   3600,
 	})
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(map[string]string{"message": "token refreshed successfully"})
+	if tokens.RefreshToken == "" {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    tokens.RefreshToken,
+		Path:     refreshTokenPath,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   int(time.Until(tokens.RefreshExpiresAt).Seconds()),
+	})
+}
+
+func (h *UserHandler) clearAuthCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     accessTokenCookie,
+		Value:    "",
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   -1,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    "",
+		Path:     refreshTokenPath,
+		HttpOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: http.SameSiteStrictMode,
+		MaxAge:   -1,
+	})
 }
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"backend/internal/application"
+	"backend/internal/auth"
 	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
 	"backend/internal/middleware"
 	"bytes"
 	"context"
@@ -12,25 +14,26 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 const (
-	apiPathRegister   = "/api/register"
-	apiPathLogin      = "/api/login"
-	apiPathMe         = "/api/me"
-	apiPathRefresh    = "/api/refresh"
-	apiPathLogout     = "/api/logout"
-	authTestSecret    = "handler-test-secret"
-	testEmail         = "alice@example.com"
-	testUsername      = "alice"
-	strongPass        = "ValidStrongPassword123!"
-	statusFormat      = "status = %d, want %d"
-	accessTokenCookie = "access_token"
-	notFoundMsg       = "not found"
+	apiPathRegister = "/api/register"
+	apiPathLogin    = "/api/login"
+	apiPathMe       = "/api/me"
+	apiPathRefresh  = "/api/refresh"
+	apiPathLogout   = "/api/logout"
+	testEmail       = "alice@example.com"
+	testUsername    = "alice"
+	strongPass      = "ValidStrongPassword123!"
+	statusFormat    = "status = %d, want %d"
+	notFoundMsg     = "not found"
 )
 
+var authTestKeys = auth.NewHMACKeySet([]byte("handler-test-secret"))
+
 type fakeUserRepoHandler struct {
 	saveFn                  func(user model.User) error
 	findByUsernameOrEmailFn func(identifier string) (model.User, error)
@@ -39,8 +42,6 @@ type fakeUserRepoHandler struct {
 	savedUsers []model.User
 }
 
-func (f *fakeUserRepoHandler) Migrate() error { return nil }
-
 func (f *fakeUserRepoHandler) Save(ctx context.Context, user model.User) error {
 	f.savedUsers = append(f.savedUsers, user)
 	if f.saveFn != nil {
@@ -67,8 +68,48 @@ func (f *fakeUserRepoHandler) UpdatePassword(ctx context.Context, id uint, hashe
 	return nil
 }
 
+func (f *fakeUserRepoHandler) MarkEmailVerified(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) List(ctx context.Context, filter repository.UserFilter) ([]model.User, int, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeUserRepoHandler) UpdateRole(ctx context.Context, id uint, role string) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) SetDisabled(ctx context.Context, id uint, disabled bool) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) RequirePasswordReset(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) UpdateEmail(ctx context.Context, id uint, email string, verified bool) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) UpdateUsername(ctx context.Context, id uint, username string) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) ScheduleDeletion(ctx context.Context, id uint, at *time.Time) error {
+	return nil
+}
+
+func (f *fakeUserRepoHandler) ListDueForDeletion(ctx context.Context, t time.Time) ([]uint, error) {
+	return nil, nil
+}
+
+func (f *fakeUserRepoHandler) Delete(ctx context.Context, id uint) error {
+	return nil
+}
+
 func newHandlerWithRepo(repo *fakeUserRepoHandler) *UserHandler {
-	svc := application.NewUserService(repo, authTestSecret)
+	svc := application.NewUserService(repo, authTestKeys)
 	return NewUserHandler(svc, false)
 }
 
@@ -217,15 +258,15 @@ func TestMeHandlerSuccessThroughJWTMiddleware(t *testing.T) {
 	}
 	h := newHandlerWithRepo(repo)
 
-	tokenSvc := application.NewUserService(repo, authTestSecret)
-	_, token, err := tokenSvc.Login(context.Background(), application.LoginInput{Identifier: testUsername, Password: strongPass})
+	tokenSvc := application.NewUserService(repo, authTestKeys)
+	_, tokens, err := tokenSvc.Login(context.Background(), application.LoginInput{Identifier: testUsername, Password: strongPass})
 	if err != nil {
 		t.Fatalf("failed to generate login token: %v", err)
 	}
 
-	next := middleware.NewJWTAuthMiddleware(authTestSecret)(http.HandlerFunc(h.MeHandler))
+	next := middleware.NewJWTAuthMiddleware(authTestKeys)(http.HandlerFunc(h.MeHandler))
 	req := httptest.NewRequest(http.MethodGet, apiPathMe, nil)
-	req.AddCookie(&http.Cookie{Name: accessTokenCookie, Value: token})
+	req.AddCookie(&http.Cookie{Name: accessTokenCookie, Value: tokens.AccessToken})
 	rr := httptest.NewRecorder()
 
 	next.ServeHTTP(rr, req)
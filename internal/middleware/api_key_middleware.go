@@ -0,0 +1,15 @@
+package middleware
+
+import "net/http"
+
+// RejectAPIKeys keeps API keys away from account management routes (passwords, sessions,
+// two-factor, the keys themselves), which need a real login.
+func RejectAPIKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetAPIKeyIDFromContext(r.Context()); ok {
+			writeJSONError(w, "not available to API keys, sign in instead", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"backend/internal/auth"
+	"backend/internal/domain/model"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type fakeAPIKeyAuthenticator map[string]model.APIKeyAccess
+
+func (f fakeAPIKeyAuthenticator) AuthenticateAPIKey(ctx context.Context, key string) (model.APIKeyAccess, error) {
+	access, ok := f[key]
+	if !ok {
+		return model.APIKeyAccess{}, auth.ErrInvalidAPIKey
+	}
+	return access, nil
+}
+
+func serveAPIKey(h http.Handler, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	req.Header.Set(APIKeyHeader, key)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestJWTAuthMiddlewareAcceptsAPIKey(t *testing.T) {
+	keys := fakeAPIKeyAuthenticator{"good": {KeyID: "k1", UserID: 21, Role: "user", Permissions: []string{"commodity:read"}, RateLimit: 5}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := GetUserIDFromContext(r.Context())
+		keyID, _ := GetAPIKeyIDFromContext(r.Context())
+		perms, _ := GetPermissionsFromContext(r.Context())
+		if id != 21 || keyID != "k1" || len(perms) != 1 {
+			t.Fatalf("context = user %d key %q perms %v", id, keyID, perms)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	h := NewJWTAuthMiddleware(jwtMiddlewareKeys, WithAPIKeys(keys))(next)
+
+	if rr := serveAPIKey(h, "good"); rr.Code != http.StatusOK {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusOK)
+	}
+	if rr := serveAPIKey(h, "bad"); rr.Code != http.StatusUnauthorized {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestJWTAuthMiddlewareRateLimitsAPIKey(t *testing.T) {
+	keys := fakeAPIKeyAuthenticator{"good": {KeyID: "k1", UserID: 21, RateLimit: 2}}
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := NewJWTAuthMiddleware(jwtMiddlewareKeys, WithAPIKeys(keys))(next)
+
+	for i := 0; i < 2; i++ {
+		if rr := serveAPIKey(h, "good"); rr.Code != http.StatusOK {
+			t.Fatalf("request %d: "+statusMismatch, i, rr.Code, http.StatusOK)
+		}
+	}
+	rr := serveAPIKey(h, "good")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusTooManyRequests)
+	}
+	if rr.Header().Get("Retry-After") == "" {
+		t.Fatal("Retry-After header missing")
+	}
+}
+
+func TestRejectAPIKeysBlocksKeyRequests(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	keys := fakeAPIKeyAuthenticator{"good": {KeyID: "k1", UserID: 21, RateLimit: 5}}
+	h := NewJWTAuthMiddleware(jwtMiddlewareKeys, WithAPIKeys(keys))(RejectAPIKeys(next))
+
+	if rr := serveAPIKey(h, "good"); rr.Code != http.StatusForbidden {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusForbidden)
+	}
+}
@@ -34,8 +34,9 @@ func CSRFMiddleware(next http.Handler) http.Handler {
 			token = cookie.Value
 		}
 
-		// Safe methods do not require CSRF validation
-		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions {
+		// Safe methods do not require CSRF validation. Neither do API key requests: a browser
+		// only attaches the X-API-Key header cross-site for the CORS-allowed origins.
+		if r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions || r.Header.Get(APIKeyHeader) != "" {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -2,15 +2,74 @@ package middleware
 
 import (
 	"backend/internal/auth"
+	"backend/internal/domain/model"
 	"context"
+	"errors"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// APIKeyHeader carries an API key instead of an access token.
+const APIKeyHeader = "X-API-Key"
+
 type contextKey string
 
 const userIDCtxKey contextKey = "userID"
 const roleKey contextKey = "role"
+const sessionIDCtxKey contextKey = "sessionID"
+const permissionsKey contextKey = "permissions"
+const apiKeyIDCtxKey contextKey = "apiKeyID"
+
+// SessionChecker reports whether the session an access token belongs to is still active.
+type SessionChecker interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+// UserStatusChecker reports whether an account may still use its tokens, i.e. it exists
+// and has not been disabled.
+type UserStatusChecker interface {
+	IsUserActive(ctx context.Context, userID uint) (bool, error)
+}
+
+// APIKeyAuthenticator resolves an API key to the access it grants. It returns an error
+// wrapping auth.ErrInvalidAPIKey for keys that must be rejected.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (model.APIKeyAccess, error)
+}
+
+type jwtAuthConfig struct {
+	sessions SessionChecker
+	users    UserStatusChecker
+	apiKeys  APIKeyAuthenticator
+	limiter  *rateLimiter
+}
+
+// JWTAuthOption configures NewJWTAuthMiddleware.
+type JWTAuthOption func(*jwtAuthConfig)
+
+// WithSessionChecker rejects tokens whose session has been revoked. Tokens without a
+// session ID are accepted as before.
+func WithSessionChecker(checker SessionChecker) JWTAuthOption {
+	return func(c *jwtAuthConfig) { c.sessions = checker }
+}
+
+// WithUserStatusChecker rejects tokens of disabled or deleted accounts.
+func WithUserStatusChecker(checker UserStatusChecker) JWTAuthOption {
+	return func(c *jwtAuthConfig) { c.users = checker }
+}
+
+// WithAPIKeys also accepts API keys in the X-API-Key header, each limited to its own
+// requests per minute.
+func WithAPIKeys(authenticator APIKeyAuthenticator) JWTAuthOption {
+	return func(c *jwtAuthConfig) {
+		c.apiKeys = authenticator
+		c.limiter = newRateLimiter(time.Minute)
+	}
+}
 
 func GetUserIDFromContext(ctx context.Context) (uint, bool) {
 	id, ok := ctx.Value(userIDCtxKey).(uint)
@@ -22,10 +81,42 @@ func GetUserRoleFromContext(ctx context.Context) (string, bool) {
 	return role, ok
 }
 
-func NewJWTAuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
-	secret := []byte(jwtSecret)
+// GetPermissionsFromContext returns the permissions granted to the authenticated token.
+func GetPermissionsFromContext(ctx context.Context) ([]string, bool) {
+	perms, ok := ctx.Value(permissionsKey).([]string)
+	return perms, ok
+}
+
+// GetSessionIDFromContext returns the session of the authenticated access token, if any.
+func GetSessionIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(sessionIDCtxKey).(string)
+	return id, ok && id != ""
+}
+
+// GetAPIKeyIDFromContext returns the API key the request authenticated with, if any.
+func GetAPIKeyIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(apiKeyIDCtxKey).(string)
+	return id, ok && id != ""
+}
+
+func NewJWTAuthMiddleware(keys *auth.KeySet, opts ...JWTAuthOption) func(http.Handler) http.Handler {
+	var cfg jwtAuthConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// A request carrying an API key is authenticated by it alone, never by cookies:
+			// the CSRF check is skipped for these requests.
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				if cfg.apiKeys == nil {
+					writeJSONError(w, "API keys are not accepted here", http.StatusUnauthorized)
+					return
+				}
+				serveWithAPIKey(w, r, next, &cfg, key)
+				return
+			}
+
 			var tokenString string
 			if c, err := r.Cookie("access_token"); err == nil {
 				tokenString = c.Value
@@ -43,15 +134,69 @@ func NewJWTAuthMiddleware(jwtSecret string) func(http.Handler) http.Handler {
 				return
 			}
 
-			claims, err := auth.VerifyJWTToken(secret, tokenString)
+			claims, err := auth.VerifyJWTToken(keys, tokenString)
 			if err != nil {
 				writeJSONError(w, "invalid or expired token", http.StatusUnauthorized)
 				return
 			}
 
+			sessionID := claims.SessionID()
+			if sessionID != "" && cfg.sessions != nil {
+				active, err := cfg.sessions.IsSessionActive(r.Context(), sessionID)
+				if err != nil {
+					log.Printf("Session check failed: %v", err)
+					writeJSONError(w, "internal server error", http.StatusInternalServerError)
+					return
+				}
+				if !active {
+					writeJSONError(w, "session has been revoked", http.StatusUnauthorized)
+					return
+				}
+			}
+
+			if cfg.users != nil {
+				active, err := cfg.users.IsUserActive(r.Context(), claims.ID)
+				if err != nil {
+					log.Printf("User status check failed: %v", err)
+					writeJSONError(w, "internal server error", http.StatusInternalServerError)
+					return
+				}
+				if !active {
+					writeJSONError(w, "account is disabled", http.StatusForbidden)
+					return
+				}
+			}
+
 			ctx := context.WithValue(r.Context(), userIDCtxKey, claims.ID)
 			ctx = context.WithValue(ctx, roleKey, claims.Role)
+			ctx = context.WithValue(ctx, sessionIDCtxKey, sessionID)
+			ctx = context.WithValue(ctx, permissionsKey, claims.Permissions)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+func serveWithAPIKey(w http.ResponseWriter, r *http.Request, next http.Handler, cfg *jwtAuthConfig, key string) {
+	access, err := cfg.apiKeys.AuthenticateAPIKey(r.Context(), key)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidAPIKey) {
+			writeJSONError(w, "invalid or expired API key", http.StatusUnauthorized)
+			return
+		}
+		log.Printf("API key check failed: %v", err)
+		writeJSONError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if ok, retryAfter := cfg.limiter.allow(access.KeyID, access.RateLimit, time.Now()); !ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		writeJSONError(w, "API key rate limit exceeded", http.StatusTooManyRequests)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), userIDCtxKey, access.UserID)
+	ctx = context.WithValue(ctx, roleKey, access.Role)
+	ctx = context.WithValue(ctx, permissionsKey, access.Permissions)
+	ctx = context.WithValue(ctx, apiKeyIDCtxKey, access.KeyID)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
@@ -2,12 +2,13 @@ package middleware
 
 import (
 	"backend/internal/auth"
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 )
 
-const jwtMiddlewareSecret = "jwt-middleware-secret"
+var jwtMiddlewareKeys = auth.NewHMACKeySet([]byte("jwt-middleware-secret"))
 
 func TestJWTAuthMiddlewareMissingTokenReturnsUnauthorized(t *testing.T) {
 	nextCalled := false
@@ -19,7 +20,7 @@ func TestJWTAuthMiddlewareMissingTokenReturnsUnauthorized(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/private", nil)
 	rr := httptest.NewRecorder()
 
-	NewJWTAuthMiddleware(jwtMiddlewareSecret)(next).ServeHTTP(rr, req)
+	NewJWTAuthMiddleware(jwtMiddlewareKeys)(next).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusUnauthorized {
 		t.Fatalf(statusMismatch, rr.Code, http.StatusUnauthorized)
@@ -30,7 +31,7 @@ func TestJWTAuthMiddlewareMissingTokenReturnsUnauthorized(t *testing.T) {
 }
 
 func TestJWTAuthMiddlewareValidCookieInjectsClaimsInContext(t *testing.T) {
-	token, err := auth.GenerateJWTToken([]byte(jwtMiddlewareSecret), 11, "alice", "admin")
+	token, err := auth.GenerateJWTToken(jwtMiddlewareKeys, 11, "alice", "admin")
 	if err != nil {
 		t.Fatalf("GenerateJWTToken() error = %v", err)
 	}
@@ -51,9 +52,102 @@ func TestJWTAuthMiddlewareValidCookieInjectsClaimsInContext(t *testing.T) {
 	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
 	rr := httptest.NewRecorder()
 
-	NewJWTAuthMiddleware(jwtMiddlewareSecret)(next).ServeHTTP(rr, req)
+	NewJWTAuthMiddleware(jwtMiddlewareKeys)(next).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf(statusMismatch, rr.Code, http.StatusOK)
 	}
 }
+
+type fakeSessionChecker map[string]bool
+
+func (f fakeSessionChecker) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return f[sessionID], nil
+}
+
+func TestJWTAuthMiddlewareRejectsRevokedSession(t *testing.T) {
+	token, err := auth.GenerateSessionJWTToken(jwtMiddlewareKeys, 11, "alice", "user", nil, "revoked-session")
+	if err != nil {
+		t.Fatalf("GenerateSessionJWTToken() error = %v", err)
+	}
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	rr := httptest.NewRecorder()
+
+	checker := fakeSessionChecker{"revoked-session": false}
+	NewJWTAuthMiddleware(jwtMiddlewareKeys, WithSessionChecker(checker))(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusUnauthorized)
+	}
+	if nextCalled {
+		t.Fatal("next should not be called")
+	}
+}
+
+func TestJWTAuthMiddlewareActiveSessionInjectsSessionID(t *testing.T) {
+	token, err := auth.GenerateSessionJWTToken(jwtMiddlewareKeys, 11, "alice", "user", []string{"commodity:read"}, "live-session")
+	if err != nil {
+		t.Fatalf("GenerateSessionJWTToken() error = %v", err)
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := GetSessionIDFromContext(r.Context())
+		if !ok || id != "live-session" {
+			t.Fatalf("session id from context = %q, ok=%v", id, ok)
+		}
+		if perms, _ := GetPermissionsFromContext(r.Context()); len(perms) != 1 || perms[0] != "commodity:read" {
+			t.Fatalf("permissions from context = %v, want [commodity:read]", perms)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	rr := httptest.NewRecorder()
+
+	checker := fakeSessionChecker{"live-session": true}
+	NewJWTAuthMiddleware(jwtMiddlewareKeys, WithSessionChecker(checker))(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusOK)
+	}
+}
+
+type fakeUserStatusChecker map[uint]bool
+
+func (f fakeUserStatusChecker) IsUserActive(ctx context.Context, userID uint) (bool, error) {
+	return f[userID], nil
+}
+
+func TestJWTAuthMiddlewareRejectsDisabledUser(t *testing.T) {
+	token, err := auth.GenerateJWTToken(jwtMiddlewareKeys, 12, "mallory", "user")
+	if err != nil {
+		t.Fatalf("GenerateJWTToken() error = %v", err)
+	}
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/private", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	rr := httptest.NewRecorder()
+
+	checker := fakeUserStatusChecker{12: false}
+	NewJWTAuthMiddleware(jwtMiddlewareKeys, WithUserStatusChecker(checker))(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusForbidden {
+		t.Fatalf(statusMismatch, rr.Code, http.StatusForbidden)
+	}
+	if nextCalled {
+		t.Fatal("next should not be called")
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+)
+
+// RequirePermission only lets requests through whose access token grants perm. It must run
+// after the JWT middleware.
+func RequirePermission(perm string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			perms, _ := GetPermissionsFromContext(r.Context())
+			if !slices.Contains(perms, perm) {
+				writeJSONError(w, "Forbidden: missing permission "+perm, http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -12,7 +12,7 @@ const (
 	statusMismatch = "status = %d, want %d"
 )
 
-func TestAdminRoleMiddlewareRejectsMissingRole(t *testing.T) {
+func TestRequirePermissionRejectsMissingPermissions(t *testing.T) {
 	nextCalled := false
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		nextCalled = true
@@ -22,7 +22,7 @@ func TestAdminRoleMiddlewareRejectsMissingRole(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, adminRoute, nil)
 	rr := httptest.NewRecorder()
 
-	AdminRoleMiddleware(next).ServeHTTP(rr, req)
+	RequirePermission("users:manage")(next).ServeHTTP(rr, req)
 
 	if rr.Code != http.StatusForbidden {
 		t.Fatalf(statusMismatch, rr.Code, http.StatusForbidden)
@@ -32,7 +32,7 @@ func TestAdminRoleMiddlewareRejectsMissingRole(t *testing.T) {
 	}
 }
 
-func TestAdminRoleMiddlewareRejectsNonAdmin(t *testing.T) {
+func TestRequirePermissionRejectsOtherPermission(t *testing.T) {
 	nextCalled := false
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		nextCalled = true
@@ -40,10 +40,10 @@ func TestAdminRoleMiddlewareRejectsNonAdmin(t *testing.T) {
 	})
 
 	req := httptest.NewRequest(http.MethodGet, adminRoute, nil)
-	ctx := context.WithValue(req.Context(), roleKey, "user")
+	ctx := context.WithValue(req.Context(), permissionsKey, []string{"commodity:read"})
 	rr := httptest.NewRecorder()
 
-	AdminRoleMiddleware(next).ServeHTTP(rr, req.WithContext(ctx))
+	RequirePermission("users:manage")(next).ServeHTTP(rr, req.WithContext(ctx))
 
 	if rr.Code != http.StatusForbidden {
 		t.Fatalf(statusMismatch, rr.Code, http.StatusForbidden)
@@ -53,16 +53,16 @@ func TestAdminRoleMiddlewareRejectsNonAdmin(t *testing.T) {
 	}
 }
 
-func TestAdminRoleMiddlewareAllowsAdmin(t *testing.T) {
+func TestRequirePermissionAllowsGrantedPermission(t *testing.T) {
 	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
 
 	req := httptest.NewRequest(http.MethodGet, adminRoute, nil)
-	ctx := context.WithValue(req.Context(), roleKey, "admin")
+	ctx := context.WithValue(req.Context(), permissionsKey, []string{"commodity:read", "users:manage"})
 	rr := httptest.NewRecorder()
 
-	AdminRoleMiddleware(next).ServeHTTP(rr, req.WithContext(ctx))
+	RequirePermission("users:manage")(next).ServeHTTP(rr, req.WithContext(ctx))
 
 	if rr.Code != http.StatusOK {
 		t.Fatalf(statusMismatch, rr.Code, http.StatusOK)
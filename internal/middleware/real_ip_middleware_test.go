@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestRealIPMiddleware(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")}
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"untrusted peer spoofing X-Forwarded-For", "203.0.113.9:5000", map[string]string{"X-Forwarded-For": "1.2.3.4"}, "203.0.113.9:5000"},
+		{"untrusted peer spoofing X-Real-IP", "203.0.113.9:5000", map[string]string{"X-Real-IP": "1.2.3.4"}, "203.0.113.9:5000"},
+		{"trusted proxy with X-Real-IP", "10.0.0.2:5000", map[string]string{"X-Real-IP": "198.51.100.7"}, "198.51.100.7"},
+		{"right-most untrusted hop wins", "10.0.0.2:5000", map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.7, 10.0.0.3"}, "198.51.100.7"},
+		{"forwarded header wins over X-Real-IP", "[::1]:5000", map[string]string{"X-Forwarded-For": "198.51.100.7", "X-Real-IP": "1.2.3.4"}, "198.51.100.7"},
+		{"malformed hop keeps the peer", "10.0.0.2:5000", map[string]string{"X-Forwarded-For": "1.2.3.4, garbage"}, "10.0.0.2:5000"},
+		{"trusted proxy without headers", "10.0.0.2:5000", nil, "10.0.0.2:5000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r.RemoteAddr })
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			NewRealIPMiddleware(trusted)(next).ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Fatalf("RemoteAddr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,14 +0,0 @@
-package middleware
-
-import "net/http"
-
-func AdminRoleMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		role, ok := GetUserRoleFromContext(r.Context())
-		if !ok || role != "admin" {
-			writeJSONError(w, "Forbidden: Admins only", http.StatusForbidden)
-			return
-		}
-		next.ServeHTTP(w, r)
-	})
-}